@@ -0,0 +1,231 @@
+package main
+
+import (
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/bitcoin-ai-platform/pkg/evm"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/siwe"
+)
+
+// testOwnerKey is the third account of the default anvil devnet, signed in
+// to create assets
+const testOwnerKey = "0x5de4111afa1a4b94908f83103eb1f1706367c2e68ca870fc3fb9a804cdab365a"
+
+type nonceResponse struct {
+	Nonce   string `json:"nonce"`
+	Domain  string `json:"domain"`
+	ChainID int64  `json:"chainId"`
+}
+
+type sessionResponse struct {
+	Token     string    `json:"token"`
+	Address   string    `json:"address"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// parseKey parses a test private key
+func parseKey(t *testing.T, hexKey string) *evm.PrivateKey {
+	t.Helper()
+	key, err := evm.ParsePrivateKey(hexKey)
+	if err != nil {
+		t.Fatalf("ParsePrivateKey: %v", err)
+	}
+	return key
+}
+
+// siweMessage returns a sign-in message for key with a fresh nonce
+func siweMessage(t *testing.T, r http.Handler, key *evm.PrivateKey) *siwe.Message {
+	t.Helper()
+	var challenge nonceResponse
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/auth/nonce", nil, &challenge); w.Code != http.StatusOK {
+		t.Fatalf("nonce = %d: %s", w.Code, w.Body.String())
+	}
+	return &siwe.Message{
+		Scheme:    "http",
+		Domain:    challenge.Domain,
+		Address:   key.Address(),
+		Statement: "Sign in to FansMint",
+		URI:       "http://" + challenge.Domain,
+		Version:   siwe.Version,
+		ChainID:   challenge.ChainID,
+		Nonce:     challenge.Nonce,
+		IssuedAt:  time.Now().Truncate(time.Second),
+	}
+}
+
+// signMessage returns the personal_sign signature of text by key
+func signMessage(key *evm.PrivateKey, text string) string {
+	sig := key.Sign(evm.TextHash([]byte(text)))
+	sig[64] += 27 // as wallets report it
+	return "0x" + hex.EncodeToString(sig)
+}
+
+// signIn signs key in and returns the session token
+func signIn(t *testing.T, r http.Handler, hexKey string) string {
+	t.Helper()
+	key := parseKey(t, hexKey)
+	text := siweMessage(t, r, key).String()
+	var session sessionResponse
+	w := doRequest(t, r, http.MethodPost, "/api/v1/auth/verify", map[string]string{"message": text, "signature": signMessage(key, text)}, &session)
+	if w.Code != http.StatusOK || session.Token == "" {
+		t.Fatalf("sign in = %d: %s", w.Code, w.Body.String())
+	}
+	return session.Token
+}
+
+func TestSignIn(t *testing.T) {
+	r := newTestRouter(t, testConfig(t))
+	key := parseKey(t, testOwnerKey)
+	verify := func(text, signature string, out interface{}) int {
+		t.Helper()
+		return doRequest(t, r, http.MethodPost, "/api/v1/auth/verify", map[string]string{"message": text, "signature": signature}, out).Code
+	}
+
+	msg := siweMessage(t, r, key)
+	if msg.Domain != "localhost:3000" || msg.ChainID != 839999 || len(msg.Nonce) < siwe.MinNonceLength {
+		t.Fatalf("challenge = %+v", msg)
+	}
+	text := msg.String()
+
+	var session sessionResponse
+	if code := verify(text, signMessage(key, text), &session); code != http.StatusOK {
+		t.Fatalf("verify = %d, want 200", code)
+	}
+	if session.Address != key.Address().Hex() || !session.ExpiresAt.After(time.Now().Add(23*time.Hour)) {
+		t.Errorf("session = %+v, want the signer for a day", session)
+	}
+
+	var me sessionResponse
+	if w := doRequestAs(t, r, session.Token, http.MethodGet, "/api/v1/auth/session", nil, &me); w.Code != http.StatusOK || me.Address != key.Address().Hex() {
+		t.Errorf("session = %d %+v, want the signer", w.Code, me)
+	}
+
+	// A nonce is single use
+	var failed errorResponse
+	if code := verify(text, signMessage(key, text), &failed); code != http.StatusUnauthorized || failed.Error.Code != "sign_in_failed" {
+		t.Errorf("replayed message = %d %+v, want 401 sign_in_failed", code, failed.Error)
+	}
+	replay := *msg
+	replay.Nonce = strings.ToUpper(msg.Nonce)
+	if text := replay.String(); verify(text, signMessage(key, text), &failed) != http.StatusUnauthorized {
+		t.Error("replayed message with an upper case nonce was accepted")
+	}
+
+	// Nonces are signed by the server that issued them
+	forged := siweMessage(t, r, key)
+	flipped := "0"
+	if strings.HasSuffix(forged.Nonce, flipped) {
+		flipped = "1"
+	}
+	forged.Nonce = forged.Nonce[:len(forged.Nonce)-1] + flipped
+	if text := forged.String(); verify(text, signMessage(key, text), &failed) != http.StatusUnauthorized {
+		t.Error("message with a forged nonce was accepted")
+	}
+	forged.Nonce = "deadbeefdeadbeef"
+	if text := forged.String(); verify(text, signMessage(key, text), &failed) != http.StatusUnauthorized {
+		t.Error("message with a made up nonce was accepted")
+	}
+
+	// Signed by another wallet than the one in the message
+	text = siweMessage(t, r, key).String()
+	if code := verify(text, signMessage(parseKey(t, testOtherKey), text), &failed); code != http.StatusUnauthorized {
+		t.Errorf("message signed by another wallet = %d, want 401", code)
+	}
+
+	// Issued for another site or chain
+	for _, tamper := range []func(*siwe.Message){
+		func(m *siwe.Message) { m.Domain = "evil.example" },
+		func(m *siwe.Message) { m.ChainID = 1 },
+		func(m *siwe.Message) { expired := time.Now().Add(-time.Minute); m.ExpirationTime = &expired },
+	} {
+		msg := siweMessage(t, r, key)
+		tamper(msg)
+		text := msg.String()
+		if code := verify(text, signMessage(key, text), &failed); code != http.StatusUnauthorized {
+			t.Errorf("verify %q = %d, want 401", text, code)
+		}
+	}
+
+	// Not an EIP-4361 message at all
+	if code := verify("hello", signMessage(key, "hello"), &failed); code != http.StatusBadRequest || failed.Error.Details[0].Field != "message" {
+		t.Errorf("verify of a plain text = %d %+v, want 400 on message", code, failed.Error)
+	}
+	lower := strings.Replace(siweMessage(t, r, key).String(), key.Address().Hex(), strings.ToLower(key.Address().Hex()), 1)
+	if code := verify(lower, signMessage(key, lower), &failed); code != http.StatusBadRequest {
+		t.Errorf("verify with an unchecksummed address = %d, want 400", code)
+	}
+
+	// Protected routes need a valid token
+	for _, token := range []string{"", "garbage", session.Token[:len(session.Token)-2] + "AA"} {
+		if w := doRequestAs(t, r, token, http.MethodGet, "/api/v1/auth/session", nil, &failed); w.Code != http.StatusUnauthorized || failed.Error.Code != "unauthorized" {
+			t.Errorf("session with token %q = %d %+v, want 401 unauthorized", token, w.Code, failed.Error)
+		}
+	}
+	if w := doRequest(t, r, http.MethodPost, "/api/v1/assets/create", map[string]string{"name": "Anon", "symbol": "ANON", "totalSupply": "1"}, &failed); w.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous create = %d, want 401", w.Code)
+	}
+
+	// Sessions from another secret are rejected
+	cfg := testConfig(t)
+	cfg.Auth.SessionSecret = strings.Repeat("s", 32)
+	other := newTestRouter(t, cfg)
+	if w := doRequestAs(t, other, session.Token, http.MethodGet, "/api/v1/auth/session", nil, nil); w.Code != http.StatusUnauthorized {
+		t.Errorf("session on another server = %d, want 401", w.Code)
+	}
+	text = siweMessage(t, other, key).String()
+	if code := verify(text, signMessage(key, text), &failed); code != http.StatusUnauthorized {
+		t.Errorf("nonce from another server = %d, want 401", code)
+	}
+}
+
+func TestCreateAssetOwner(t *testing.T) {
+	r := newTestRouter(t, testConfig(t))
+	token := signIn(t, r, testOwnerKey)
+	owner := parseKey(t, testOwnerKey).Address().Hex()
+
+	// The signed-in wallet owns the asset, whatever the body says
+	var created assetResponse
+	w := doRequestAs(t, r, token, http.MethodPost, "/api/v1/assets/create", map[string]string{
+		"name": "Owned", "symbol": "OWN", "totalSupply": "10",
+	}, &created)
+	if w.Code != http.StatusCreated || created.Asset.CreatorAddress != owner {
+		t.Errorf("create = %d with creator %s, want 201 by %s", w.Code, created.Asset.CreatorAddress, owner)
+	}
+
+	var invalid errorResponse
+	w = doRequestAs(t, r, token, http.MethodPost, "/api/v1/assets/create", map[string]string{
+		"name": "Stolen", "symbol": "STOLE", "totalSupply": "10", "ownerAddress": testWallet,
+	}, &invalid)
+	if w.Code != http.StatusBadRequest || invalid.Error.Details[0].Field != "ownerAddress" {
+		t.Errorf("create for another wallet = %d %+v, want 400 on ownerAddress", w.Code, invalid.Error)
+	}
+
+	// A Bitcoin address of the configured network is recorded next to the wallet
+	created = assetResponse{}
+	w = doRequestAs(t, r, token, http.MethodPost, "/api/v1/assets/create", map[string]string{
+		"name": "Bridged", "symbol": "BRDG", "totalSupply": "10",
+		"ownerAddress": "TB1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KXPJZSX",
+	}, &created)
+	if w.Code != http.StatusCreated || created.Asset.CreatorAddress != owner ||
+		created.Asset.OwnerBitcoinAddress != "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx" {
+		t.Errorf("create with Bitcoin owner = %d %+v, want 201 with the normalized address", w.Code, created.Asset)
+	}
+
+	for _, tc := range []struct{ owner, want string }{
+		{"bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", "is a Bitcoin mainnet address, expected testnet"},
+		{"tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsy", "has an invalid bech32 checksum"},
+		{owner[:2] + strings.ToLower(owner[2:4]) + owner[4:], "has an invalid EIP-55 checksum, expected " + owner},
+	} {
+		invalid = errorResponse{}
+		w = doRequestAs(t, r, token, http.MethodPost, "/api/v1/assets/create", map[string]string{
+			"name": "Invalid", "symbol": "INV", "totalSupply": "10", "ownerAddress": tc.owner,
+		}, &invalid)
+		if w.Code != http.StatusBadRequest || len(invalid.Error.Details) != 1 || invalid.Error.Details[0].Message != tc.want {
+			t.Errorf("create with owner %s = %d %+v, want 400 %q", tc.owner, w.Code, invalid.Error, tc.want)
+		}
+	}
+}
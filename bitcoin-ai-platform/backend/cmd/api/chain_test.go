@@ -0,0 +1,788 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/bitcoin-ai-platform/internal/repository"
+	"github.com/yourusername/bitcoin-ai-platform/internal/services"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/evm"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/evm/evmtest"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
+)
+
+const (
+	testXSAT   = "0x8266f2fbc720012e5Ac038aD3dbb29d2d613c459"
+	testWallet = "0x742d35Cc6634C0532925a3b844Bc454e4438f44e"
+	// seededBTU is the contract of the BTU mock asset "2"
+	seededBTU = "0x5aeda56215b167893e80b4fe645ba6d5bab767de"
+)
+
+// newTestChain starts a stub node holding XSAT and the seeded BTU token,
+// with 1.5 XSAT and 42 BTU base units minted to testWallet
+func newTestChain(t *testing.T) *evmtest.Server {
+	t.Helper()
+
+	node := evmtest.NewServer(t)
+	xsat, _ := evm.ParseAddress(testXSAT)
+	btu, _ := evm.ParseAddress(seededBTU)
+	wallet, _ := evm.ParseAddress(testWallet)
+	node.AddToken(xsat, "exSat Token", "XSAT", 18)
+	node.AddToken(btu, "BitcoinUtility", "BTU", 0)
+	node.Mint(xsat, wallet, big.NewInt(1_500_000_000_000_000_000))
+	node.Mint(btu, wallet, big.NewInt(42))
+	return node
+}
+
+func TestChainTokenRoute(t *testing.T) {
+	node := newTestChain(t)
+	cfg := testConfig(t)
+	cfg.Chain.RPCURL = node.URL
+	r := newTestRouter(t, cfg)
+
+	var resp struct {
+		Token services.TokenView `json:"token"`
+	}
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/chain/tokens/"+seededBTU, nil, &resp); w.Code != http.StatusOK {
+		t.Fatalf("get token = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	if resp.Token.Symbol != "BTU" || resp.Token.TotalSupply != "42" || resp.Token.AssetID != "2" || resp.Token.BlockNumber != 3 {
+		t.Errorf("token = %+v, want BTU with supply 42 issued as asset 2 at block 3", resp.Token)
+	}
+	// Addresses are returned in their EIP-55 form
+	if resp.Token.Address != "0x5AEDA56215b167893e80B4fE645BA6d5Bab767DE" {
+		t.Errorf("address = %s, want the checksum form", resp.Token.Address)
+	}
+
+	var missing errorResponse
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/chain/tokens/"+testWallet, nil, &missing); w.Code != http.StatusNotFound || missing.Error.Code != "token_not_found" {
+		t.Errorf("get token of a wallet = %d %+v, want 404 token_not_found", w.Code, missing.Error)
+	}
+
+	var invalid errorResponse
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/chain/tokens/0x1234", nil, &invalid); w.Code != http.StatusBadRequest || invalid.Error.Code != "validation_failed" {
+		t.Errorf("get token with bad address = %d %+v, want 400 validation_failed", w.Code, invalid.Error)
+	}
+
+	node.Close()
+	var down errorResponse
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/chain/tokens/"+seededBTU, nil, &down); w.Code != http.StatusBadGateway || down.Error.Code != "chain_unavailable" {
+		t.Errorf("get token with node down = %d %+v, want 502 chain_unavailable", w.Code, down.Error)
+	}
+}
+
+func TestChainBalancesRoute(t *testing.T) {
+	node := newTestChain(t)
+	cfg := testConfig(t)
+	cfg.Chain.RPCURL = node.URL
+	cfg.Chain.Tokens = []string{testXSAT}
+	r, app := newTestApp(t, cfg)
+
+	type balancesResponse struct {
+		BlockNumber uint64                  `json:"blockNumber"`
+		Balances    []services.TokenBalance `json:"balances"`
+	}
+
+	var resp balancesResponse
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/chain/balances/"+testWallet, nil, &resp); w.Code != http.StatusOK {
+		t.Fatalf("get balances = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	// XSAT is configured; BTU, the token of the seeded asset 2, is not
+	// indexed yet
+	if len(resp.Balances) != 1 {
+		t.Fatalf("balances = %+v, want XSAT alone", resp.Balances)
+	}
+	if xsat := resp.Balances[0]; xsat.Symbol != "XSAT" || xsat.BalanceFormatted != "1.5" || xsat.AssetID != "" || xsat.IndexedBlock != 0 {
+		t.Errorf("XSAT balance = %+v, want 1.5", xsat)
+	}
+
+	// A second read at the same block is served from the cache
+	calls := node.Calls("eth_call")
+	doRequest(t, r, http.MethodGet, "/api/v1/chain/balances/"+testWallet, nil, &resp)
+	if node.Calls("eth_call") != calls {
+		t.Errorf("repeated read made %d eth_calls, want 0", node.Calls("eth_call")-calls)
+	}
+
+	// A new block invalidates it
+	node.MineBlocks(1)
+	doRequest(t, r, http.MethodGet, "/api/v1/chain/balances/"+testWallet, nil, &resp)
+	if node.Calls("eth_call") == calls || resp.BlockNumber != 4 {
+		t.Errorf("read after a new block at %d made no eth_call, want fresh reads at block 4", resp.BlockNumber)
+	}
+
+	// FansMint tokens come from the indexed balances, without a read of
+	// their own
+	node.MineBlocks(2)
+	if err := app.indexer.SyncOnce(context.Background()); err != nil {
+		t.Fatalf("SyncOnce: %v", err)
+	}
+	calls = node.Calls("eth_call")
+	doRequest(t, r, http.MethodGet, "/api/v1/chain/balances/"+testWallet, nil, &resp)
+	if len(resp.Balances) != 2 {
+		t.Fatalf("balances after indexing = %+v, want XSAT and BTU", resp.Balances)
+	}
+	if btu := resp.Balances[1]; btu.Symbol != "BTU" || btu.Balance != "42" || btu.AssetID != "2" || btu.IndexedBlock != 3 {
+		t.Errorf("BTU balance = %+v, want 42 of asset 2 indexed up to block 3", btu)
+	}
+	if reads := node.Calls("eth_call") - calls; reads > 5 {
+		t.Errorf("balances made %d eth_calls, want at most the 5 reading XSAT", reads)
+	}
+	other := "0x0000000000000000000000000000000000000B0B"
+	doRequest(t, r, http.MethodGet, "/api/v1/chain/balances/"+other, nil, &resp)
+	if len(resp.Balances) != 1 || resp.Balances[0].Symbol != "XSAT" {
+		t.Errorf("balances of a wallet without BTU = %+v, want XSAT alone", resp.Balances)
+	}
+}
+
+func TestAssetHolders(t *testing.T) {
+	node := newTestChain(t)
+	cfg := testConfig(t)
+	cfg.Chain.RPCURL = node.URL
+	cfg.Chain.LogBlockRange = 2
+	r, app := newTestApp(t, cfg)
+	ctx := context.Background()
+
+	// testWallet created BTU, so its balance is not circulating
+	btuAsset, err := app.assets.Get("2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	btuAsset.CreatorAddress = testWallet
+	app.assets.Save(*btuAsset)
+
+	btu, _ := evm.ParseAddress(seededBTU)
+	wallet, _ := evm.ParseAddress(testWallet)
+	alice, _ := evm.ParseAddress("0x00000000000000000000000000000000000A11CE")
+	bob, _ := evm.ParseAddress("0x0000000000000000000000000000000000000B0B")
+	node.Transfer(btu, wallet, alice, big.NewInt(10)) // block 4
+	node.Transfer(btu, wallet, bob, big.NewInt(12))   // block 5
+	node.Transfer(btu, alice, bob, big.NewInt(10))    // block 6, alice holds nothing
+	node.MineBlocks(3)
+
+	type holdersResponse struct {
+		IndexedBlock uint64                `json:"indexedBlock"`
+		HolderCount  int                   `json:"holderCount"`
+		Holders      []services.HolderView `json:"holders"`
+		NextCursor   string                `json:"nextCursor"`
+	}
+
+	var before holdersResponse
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/assets/2/holders", nil, &before); w.Code != http.StatusOK || before.IndexedBlock != 0 || len(before.Holders) != 0 {
+		t.Fatalf("holders before indexing = %d %+v, want none", w.Code, before)
+	}
+
+	if err := app.indexer.SyncOnce(ctx); err != nil {
+		t.Fatalf("SyncOnce: %v", err)
+	}
+
+	var first holdersResponse
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/assets/2/holders?limit=1", nil, &first); w.Code != http.StatusOK {
+		t.Fatalf("holders = %d: %s", w.Code, w.Body.String())
+	}
+	// Head is 9, three confirmations leave block 6 as the last indexed
+	if first.IndexedBlock != 6 || first.HolderCount != 2 || first.NextCursor == "" {
+		t.Fatalf("first page = %+v, want 2 holders indexed up to block 6", first)
+	}
+	if h := first.Holders[0]; h.Address != bob.Hex() || h.Balance != "22" || h.Share != 0.5238095238095238 {
+		t.Errorf("largest holder = %+v, want bob with 22", h)
+	}
+
+	var second holdersResponse
+	doRequest(t, r, http.MethodGet, "/api/v1/assets/2/holders?limit=1&cursor="+first.NextCursor, nil, &second)
+	if len(second.Holders) != 1 || second.Holders[0].Address != wallet.Hex() || second.Holders[0].Balance != "20" || second.NextCursor != "" {
+		t.Errorf("second page = %+v, want the creator with 20 and no cursor", second)
+	}
+
+	var asset assetResponse
+	doRequest(t, r, http.MethodGet, "/api/v1/assets/2", nil, &asset)
+	if asset.Asset.Holders != 2 || asset.Asset.CirculatingSupply != "22" {
+		t.Errorf("asset holders = %d, circulating = %s, want 2 and 22", asset.Asset.Holders, asset.Asset.CirculatingSupply)
+	}
+	// The deployed token, not the exSat listing, has the total supply
+	if asset.Asset.TotalSupply != "42" || asset.Asset.Decimals != 0 || asset.Asset.TotalSupplyFormatted != "42" {
+		t.Errorf("asset total supply = %s (%s) with %d decimals, want the 42 minted", asset.Asset.TotalSupply, asset.Asset.TotalSupplyFormatted, asset.Asset.Decimals)
+	}
+
+	// Transfers are only applied once confirmed
+	node.Transfer(btu, bob, alice, big.NewInt(2)) // block 10
+	app.indexer.SyncOnce(ctx)
+	doRequest(t, r, http.MethodGet, "/api/v1/assets/2", nil, &asset)
+	if asset.Asset.Holders != 2 {
+		t.Errorf("holders after an unconfirmed transfer = %d, want 2", asset.Asset.Holders)
+	}
+	node.MineBlocks(3)
+	app.indexer.SyncOnce(ctx)
+	doRequest(t, r, http.MethodGet, "/api/v1/assets/2", nil, &asset)
+	if asset.Asset.Holders != 3 || asset.Asset.CirculatingSupply != "22" {
+		t.Errorf("after confirmation holders = %d, circulating = %s, want 3 and 22", asset.Asset.Holders, asset.Asset.CirculatingSupply)
+	}
+
+	// A mint or burn changes the total supply
+	node.Mint(btu, wallet, big.NewInt(8))                 // block 11
+	node.Transfer(btu, bob, evm.Address{}, big.NewInt(5)) // block 12
+	node.MineBlocks(3)
+	app.indexer.SyncOnce(ctx)
+	doRequest(t, r, http.MethodGet, "/api/v1/assets/2", nil, &asset)
+	if asset.Asset.TotalSupply != "45" || asset.Asset.CirculatingSupply != "17" {
+		t.Errorf("after a mint and a burn total supply = %s, circulating = %s, want 45 and 17", asset.Asset.TotalSupply, asset.Asset.CirculatingSupply)
+	}
+
+	var missing errorResponse
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/assets/missing/holders", nil, &missing); w.Code != http.StatusNotFound || missing.Error.Code != "asset_not_found" {
+		t.Errorf("holders of a missing asset = %d %+v, want 404 asset_not_found", w.Code, missing.Error)
+	}
+	var invalid errorResponse
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/assets/2/holders?cursor=bogus", nil, &invalid); w.Code != http.StatusBadRequest || invalid.Error.Code != "validation_failed" {
+		t.Errorf("holders with a bad cursor = %d %+v, want 400 validation_failed", w.Code, invalid.Error)
+	}
+}
+
+func TestAssetTransactions(t *testing.T) {
+	node := newTestChain(t)
+	cfg := testConfig(t)
+	cfg.Chain.RPCURL = node.URL
+	cfg.Chain.ExplorerURL = "https://scan.example/"
+	r, app := newTestApp(t, cfg)
+
+	btu, _ := evm.ParseAddress(seededBTU)
+	wallet, _ := evm.ParseAddress(testWallet)
+	alice, _ := evm.ParseAddress("0x00000000000000000000000000000000000A11CE")
+	bob, _ := evm.ParseAddress("0x0000000000000000000000000000000000000B0B")
+	node.Transfer(btu, wallet, alice, big.NewInt(10)) // block 4
+	node.Transfer(btu, wallet, bob, big.NewInt(12))   // block 5
+	node.Transfer(btu, alice, bob, big.NewInt(3))     // block 6
+	node.MineBlocks(3)
+	if err := app.indexer.SyncOnce(context.Background()); err != nil {
+		t.Fatalf("SyncOnce: %v", err)
+	}
+
+	type transactionsResponse struct {
+		IndexedBlock uint64                  `json:"indexedBlock"`
+		Transactions []services.TransferView `json:"transactions"`
+		NextCursor   string                  `json:"nextCursor"`
+	}
+	list := func(query string) transactionsResponse {
+		t.Helper()
+		var resp transactionsResponse
+		if w := doRequest(t, r, http.MethodGet, "/api/v1/assets/2/transactions"+query, nil, &resp); w.Code != http.StatusOK {
+			t.Fatalf("transactions%s = %d: %s", query, w.Code, w.Body.String())
+		}
+		return resp
+	}
+	blocks := func(resp transactionsResponse) []uint64 {
+		var blocks []uint64
+		for _, tx := range resp.Transactions {
+			blocks = append(blocks, tx.BlockNumber)
+		}
+		return blocks
+	}
+
+	all := list("")
+	if all.IndexedBlock != 6 || len(all.Transactions) != 4 || all.NextCursor != "" {
+		t.Fatalf("transactions = %+v, want the mint and 3 transfers indexed up to block 6", all)
+	}
+	latest := all.Transactions[0]
+	if latest.BlockNumber != 6 || latest.From != alice.Hex() || latest.To != bob.Hex() || latest.Amount != "3" || latest.Direction != "" {
+		t.Errorf("latest transaction = %+v, want 3 from alice to bob in block 6", latest)
+	}
+	if want := evmtest.BlockTime(6).UTC().Format(time.RFC3339); latest.Timestamp != want {
+		t.Errorf("timestamp = %q, want %q", latest.Timestamp, want)
+	}
+	if !strings.HasPrefix(latest.TxHash, "0x") || latest.ExplorerURL != "https://scan.example/tx/"+latest.TxHash {
+		t.Errorf("tx hash %q links to %q, want the explorer transaction page", latest.TxHash, latest.ExplorerURL)
+	}
+	if mint := all.Transactions[3]; mint.BlockNumber != 3 || mint.From != repository.ZeroAddress || mint.Amount != "42" {
+		t.Errorf("oldest transaction = %+v, want the mint of 42", mint)
+	}
+
+	aliceAll := list("?address=" + strings.ToLower(alice.Hex()))
+	if got := blocks(aliceAll); len(got) != 2 || got[0] != 6 || got[1] != 4 {
+		t.Errorf("alice transactions in blocks %v, want [6 4]", got)
+	} else if aliceAll.Transactions[0].Direction != "out" || aliceAll.Transactions[1].Direction != "in" {
+		t.Errorf("alice directions = %s, %s, want out, in", aliceAll.Transactions[0].Direction, aliceAll.Transactions[1].Direction)
+	}
+	if got := blocks(list("?address=" + wallet.Hex() + "&direction=out")); len(got) != 2 || got[0] != 5 || got[1] != 4 {
+		t.Errorf("outgoing wallet transactions in blocks %v, want [5 4]", got)
+	}
+	if got := blocks(list("?address=" + bob.Hex() + "&direction=in&sinceBlock=5")); len(got) != 1 || got[0] != 6 {
+		t.Errorf("incoming bob transactions since block 5 in blocks %v, want [6]", got)
+	}
+
+	// Polling from the indexed block only returns what is new
+	if got := list("?sinceBlock=6"); len(got.Transactions) != 0 {
+		t.Errorf("transactions since the indexed block = %v, want none", blocks(got))
+	}
+	node.Transfer(btu, bob, wallet, big.NewInt(1)) // block 10
+	node.MineBlocks(3)
+	app.indexer.SyncOnce(context.Background())
+	if got := list("?sinceBlock=6"); got.IndexedBlock != 10 || len(got.Transactions) != 1 || got.Transactions[0].BlockNumber != 10 {
+		t.Errorf("transactions since block 6 = %+v, want the one in block 10", got)
+	}
+
+	first := list("?limit=2")
+	second := list("?limit=2&cursor=" + first.NextCursor)
+	third := list("?limit=2&cursor=" + second.NextCursor)
+	if got := append(append(blocks(first), blocks(second)...), blocks(third)...); len(got) != 5 || got[0] != 10 || got[4] != 3 {
+		t.Errorf("paged blocks = %v, want 10 down to 3", got)
+	}
+	if first.NextCursor == "" || third.NextCursor != "" {
+		t.Errorf("cursors = %q, %q, want one until the last page", first.NextCursor, third.NextCursor)
+	}
+
+	for _, query := range []string{
+		"?direction=in",
+		"?address=" + alice.Hex() + "&direction=sideways",
+		"?address=alice",
+		"?sinceBlock=-1",
+		"?limit=0",
+		"?cursor=bogus",
+	} {
+		var invalid errorResponse
+		if w := doRequest(t, r, http.MethodGet, "/api/v1/assets/2/transactions"+query, nil, &invalid); w.Code != http.StatusBadRequest || invalid.Error.Code != "validation_failed" {
+			t.Errorf("transactions%s = %d %+v, want 400 validation_failed", query, w.Code, invalid.Error)
+		}
+	}
+	var missing errorResponse
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/assets/missing/transactions", nil, &missing); w.Code != http.StatusNotFound || missing.Error.Code != "asset_not_found" {
+		t.Errorf("transactions of a missing asset = %d %+v, want 404 asset_not_found", w.Code, missing.Error)
+	}
+}
+
+func TestWalletPortfolio(t *testing.T) {
+	node := newTestChain(t)
+	cfg := testConfig(t)
+	cfg.Chain.RPCURL = node.URL
+	r, app := newTestApp(t, cfg)
+
+	// testWallet created BTU and is named Bitcoin owner of an undeployed asset
+	const bitcoinOwner = "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"
+	btuAsset, _ := app.assets.Get("2")
+	btuAsset.CreatorAddress = testWallet
+	app.assets.Save(*btuAsset)
+	app.assets.Save(client.Asset{
+		ID:                  "3",
+		Name:                "Satoshi Fans",
+		Symbol:              "SFAN",
+		TotalSupply:         "1000",
+		CreatorAddress:      "0x0000000000000000000000000000000000000B0B",
+		OwnerBitcoinAddress: bitcoinOwner,
+		CreatedAt:           "2023-06-01T00:00:00Z",
+		Status:              "pending",
+	})
+
+	btu, _ := evm.ParseAddress(seededBTU)
+	wallet, _ := evm.ParseAddress(testWallet)
+	alice, _ := evm.ParseAddress("0x00000000000000000000000000000000000A11CE")
+	node.Transfer(btu, wallet, alice, big.NewInt(10)) // block 4
+	node.Transfer(btu, alice, wallet, big.NewInt(4))  // block 5
+	node.MineBlocks(3)
+	if err := app.indexer.SyncOnce(context.Background()); err != nil {
+		t.Fatalf("SyncOnce: %v", err)
+	}
+
+	type portfolioResponse struct {
+		Address       string             `json:"address"`
+		Kind          string             `json:"kind"`
+		Holdings      []services.Holding `json:"holdings"`
+		Created       []client.Asset     `json:"created"`
+		TransferCount int                `json:"transferCount"`
+		FirstActivity *services.Activity `json:"firstActivity"`
+		LastActivity  *services.Activity `json:"lastActivity"`
+	}
+	portfolio := func(address string) portfolioResponse {
+		t.Helper()
+		var resp portfolioResponse
+		if w := doRequest(t, r, http.MethodGet, "/api/v1/wallets/"+address+"/portfolio", nil, &resp); w.Code != http.StatusOK {
+			t.Fatalf("portfolio of %s = %d: %s", address, w.Code, w.Body.String())
+		}
+		return resp
+	}
+
+	mine := portfolio(strings.ToLower(testWallet))
+	if mine.Address != wallet.Hex() || mine.Kind != "evm" {
+		t.Errorf("address = %s (%s), want %s (evm)", mine.Address, mine.Kind, wallet.Hex())
+	}
+	if len(mine.Holdings) != 1 || mine.Holdings[0].AssetID != "2" || mine.Holdings[0].Balance != "36" || mine.Holdings[0].IndexedBlock != 5 {
+		t.Errorf("holdings = %+v, want 36 BTU indexed up to block 5", mine.Holdings)
+	}
+	if len(mine.Created) != 1 || mine.Created[0].ID != "2" {
+		t.Errorf("created = %+v, want BTU", mine.Created)
+	}
+	// The mint, the transfer to alice and the one back
+	if mine.TransferCount != 3 || mine.FirstActivity == nil || mine.LastActivity == nil {
+		t.Fatalf("activity = %d from %+v to %+v, want 3 transfers", mine.TransferCount, mine.FirstActivity, mine.LastActivity)
+	}
+	if first := mine.FirstActivity; first.AssetID != "2" || first.BlockNumber != 3 || first.From != repository.ZeroAddress {
+		t.Errorf("first activity = %+v, want the BTU mint in block 3", first)
+	}
+	if last := mine.LastActivity; last.BlockNumber != 5 || last.From != alice.Hex() || last.Timestamp != evmtest.BlockTime(5).UTC().Format(time.RFC3339) {
+		t.Errorf("last activity = %+v, want alice's transfer in block 5", last)
+	}
+
+	// A recipient holds what it kept of the transfer
+	emptied := portfolio(alice.Hex())
+	if len(emptied.Holdings) != 1 || emptied.Holdings[0].Balance != "6" || emptied.TransferCount != 2 {
+		t.Errorf("alice portfolio = %+v, want 6 BTU over 2 transfers", emptied)
+	}
+	stranger := portfolio("0x000000000000000000000000000000000000dEaD")
+	if len(stranger.Holdings) != 0 || len(stranger.Created) != 0 || stranger.FirstActivity != nil || stranger.LastActivity != nil {
+		t.Errorf("stranger portfolio = %+v, want an empty one", stranger)
+	}
+
+	bitcoin := portfolio(bitcoinOwner)
+	if bitcoin.Kind != "p2wpkh" || len(bitcoin.Created) != 1 || bitcoin.Created[0].ID != "3" || len(bitcoin.Holdings) != 0 {
+		t.Errorf("bitcoin owner portfolio = %+v, want asset 3 and no holdings", bitcoin)
+	}
+
+	for _, address := range []string{"bogus", "0x742d35cc6634C0532925a3b844Bc454e4438f44e", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"} {
+		var invalid errorResponse
+		if w := doRequest(t, r, http.MethodGet, "/api/v1/wallets/"+address+"/portfolio", nil, &invalid); w.Code != http.StatusBadRequest || invalid.Error.Code != "validation_failed" {
+			t.Errorf("portfolio of %s = %d %+v, want 400 validation_failed", address, w.Code, invalid.Error)
+		}
+	}
+}
+
+// testDeployerKey is the first account of the default anvil devnet
+const testDeployerKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+func TestTokenDeployment(t *testing.T) {
+	node := evmtest.NewServer(t)
+	factory, _ := evm.ParseAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	node.AddFactory(factory)
+
+	cfg := testConfig(t)
+	cfg.Chain.RPCURL = node.URL
+	cfg.Chain.Factory = factory.Hex()
+	cfg.Chain.DeployerKey = testDeployerKey
+	cfg.Chain.MetadataBaseURL = "https://fansmint.example/api/v1/assets"
+	r, app := newTestApp(t, cfg)
+	ctx := context.Background()
+	session := signIn(t, r, testOwnerKey)
+	owner := parseKey(t, testOwnerKey).Address().Hex()
+
+	create := func(symbol string) client.Asset {
+		t.Helper()
+		var created assetResponse
+		w := doRequestAs(t, r, session, http.MethodPost, "/api/v1/assets/create", map[string]string{
+			"name":        symbol + " Fans",
+			"symbol":      symbol,
+			"totalSupply": "1,000",
+		}, &created)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create %s = %d: %s", symbol, w.Code, w.Body.String())
+		}
+		return created.Asset
+	}
+	getAsset := func(id string) client.Asset {
+		t.Helper()
+		var resp assetResponse
+		doRequest(t, r, http.MethodGet, "/api/v1/assets/"+id, nil, &resp)
+		return resp.Asset
+	}
+
+	asset := create("FAN")
+	if d := asset.Deployment; d == nil || d.Status != client.DeploymentPending || d.TxHash == "" || asset.ContractAddress != "" {
+		t.Fatalf("created asset = %+v, want a pending deployment", asset)
+	}
+	want := new(big.Int).Mul(big.NewInt(1000), new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
+	if asset.TotalSupply != want.String() || asset.Decimals != 18 || asset.TotalSupplyFormatted != "1000" {
+		t.Errorf("created supply = %s with %d decimals (%s), want 1000 tokens of 18 decimals", asset.TotalSupply, asset.Decimals, asset.TotalSupplyFormatted)
+	}
+
+	txs := node.Transactions()
+	if len(txs) != 1 {
+		t.Fatalf("sent %d transactions, want 1", len(txs))
+	}
+	params, err := evm.DecodeCreateToken(txs[0].Data)
+	if err != nil {
+		t.Fatalf("transaction data: %v", err)
+	}
+	if params.Symbol != "FAN" || params.Decimals != 18 || params.InitialSupply.Cmp(want) != 0 || params.Owner.Hex() != owner || params.MetadataURI != "https://fansmint.example/api/v1/assets/"+asset.ID {
+		t.Errorf("createToken params = %+v", params)
+	}
+
+	// Mined but not yet confirmed
+	app.deployer.SyncOnce(ctx)
+	if got := getAsset(asset.ID); got.Deployment.Status != client.DeploymentPending {
+		t.Errorf("status before confirmations = %s, want pending", got.Deployment.Status)
+	}
+
+	node.MineBlocks(3)
+	if err := app.deployer.SyncOnce(ctx); err != nil {
+		t.Fatalf("SyncOnce: %v", err)
+	}
+	deployed := getAsset(asset.ID)
+	if d := deployed.Deployment; d.Status != client.DeploymentConfirmed || d.BlockNumber != 2 || deployed.ContractAddress == "" {
+		t.Fatalf("deployed asset = %+v %+v, want confirmed in block 2 with a contract", deployed, d)
+	}
+
+	var token struct {
+		Token services.TokenView `json:"token"`
+	}
+	doRequest(t, r, http.MethodGet, "/api/v1/chain/tokens/"+deployed.ContractAddress, nil, &token)
+	if token.Token.Symbol != "FAN" || token.Token.TotalSupplyFormatted != "1000" || token.Token.AssetID != asset.ID {
+		t.Errorf("deployed token = %+v", token.Token)
+	}
+
+	// The indexer picks the new token up from its deployment block
+	app.indexer.SyncOnce(ctx)
+	if got := getAsset(asset.ID); got.Holders != 1 || got.CirculatingSupply != "0" {
+		t.Errorf("indexed holders = %d, circulating = %s, want the owner alone", got.Holders, got.CirculatingSupply)
+	}
+
+	// A reverted transaction fails the deployment
+	node.RevertTransactions(true)
+	reverted := create("REV")
+	node.MineBlocks(3)
+	app.deployer.SyncOnce(ctx)
+	if got := getAsset(reverted.ID); got.Deployment.Status != client.DeploymentFailed || got.Deployment.Error != "transaction reverted" || got.ContractAddress != "" {
+		t.Errorf("reverted deployment = %+v, want failed", got.Deployment)
+	}
+
+	// A dropped transaction stays pending while its nonce may still be mined
+	node.RevertTransactions(false)
+	node.DropTransactions(true)
+	dropped := create("DROP")
+	app.deployer.DropTimeout = time.Nanosecond
+	node.MineBlocks(3)
+	app.deployer.SyncOnce(ctx)
+	if got := getAsset(dropped.ID); got.Deployment.Status != client.DeploymentPending {
+		t.Errorf("dropped deployment with a free nonce = %+v, want pending", got.Deployment)
+	}
+
+	// and fails once another transaction takes the nonce
+	node.DropTransactions(false)
+	replacing := create("NEXT")
+	node.MineBlocks(3)
+	if err := app.deployer.SyncOnce(ctx); err != nil {
+		t.Fatalf("SyncOnce: %v", err)
+	}
+	if got := getAsset(dropped.ID); got.Deployment.Status != client.DeploymentFailed || !strings.Contains(got.Deployment.Error, "nonce") {
+		t.Errorf("dropped deployment with its nonce taken = %+v, want failed", got.Deployment)
+	}
+	if got := getAsset(replacing.ID); got.Deployment.Status != client.DeploymentConfirmed {
+		t.Errorf("replacing deployment = %+v, want confirmed", got.Deployment)
+	}
+
+	// A node serving another chain is never sent a transaction
+	other := evmtest.NewServer(t)
+	cfg.Chain.RPCURL = other.URL
+	cfg.Chain.ChainID = 31337
+	r, _ = newTestApp(t, cfg)
+	var failed assetResponse
+	doRequestAs(t, r, signIn(t, r, testOwnerKey), http.MethodPost, "/api/v1/assets/create", map[string]string{
+		"name": "Wrong Chain", "symbol": "WRONG", "totalSupply": "1",
+	}, &failed)
+	if d := failed.Asset.Deployment; d == nil || d.Status != client.DeploymentFailed || len(other.Transactions()) != 0 {
+		t.Errorf("deployment on a mismatched chain = %+v, want failed before sending", d)
+	}
+}
+
+// testOtherKey is the second account of the default anvil devnet
+const testOtherKey = "0x59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d"
+
+type preparedResponse struct {
+	AssetID     string                        `json:"assetId"`
+	Asset       client.Asset                  `json:"asset"`
+	Transaction *services.UnsignedTransaction `json:"transaction"`
+}
+
+// signPrepared signs a prepared transaction with key
+func signPrepared(t *testing.T, prepared *services.UnsignedTransaction, key *evm.PrivateKey) *evm.Transaction {
+	t.Helper()
+	quantity := func(s string) *big.Int {
+		n, ok := new(big.Int).SetString(strings.TrimPrefix(s, "0x"), 16)
+		if !ok {
+			t.Fatalf("bad quantity %q", s)
+		}
+		return n
+	}
+	to, err := evm.ParseAddress(prepared.To)
+	if err != nil {
+		t.Fatalf("prepared to: %v", err)
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(prepared.Data, "0x"))
+	if err != nil {
+		t.Fatalf("prepared data: %v", err)
+	}
+	tx := &evm.Transaction{
+		ChainID:   quantity(prepared.ChainID),
+		Nonce:     quantity(prepared.Nonce).Uint64(),
+		GasTipCap: quantity(prepared.MaxPriorityFeePerGas),
+		GasFeeCap: quantity(prepared.MaxFeePerGas),
+		Gas:       quantity(prepared.Gas).Uint64(),
+		To:        &to,
+		Value:     quantity(prepared.Value),
+		Data:      data,
+	}
+	tx.Sign(key)
+	return tx
+}
+
+func rawHex(t *testing.T, tx *evm.Transaction) string {
+	t.Helper()
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	return "0x" + hex.EncodeToString(raw)
+}
+
+func TestWalletDeployment(t *testing.T) {
+	node := evmtest.NewServer(t)
+	factory, _ := evm.ParseAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	node.AddFactory(factory)
+	owner, _ := evm.ParsePrivateKey(testDeployerKey)
+	other, _ := evm.ParsePrivateKey(testOtherKey)
+
+	cfg := testConfig(t)
+	cfg.Chain.RPCURL = node.URL
+	cfg.Chain.Factory = factory.Hex()
+	r, app := newTestApp(t, cfg)
+	ctx := context.Background()
+	token := signIn(t, r, testDeployerKey)
+
+	prepare := func(symbol string) preparedResponse {
+		t.Helper()
+		var prepared preparedResponse
+		w := doRequestAs(t, r, token, http.MethodPost, "/api/v1/assets/create/prepare", map[string]string{
+			"name":        symbol + " Fans",
+			"symbol":      symbol,
+			"totalSupply": "500",
+		}, &prepared)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("prepare %s = %d: %s", symbol, w.Code, w.Body.String())
+		}
+		return prepared
+	}
+	submit := func(body map[string]string, out interface{}) int {
+		t.Helper()
+		return doRequestAs(t, r, token, http.MethodPost, "/api/v1/assets/create/submit", body, out).Code
+	}
+
+	// Without a server key plain creation leaves the token undeployed
+	var plain assetResponse
+	doRequestAs(t, r, token, http.MethodPost, "/api/v1/assets/create", map[string]string{
+		"name": "Plain", "symbol": "PLAIN", "totalSupply": "1",
+	}, &plain)
+	if plain.Asset.Deployment != nil || len(node.Transactions()) != 0 {
+		t.Errorf("plain creation deployment = %+v, want none", plain.Asset.Deployment)
+	}
+
+	prepared := prepare("WAL")
+	if d := prepared.Asset.Deployment; d == nil || d.Status != client.DeploymentAwaitingSignature || prepared.Asset.Status != services.StatusPendingReview {
+		t.Fatalf("prepared deployment = %+v with status %q, want awaiting_signature and pending_review", d, prepared.Asset.Status)
+	}
+	unsigned := prepared.Transaction
+	if unsigned == nil || unsigned.Type != "0x2" || unsigned.ChainID != "0xcd13f" || unsigned.To != factory.Hex() || unsigned.From != owner.Address().Hex() || unsigned.Value != "0x0" {
+		t.Fatalf("prepared transaction = %+v", unsigned)
+	}
+	if gas, _ := new(big.Int).SetString(strings.TrimPrefix(unsigned.Gas, "0x"), 16); gas.Uint64() <= evmtest.CreateTokenGas {
+		t.Errorf("prepared gas = %s, want the estimate plus a margin", unsigned.Gas)
+	}
+
+	// The tracker leaves unsubmitted deployments alone
+	if err := app.deployer.SyncOnce(ctx); err != nil {
+		t.Fatalf("SyncOnce: %v", err)
+	}
+
+	// Signed by someone else, or carrying other call data, is rejected
+	var invalid errorResponse
+	if code := submit(map[string]string{"assetId": prepared.AssetID, "rawTransaction": rawHex(t, signPrepared(t, unsigned, other))}, &invalid); code != http.StatusBadRequest || invalid.Error.Details[0].Field != "rawTransaction" {
+		t.Errorf("submit signed by another account = %d %+v, want 400", code, invalid.Error)
+	}
+	tampered := *unsigned
+	tampered.Data = unsigned.Data[:len(unsigned.Data)-2] + "01"
+	if code := submit(map[string]string{"assetId": prepared.AssetID, "rawTransaction": rawHex(t, signPrepared(t, &tampered, owner))}, &invalid); code != http.StatusBadRequest {
+		t.Errorf("submit with tampered data = %d, want 400", code)
+	}
+	if code := submit(map[string]string{"assetId": prepared.AssetID, "rawTransaction": "0x02", "txHash": "0x01"}, &invalid); code != http.StatusBadRequest {
+		t.Errorf("submit with both forms = %d, want 400", code)
+	}
+	if len(node.Transactions()) != 0 {
+		t.Fatalf("rejected submissions reached the node")
+	}
+
+	raw := rawHex(t, signPrepared(t, unsigned, owner))
+
+	// Only the owner may submit the deployment of an asset
+	if w := doRequestAs(t, r, signIn(t, r, testOtherKey), http.MethodPost, "/api/v1/assets/create/submit", map[string]string{"assetId": prepared.AssetID, "rawTransaction": raw}, &invalid); w.Code != http.StatusForbidden || invalid.Error.Code != "not_asset_owner" {
+		t.Errorf("submit by another wallet = %d %+v, want 403 not_asset_owner", w.Code, invalid.Error)
+	}
+	var submitted assetResponse
+	if code := submit(map[string]string{"assetId": prepared.AssetID, "rawTransaction": raw}, &submitted); code != http.StatusAccepted {
+		t.Fatalf("submit = %d, want 202", code)
+	}
+	if d := submitted.Asset.Deployment; d.Status != client.DeploymentPending || d.Sender != owner.Address().Hex() || d.TxHash == "" {
+		t.Fatalf("submitted deployment = %+v, want pending from the owner", d)
+	}
+	if submitted.Asset.Status != services.StatusDeploying {
+		t.Errorf("submitted status = %q, want deploying", submitted.Asset.Status)
+	}
+
+	// Resubmitting the same transaction is idempotent
+	if code := submit(map[string]string{"assetId": prepared.AssetID, "rawTransaction": raw}, &submitted); code != http.StatusAccepted || len(node.Transactions()) != 1 {
+		t.Errorf("resubmit = %d with %d transactions, want 202 with 1", code, len(node.Transactions()))
+	}
+
+	node.MineBlocks(3)
+	if err := app.deployer.SyncOnce(ctx); err != nil {
+		t.Fatalf("SyncOnce: %v", err)
+	}
+	var deployed assetResponse
+	doRequest(t, r, http.MethodGet, "/api/v1/assets/"+prepared.AssetID, nil, &deployed)
+	if d := deployed.Asset.Deployment; d.Status != client.DeploymentConfirmed || deployed.Asset.ContractAddress == "" || deployed.Asset.Status != services.StatusActive {
+		t.Fatalf("deployed asset = %+v with status %q, want confirmed with a contract and active", d, deployed.Asset.Status)
+	}
+	var history struct {
+		History []repository.StatusChange `json:"history"`
+	}
+	doRequest(t, r, http.MethodGet, "/api/v1/assets/"+prepared.AssetID+"/history", nil, &history)
+	var steps []string
+	for _, change := range history.History {
+		steps = append(steps, change.To+" by "+change.Actor)
+	}
+	if want := "pending_review by " + owner.Address().Hex() + ", deploying by " + owner.Address().Hex() + ", active by system"; strings.Join(steps, ", ") != want {
+		t.Errorf("deployment history = %s, want %s", strings.Join(steps, ", "), want)
+	}
+	if code := submit(map[string]string{"assetId": prepared.AssetID, "txHash": submitted.Asset.Deployment.TxHash + "00"}, &invalid); code != http.StatusBadRequest {
+		t.Errorf("submit with a malformed hash = %d, want 400", code)
+	}
+	otherHash := "0x" + strings.Repeat("ab", 32)
+	if code := submit(map[string]string{"assetId": prepared.AssetID, "txHash": otherHash}, &invalid); code != http.StatusConflict || invalid.Error.Code != "deployment_conflict" {
+		t.Errorf("submit after confirmation = %d %+v, want 409", code, invalid.Error)
+	}
+
+	// A wallet that broadcast the transaction itself submits its hash
+	byHash := prepare("HASH")
+	hash, err := evm.NewClient(node.URL).SendTransaction(ctx, signPrepared(t, byHash.Transaction, owner))
+	if err != nil {
+		t.Fatalf("wallet broadcast: %v", err)
+	}
+	if code := submit(map[string]string{"assetId": byHash.AssetID, "txHash": otherHash}, &invalid); code != http.StatusBadRequest {
+		t.Errorf("submit with an unknown hash = %d, want 400", code)
+	}
+	if code := submit(map[string]string{"assetId": byHash.AssetID, "txHash": hash.Hex()}, &submitted); code != http.StatusAccepted || submitted.Asset.Deployment.TxHash != hash.Hex() {
+		t.Fatalf("submit by hash = %d %+v", code, submitted.Asset.Deployment)
+	}
+	node.MineBlocks(3)
+	app.deployer.SyncOnce(ctx)
+	doRequest(t, r, http.MethodGet, "/api/v1/assets/"+byHash.AssetID, nil, &deployed)
+	if deployed.Asset.Deployment.Status != client.DeploymentConfirmed {
+		t.Errorf("deployment submitted by hash = %+v, want confirmed", deployed.Asset.Deployment)
+	}
+
+	// Without a factory nothing can be prepared
+	cfg.Chain.Factory = ""
+	r, _ = newTestApp(t, cfg)
+	if w := doRequestAs(t, r, signIn(t, r, testDeployerKey), http.MethodPost, "/api/v1/assets/create/prepare", map[string]string{
+		"name": "Off", "symbol": "OFF", "totalSupply": "1",
+	}, &invalid); w.Code != http.StatusServiceUnavailable || invalid.Error.Code != "deployment_disabled" {
+		t.Errorf("prepare without a factory = %d %+v, want 503", w.Code, invalid.Error)
+	}
+}
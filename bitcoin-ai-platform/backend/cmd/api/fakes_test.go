@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
+)
+
+const testExSatAPIKey = "test-exsat-key"
+
+// fakeExSat is an in-memory stand-in for the exSat REST API
+type fakeExSat struct {
+	mu     sync.Mutex
+	assets map[string]client.Asset
+	order  []string
+	calls  int
+	delay  time.Duration // applied before answering, to exercise deadlines
+
+	// pageSize, when set, caps the listing page size to exercise cursors
+	pageSize int
+	// held, when set, holds the next asset lookup: the lookup sends on it
+	// once it arrives and answers after receiving from it
+	held chan struct{}
+}
+
+// newFakeExSat starts a fake exSat server pre-loaded with assets
+func newFakeExSat(t *testing.T, assets ...client.Asset) (*fakeExSat, *httptest.Server) {
+	t.Helper()
+
+	f := &fakeExSat{assets: make(map[string]client.Asset)}
+	for _, asset := range assets {
+		f.assets[asset.ID] = asset
+		f.order = append(f.order, asset.ID)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(server.Close)
+	return f, server
+}
+
+func (f *fakeExSat) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if f.delay > 0 {
+		select {
+		case <-time.After(f.delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+
+	if r.Header.Get("Authorization") != "Bearer "+testExSatAPIKey {
+		writeFakeJSON(w, http.StatusUnauthorized, map[string]interface{}{"success": false, "message": "invalid API key"})
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/assets/create":
+		var params client.AssetCreateParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			writeFakeJSON(w, http.StatusBadRequest, map[string]interface{}{"success": false, "message": err.Error()})
+			return
+		}
+		asset := client.Asset{
+			ID:              fmt.Sprintf("exsat_%d", len(f.order)+1),
+			Name:            params.Name,
+			Symbol:          params.Symbol,
+			TotalSupply:     params.TotalSupply,
+			Decimals:        params.Decimals,
+			Description:     params.Description,
+			CreatorAddress:  params.OwnerAddress,
+			ContractAddress: "0x000000000000000000000000000000000000beef",
+			CreatedAt:       "2024-01-01T00:00:00Z",
+			Status:          "active",
+		}
+		f.assets[asset.ID] = asset
+		f.order = append(f.order, asset.ID)
+		writeFakeJSON(w, http.StatusCreated, client.AssetResponse{Success: true, Asset: asset})
+	case r.Method == http.MethodGet && r.URL.Path == "/assets":
+		// The cursor is simply the index of the next asset
+		start, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if f.pageSize > 0 && (limit <= 0 || limit > f.pageSize) {
+			limit = f.pageSize
+		}
+		end := len(f.order)
+		if limit > 0 {
+			end = min(start+limit, end)
+		}
+
+		response := client.AssetsResponse{Success: true}
+		for _, id := range f.order[min(start, end):end] {
+			response.Assets = append(response.Assets, f.assets[id])
+		}
+		if end < len(f.order) {
+			response.NextCursor = strconv.Itoa(end)
+		}
+		writeFakeJSON(w, http.StatusOK, response)
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/assets/"):
+		if held := f.held; held != nil {
+			f.held = nil
+			f.mu.Unlock()
+			held <- struct{}{}
+			<-held
+			f.mu.Lock()
+		}
+		asset, ok := f.assets[strings.TrimPrefix(r.URL.Path, "/assets/")]
+		if !ok {
+			writeFakeJSON(w, http.StatusNotFound, map[string]interface{}{"success": false, "message": "asset not found"})
+			return
+		}
+		writeFakeJSON(w, http.StatusOK, client.AssetResponse{Success: true, Asset: asset})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// callCount returns the number of requests served so far
+func (f *fakeExSat) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+// newFakeOpenAI starts a fake OpenAI-compatible chat completions server.
+// JSON mode requests get suggestionsJSON, streaming requests get the
+// whitepaper in chunks followed by a usage chunk.
+func newFakeOpenAI(t *testing.T, suggestionsJSON string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+
+		var req struct {
+			Stream         bool `json:"stream"`
+			ResponseFormat *struct {
+				Type string `json:"type"`
+			} `json:"response_format"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if req.Stream {
+			w.Header().Set("Content-Type", "text/event-stream")
+			for _, chunk := range []string{"# Fake Whitepaper\n", "\n## Abstract\n", "Streamed by a fake model.\n"} {
+				data, _ := json.Marshal(map[string]interface{}{
+					"object":  "chat.completion.chunk",
+					"choices": []map[string]interface{}{{"index": 0, "delta": map[string]string{"content": chunk}}},
+				})
+				fmt.Fprintf(w, "data: %s\n\n", data)
+			}
+			fmt.Fprint(w, `data: {"object":"chat.completion.chunk","choices":[],"usage":{"prompt_tokens":10,"completion_tokens":8,"total_tokens":18}}`+"\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+			return
+		}
+
+		content := "# Fake Whitepaper\n\nGenerated by a fake model."
+		if req.ResponseFormat != nil && req.ResponseFormat.Type == "json_object" {
+			content = suggestionsJSON
+		}
+		writeFakeJSON(w, http.StatusOK, map[string]interface{}{
+			"object": "chat.completion",
+			"choices": []map[string]interface{}{
+				{"index": 0, "message": map[string]string{"role": "assistant", "content": content}},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// writeFakeJSON writes a JSON response from a fake server
+func writeFakeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
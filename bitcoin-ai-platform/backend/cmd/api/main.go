@@ -1,93 +1,131 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
+	"github.com/yourusername/bitcoin-ai-platform/internal/config"
 	"github.com/yourusername/bitcoin-ai-platform/internal/handlers"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
 )
 
 func main() {
-	// Load environment variables
-	if err := godotenv.Load(); err != nil {
-		log.Println("Warning: .env file not found")
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML or TOML config file, e.g. config/production.yaml")
+	printConfig := flag.Bool("print-config", false, "print the effective configuration with secrets redacted and exit")
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Unable to load configuration: %s", err.Error())
+	}
+
+	if *printConfig {
+		out, err := cfg.YAML()
+		if err != nil {
+			log.Fatalf("Unable to print configuration: %s", err.Error())
+		}
+		fmt.Print(out)
+		return
 	}
 
 	// Set gin mode
-	if os.Getenv("ENV") == "production" {
+	if cfg.Env == config.EnvProduction {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	// Create gin router
 	r := gin.Default()
 
-	// Configure CORS
+	// Configure CORS. Sessions travel in the Authorization header, so
+	// browsers never need to send cookies.
 	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
+		AllowOrigins:  cfg.Auth.AllowedOrigins,
+		AllowMethods:  []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowHeaders:  []string{"Origin", "Content-Type", "Authorization", "Idempotency-Key", handlers.RequestIDHeader},
+		ExposeHeaders: []string{"Content-Length", "Retry-After", handlers.RequestIDHeader},
 	}))
 
 	// API routes
-	setupRoutes(r)
+	app, err := newApp(cfg)
+	if err != nil {
+		log.Fatalf("Unable to start: %s", err.Error())
+	}
+	defer app.close()
+	setupRoutes(r, app)
 
-	// Get port
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	// Follow the exSat listing, Transfer logs of FansMint tokens and
+	// pending deployments in the background
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	if interval := cfg.ExSat.SyncInterval.Duration; interval > 0 && !app.mockMode {
+		go app.assetSync.Run(ctx, interval)
+	}
+	if interval := cfg.Chain.IndexInterval.Duration; interval > 0 {
+		go app.indexer.Run(ctx, interval)
+	}
+	if app.deployer != nil {
+		go app.deployer.Run(ctx, cfg.Chain.DeployPollInterval.Duration)
 	}
 
 	// Start server
-	fmt.Printf("Server started at http://localhost:%s\n", port)
-	if err := r.Run(":" + port); err != nil {
+	fmt.Printf("Server started at http://localhost:%s\n", cfg.Port)
+	if err := r.Run(":" + cfg.Port); err != nil {
 		log.Fatalf("Unable to start server: %s", err.Error())
 	}
 }
 
-func setupRoutes(r *gin.Engine) {
+func setupRoutes(r *gin.Engine, app *app) {
+	// Every failure leaves as the same JSON envelope, tagged with the request ID
+	r.Use(handlers.RequestID(), handlers.ErrorHandler())
+	r.NoRoute(handlers.NotFound)
+
 	// Health check
 	r.GET("/health", func(c *gin.Context) {
+		// exSat trouble degrades the service without taking it down, the
+		// stored assets and the AI routes keep working
+		circuit := app.exSat.CircuitState()
+		status := "healthy"
+		if !app.mockMode && circuit != client.CircuitClosed {
+			status = "degraded"
+		}
+
 		c.JSON(200, gin.H{
-			"status": "healthy",
+			"status": status,
+			"exsat": gin.H{
+				"mockMode": app.mockMode,
+				"circuit":  circuit,
+			},
 		})
 	})
 
 	// API v1 routes
 	v1 := r.Group("/api/v1")
 	{
+		// Sign-In with Ethereum
+		app.authHandler.RegisterRoutes(v1)
+
 		// Asset routes
-		assetHandler := handlers.NewAssetHandler()
-		assetHandler.RegisterRoutes(v1)
+		app.assetHandler.RegisterRoutes(v1)
+
+		// Versioned asset whitepapers
+		app.whitepaperHandler.RegisterRoutes(v1)
+
+		// Asset creations in progress of the signed-in wallet
+		app.draftHandler.RegisterRoutes(v1)
 
 		// AI related routes
-		ai := v1.Group("/ai")
-		{
-			ai.POST("/generate-whitepaper", func(c *gin.Context) {
-				c.JSON(200, gin.H{
-					"message": "Whitepaper generated successfully",
-					"content": "# ExampleToken Whitepaper\n\n## Introduction\nExampleToken is a digital asset based on the Bitcoin ecosystem...",
-				})
-			})
-			ai.POST("/token-suggestion", func(c *gin.Context) {
-				c.JSON(200, gin.H{
-					"message": "Token suggestions generated successfully",
-					"suggestions": []map[string]interface{}{
-						{
-							"name":            "Suggested Name",
-							"symbol":          "SYM",
-							"description":     "This is a suggested token description",
-							"useCase":         "Can be used for...",
-							"marketPotential": "Market potential analysis...",
-						},
-					},
-				})
-			})
-		}
+		app.aiHandler.RegisterRoutes(v1)
+
+		// exSat EVM reads
+		app.chainHandler.RegisterRoutes(v1)
+
+		// Wallet portfolios from indexed chain data
+		app.walletHandler.RegisterRoutes(v1)
 	}
 }
@@ -0,0 +1,1056 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/bitcoin-ai-platform/internal/config"
+	"github.com/yourusername/bitcoin-ai-platform/internal/repository"
+	"github.com/yourusername/bitcoin-ai-platform/internal/services"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
+)
+
+// testConfig returns a mock mode configuration with in-memory asset storage
+func testConfig(t *testing.T) *config.Config {
+	t.Helper()
+
+	cfg := config.Default()
+	cfg.Env = config.EnvTest
+	cfg.AI.Provider = "mock"
+	cfg.Storage.AssetDBPath = "memory"
+	cfg.Storage.IconDir = t.TempDir()
+	return cfg
+}
+
+// newTestRouter wires the application for cfg and returns its router
+func newTestRouter(t *testing.T, cfg *config.Config) *gin.Engine {
+	t.Helper()
+
+	r, _ := newTestApp(t, cfg)
+	return r
+}
+
+// newTestApp wires the application for cfg and returns its router along
+// with the app, for tests that drive background work themselves
+func newTestApp(t *testing.T, cfg *config.Config) (*gin.Engine, *app) {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	app, err := newApp(cfg)
+	if err != nil {
+		t.Fatalf("newApp: %v", err)
+	}
+	t.Cleanup(app.close)
+	setupRoutes(r, app)
+	return r, app
+}
+
+// doRequest performs a request against the router and decodes a JSON response into out
+func doRequest(t *testing.T, r http.Handler, method, path string, body interface{}, out interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	return doRequestAs(t, r, "", method, path, body, out)
+}
+
+// doRequestAs performs a request with the session token, when not empty
+func doRequestAs(t *testing.T, r http.Handler, token, method, path string, body interface{}, out interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	header := http.Header{}
+	if token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+	return doRequestWith(t, r, header, method, path, body, out)
+}
+
+// doRequestWith performs a request with extra headers
+func doRequestWith(t *testing.T, r http.Handler, header http.Header, method, path string, body interface{}, out interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if out != nil && strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("decode %s %s response: %v\n%s", method, path, err, w.Body.String())
+		}
+	}
+	return w
+}
+
+// testPNG returns a base64 encoded PNG of the given size
+func testPNG(t *testing.T, width, height int) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+type errorResponse struct {
+	Error struct {
+		Code      string              `json:"code"`
+		Message   string              `json:"message"`
+		Details   []client.FieldError `json:"details"`
+		RequestID string              `json:"requestId"`
+	} `json:"error"`
+}
+
+type assetResponse struct {
+	Message string       `json:"message"`
+	AssetID string       `json:"assetId"`
+	IconURL string       `json:"iconUrl"`
+	Asset   client.Asset `json:"asset"`
+}
+
+type assetsResponse struct {
+	Assets     []client.Asset `json:"assets"`
+	NextCursor string         `json:"nextCursor"`
+}
+
+func TestHealth(t *testing.T) {
+	r := newTestRouter(t, testConfig(t))
+
+	var health struct {
+		Status string `json:"status"`
+		ExSat  struct {
+			Circuit string `json:"circuit"`
+		} `json:"exsat"`
+	}
+	w := doRequest(t, r, http.MethodGet, "/health", nil, &health)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /health = %d, want 200", w.Code)
+	}
+	if health.Status != "healthy" || health.ExSat.Circuit != "closed" {
+		t.Errorf("health = %+v, want healthy with a closed circuit", health)
+	}
+}
+
+func TestHealthReportsOpenCircuit(t *testing.T) {
+	_, server := newFakeExSat(t)
+	server.Close()
+	cfg := testConfig(t)
+	cfg.ExSat.APIURL = server.URL
+	cfg.ExSat.APIKey = testExSatAPIKey
+	cfg.ExSat.MaxAttempts = 1
+	cfg.ExSat.BreakerThreshold = 1
+	r := newTestRouter(t, cfg)
+
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/assets/exsat_0", nil, nil); w.Code == http.StatusOK {
+		t.Fatal("get with exSat down = 200, want an error")
+	}
+
+	var health struct {
+		Status string `json:"status"`
+		ExSat  struct {
+			Circuit string `json:"circuit"`
+		} `json:"exsat"`
+	}
+	doRequest(t, r, http.MethodGet, "/health", nil, &health)
+	if health.Status != "degraded" || health.ExSat.Circuit != "open" {
+		t.Errorf("health = %+v, want degraded with an open circuit", health)
+	}
+}
+
+func TestStorageFailureStopsStartup(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A second instance finds the database locked
+	locked := filepath.Join(dir, "fansmint.db")
+	cfg := testConfig(t)
+	cfg.Storage.AssetDBPath = locked
+	newTestApp(t, cfg)
+
+	for _, path := range []string{filepath.Join(file, "fansmint.db"), locked} {
+		cfg := testConfig(t)
+		cfg.Storage.AssetDBPath = path
+		if app, err := newApp(cfg); err == nil {
+			app.close()
+			t.Errorf("newApp with the asset store at %s succeeded, want an error", path)
+		}
+	}
+
+	// A failing icon store releases the asset store opened before it
+	cfg = testConfig(t)
+	cfg.Storage.AssetDBPath = filepath.Join(dir, "icons.db")
+	cfg.Storage.IconDir = filepath.Join(file, "icons")
+	if app, err := newApp(cfg); err == nil {
+		app.close()
+		t.Errorf("newApp with the icon store at %s succeeded, want an error", cfg.Storage.IconDir)
+	}
+	cfg.Storage.IconDir = t.TempDir()
+	newTestApp(t, cfg)
+}
+
+func TestAssetRoutesMockMode(t *testing.T) {
+	r := newTestRouter(t, testConfig(t))
+	token := signIn(t, r, testOwnerKey)
+
+	var list assetsResponse
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/assets/", nil, &list); w.Code != http.StatusOK {
+		t.Fatalf("list = %d, want 200", w.Code)
+	}
+	if len(list.Assets) != 2 {
+		t.Fatalf("list returned %d seeded assets, want 2", len(list.Assets))
+	}
+
+	var created assetResponse
+	w := doRequestAs(t, r, token, http.MethodPost, "/api/v1/assets/create", map[string]string{
+		"name":        "Moon Fans",
+		"symbol":      "MOON",
+		"totalSupply": "1000",
+		"iconData":    "data:image/png;base64," + testPNG(t, 256, 256),
+	}, &created)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create = %d, want 201: %s", w.Code, w.Body.String())
+	}
+	if !strings.HasPrefix(created.AssetID, "ast_") {
+		t.Errorf("assetId = %q, want ast_ prefix", created.AssetID)
+	}
+
+	var fetched assetResponse
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/assets/"+created.AssetID, nil, &fetched); w.Code != http.StatusOK {
+		t.Fatalf("get created asset = %d, want 200", w.Code)
+	}
+	if fetched.Asset.Symbol != "MOON" || fetched.Asset.IconUrl != created.IconURL {
+		t.Errorf("fetched asset = %+v, want symbol MOON and icon %s", fetched.Asset, created.IconURL)
+	}
+
+	var missing errorResponse
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/assets/does-not-exist", nil, &missing); w.Code != http.StatusNotFound {
+		t.Errorf("get missing asset = %d, want 404", w.Code)
+	}
+	if missing.Error.Code != "asset_not_found" || missing.Error.RequestID == "" {
+		t.Errorf("missing asset error = %+v, want asset_not_found with a request ID", missing.Error)
+	}
+
+	var invalid errorResponse
+	w = doRequestAs(t, r, token, http.MethodPost, "/api/v1/assets/create", map[string]string{"name": "No Symbol"}, &invalid)
+	if w.Code != http.StatusBadRequest || invalid.Error.Code != "validation_failed" || len(invalid.Error.Details) != 2 {
+		t.Errorf("create without fields = %d %+v, want 400 validation_failed on 2 fields", w.Code, invalid.Error)
+	}
+
+	w = doRequestAs(t, r, token, http.MethodPost, "/api/v1/assets/create", map[string]string{
+		"name":        "Bad Icon",
+		"symbol":      "BAD",
+		"totalSupply": "1000",
+		"iconData":    base64.StdEncoding.EncodeToString([]byte("not an image")),
+	}, nil)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("create with invalid icon = %d, want 400", w.Code)
+	}
+}
+
+func TestAssetSupply(t *testing.T) {
+	r := newTestRouter(t, testConfig(t))
+	token := signIn(t, r, testOwnerKey)
+
+	var created assetResponse
+	w := doRequestAs(t, r, token, http.MethodPost, "/api/v1/assets/create", map[string]any{
+		"name": "Six Decimals", "symbol": "SIX", "totalSupply": "1,000,000.25", "decimals": 6,
+	}, &created)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create = %d: %s", w.Code, w.Body.String())
+	}
+	if a := created.Asset; a.TotalSupply != "1000000250000" || a.Decimals != 6 || a.TotalSupplyFormatted != "1000000.25" || a.CirculatingSupplyFormatted != "0" {
+		t.Errorf("created supply = %s with %d decimals (%s), want 1000000250000 base units of 6 decimals", a.TotalSupply, a.Decimals, a.TotalSupplyFormatted)
+	}
+
+	// Seeded assets are served in base units too
+	var seeded assetResponse
+	doRequest(t, r, http.MethodGet, "/api/v1/assets/1", nil, &seeded)
+	if a := seeded.Asset; a.TotalSupply != "1000000000000000000000000" || a.TotalSupplyFormatted != "1000000" || a.CirculatingSupplyFormatted != "750000" {
+		t.Errorf("seeded supply = %+v", a)
+	}
+
+	for _, tc := range []struct {
+		supply   string
+		decimals int
+		field    string
+	}{
+		{"0", 18, "totalSupply"},
+		{"-5", 18, "totalSupply"},
+		{"1.5", 0, "totalSupply"},
+		{"1.0000001", 6, "totalSupply"},
+		{"1" + strings.Repeat("0", 60), 18, "totalSupply"}, // beyond uint256 in base units
+		{"1000", 19, "decimals"},
+	} {
+		var invalid errorResponse
+		w := doRequestAs(t, r, token, http.MethodPost, "/api/v1/assets/create", map[string]any{
+			"name": "Bad Supply", "symbol": "BAD", "totalSupply": tc.supply, "decimals": tc.decimals,
+		}, &invalid)
+		if w.Code != http.StatusBadRequest || len(invalid.Error.Details) != 1 || invalid.Error.Details[0].Field != tc.field {
+			t.Errorf("create with %s tokens of %d decimals = %d %+v, want 400 on %s", tc.supply, tc.decimals, w.Code, invalid.Error, tc.field)
+		}
+	}
+}
+
+func TestSymbolAvailability(t *testing.T) {
+	r := newTestRouter(t, testConfig(t))
+	token := signIn(t, r, testOwnerKey)
+
+	type availabilityResponse struct {
+		Symbol    string `json:"symbol"`
+		Available bool   `json:"available"`
+		Reason    string `json:"reason"`
+	}
+	for symbol, want := range map[string]string{"NEW1": "available", "ext": "taken", "xSat": "blocked", "X": "invalid", "TOO-LONG": "invalid"} {
+		var resp availabilityResponse
+		w := doRequest(t, r, http.MethodGet, "/api/v1/assets/symbols/"+symbol+"/availability", nil, &resp)
+		if w.Code != http.StatusOK || resp.Reason != want || resp.Available != (want == "available") {
+			t.Errorf("availability of %s = %d %+v, want %s", symbol, w.Code, resp, want)
+		}
+	}
+
+	// Seeded EXT and ExampleToken are taken whatever their case
+	for _, tc := range []struct{ name, symbol, field string }{
+		{"Other", "Ext", "symbol"},
+		{"exampletoken", "OTHER", "name"},
+		{"Bitcoin", "BTC", "symbol"},
+	} {
+		var invalid errorResponse
+		w := doRequestAs(t, r, token, http.MethodPost, "/api/v1/assets/create", map[string]string{
+			"name": tc.name, "symbol": tc.symbol, "totalSupply": "1",
+		}, &invalid)
+		if w.Code != http.StatusBadRequest || len(invalid.Error.Details) != 1 || invalid.Error.Details[0].Field != tc.field {
+			t.Errorf("create %s/%s = %d %+v, want 400 on %s", tc.name, tc.symbol, w.Code, invalid.Error, tc.field)
+		}
+	}
+
+	// A creation reserves its symbol; retries with its idempotency key share it
+	symbols := services.NewSymbolRegistry(repository.NewMemoryAssetRepository(), nil)
+	if _, err := symbols.Reserve("MOON", "Moon Fans", "0xa/key"); err != nil {
+		t.Fatalf("Reserve: %v", err)
+	}
+	release, err := symbols.Reserve("moon", "Moon Fans", "0xa/key")
+	if err != nil {
+		t.Fatalf("retried Reserve: %v", err)
+	}
+	if _, err := symbols.Reserve("moon", "Other", "0xb/key"); err == nil {
+		t.Error("Reserve of a reserved symbol succeeded")
+	}
+	if got, _ := symbols.Check("MOON"); got.Reason != services.SymbolReserved {
+		t.Errorf("reserved symbol = %+v, want reserved", got)
+	}
+	release()
+	if got, _ := symbols.Check("MOON"); !got.Available {
+		t.Errorf("released symbol = %+v, want available", got)
+	}
+
+	// A retried creation gets the asset it made rather than a second one
+	header := http.Header{"Authorization": {"Bearer " + token}, "Idempotency-Key": {"k1"}}
+	var first, retried assetResponse
+	for _, out := range []*assetResponse{&first, &retried} {
+		if w := doRequestWith(t, r, header, http.MethodPost, "/api/v1/assets/create", map[string]string{
+			"name": "Moonx Fans", "symbol": "MOONX", "totalSupply": "1",
+		}, out); w.Code != http.StatusCreated {
+			t.Fatalf("create with an idempotency key = %d: %s", w.Code, w.Body.String())
+		}
+	}
+	var moonx assetsResponse
+	doRequest(t, r, http.MethodGet, "/api/v1/assets/?symbol=MOONX", nil, &moonx)
+	if retried.AssetID != first.AssetID || len(moonx.Assets) != 1 {
+		t.Errorf("retried creation made %s after %s, %d MOONX assets stored, want one asset", retried.AssetID, first.AssetID, len(moonx.Assets))
+	}
+
+	// Past its reservation a retry may still use what its creation made
+	assets := repository.NewMemoryAssetRepository()
+	if err := assets.Save(client.Asset{ID: "a1", Name: "Sun  Fans", Symbol: "SUN"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := assets.RecordCreation("0xa/key", "a1"); err != nil {
+		t.Fatalf("RecordCreation: %v", err)
+	}
+	symbols = services.NewSymbolRegistry(assets, nil)
+	if _, err := symbols.Reserve("sun", "sun fans", "0xa/key"); err != nil {
+		t.Errorf("Reserve by the creation of the asset: %v", err)
+	}
+	symbols = services.NewSymbolRegistry(assets, nil)
+	if _, err := symbols.Reserve("SUN", "Sun Fans", "0xa/other"); err == nil {
+		t.Error("Reserve by another creation of a taken symbol succeeded")
+	}
+	if got, _ := symbols.Check("Sun"); got.Reason != services.SymbolTaken {
+		t.Errorf("taken symbol = %+v, want taken", got)
+	}
+}
+
+func TestAssetListing(t *testing.T) {
+	r := newTestRouter(t, testConfig(t))
+	token := signIn(t, r, testOwnerKey)
+
+	owner := parseKey(t, testOwnerKey).Address().Hex()
+	for i, symbol := range []string{"MOON", "MOOD", "STAR"} {
+		w := doRequestAs(t, r, token, http.MethodPost, "/api/v1/assets/create", map[string]string{
+			"name":        symbol + " Fans",
+			"symbol":      symbol,
+			"totalSupply": fmt.Sprintf("%d", (i+1)*10_000_000),
+		}, nil)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create %s = %d", symbol, w.Code)
+		}
+	}
+
+	// Walk every page of two, the 3 created and 2 seeded assets must each appear once
+	seen := make(map[string]bool)
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("listing did not end after 3 pages of 2")
+		}
+		var page assetsResponse
+		w := doRequest(t, r, http.MethodGet, "/api/v1/assets/?limit=2&cursor="+url.QueryEscape(cursor), nil, &page)
+		if w.Code != http.StatusOK || len(page.Assets) > 2 {
+			t.Fatalf("page %d = %d with %d assets, want 200 with at most 2", pages, w.Code, len(page.Assets))
+		}
+		for _, asset := range page.Assets {
+			if seen[asset.ID] {
+				t.Errorf("asset %s listed twice", asset.ID)
+			}
+			seen[asset.ID] = true
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if len(seen) != 5 {
+		t.Errorf("paged through %d assets, want 5", len(seen))
+	}
+
+	var bySupply assetsResponse
+	doRequest(t, r, http.MethodGet, "/api/v1/assets/?sort=supply&limit=1", nil, &bySupply)
+	if len(bySupply.Assets) != 1 || bySupply.Assets[0].Symbol != "STAR" || bySupply.NextCursor == "" {
+		t.Errorf("largest supply = %+v, want STAR with a next cursor", bySupply)
+	}
+
+	var filtered assetsResponse
+	doRequest(t, r, http.MethodGet, "/api/v1/assets/?symbol=mo&owner="+strings.ToLower(owner)+"&status=active", nil, &filtered)
+	if len(filtered.Assets) != 2 {
+		t.Errorf("symbol prefix mo listed %d assets, want MOON and MOOD", len(filtered.Assets))
+	}
+
+	for _, query := range []string{"sort=price", "limit=0", "limit=1000", "cursor=bogus", "sort=holders&cursor=" + bySupply.NextCursor} {
+		var invalid errorResponse
+		w := doRequest(t, r, http.MethodGet, "/api/v1/assets/?"+query, nil, &invalid)
+		if w.Code != http.StatusBadRequest || invalid.Error.Code != "validation_failed" {
+			t.Errorf("list with %s = %d %+v, want 400 validation_failed", query, w.Code, invalid.Error)
+		}
+	}
+}
+
+func TestAssetLifecycle(t *testing.T) {
+	r := newTestRouter(t, testConfig(t))
+	token := signIn(t, r, testOwnerKey)
+	owner := parseKey(t, testOwnerKey).Address().Hex()
+
+	type historyResponse struct {
+		Status  string                    `json:"status"`
+		History []repository.StatusChange `json:"history"`
+	}
+
+	var created assetResponse
+	doRequestAs(t, r, token, http.MethodPost, "/api/v1/assets/create", map[string]string{
+		"name": "Touring Fans", "symbol": "TOUR", "totalSupply": "100",
+	}, &created)
+	// Without a deployer the asset is active as soon as it is created
+	if created.Asset.Status != services.StatusActive {
+		t.Fatalf("created status = %q, want active", created.Asset.Status)
+	}
+	base := "/api/v1/assets/" + created.AssetID
+
+	change := func(token, action string, body interface{}, out interface{}) int {
+		t.Helper()
+		return doRequestAs(t, r, token, http.MethodPost, base+"/"+action, body, out).Code
+	}
+
+	var failed errorResponse
+	if code := change("", "pause", nil, &failed); code != http.StatusUnauthorized {
+		t.Errorf("pause without a session = %d, want 401", code)
+	}
+	if code := change(signIn(t, r, testOtherKey), "pause", nil, &failed); code != http.StatusForbidden || failed.Error.Code != "not_asset_owner" {
+		t.Errorf("pause by another wallet = %d %+v, want 403 not_asset_owner", code, failed.Error)
+	}
+	if code := change(token, "resume", nil, &failed); code != http.StatusConflict || failed.Error.Code != "invalid_status_transition" {
+		t.Errorf("resume of an active asset = %d %+v, want 409 invalid_status_transition", code, failed.Error)
+	}
+	if code := change(token, "pause", map[string]string{"reason": strings.Repeat("x", services.MaxReasonLength+1)}, &failed); code != http.StatusBadRequest || failed.Error.Details[0].Field != "reason" {
+		t.Errorf("pause with a long reason = %d %+v, want 400 on reason", code, failed.Error)
+	}
+
+	var paused assetResponse
+	if code := change(token, "pause", map[string]string{"reason": "  Tour postponed "}, &paused); code != http.StatusOK || paused.Asset.Status != services.StatusPaused {
+		t.Fatalf("pause = %d with status %q, want 200 paused", code, paused.Asset.Status)
+	}
+	if code := change(token, "pause", nil, &failed); code != http.StatusConflict {
+		t.Errorf("pause of a paused asset = %d, want 409", code)
+	}
+	var listed struct {
+		Assets []client.Asset `json:"assets"`
+	}
+	doRequest(t, r, http.MethodGet, "/api/v1/assets/?status=paused", nil, &listed)
+	if len(listed.Assets) != 1 || listed.Assets[0].ID != created.AssetID {
+		t.Errorf("paused assets = %+v, want the paused one", listed.Assets)
+	}
+
+	var resumed, retired assetResponse
+	if code := change(token, "resume", nil, &resumed); code != http.StatusOK || resumed.Asset.Status != services.StatusActive {
+		t.Errorf("resume = %d with status %q, want 200 active", code, resumed.Asset.Status)
+	}
+	if code := change(token, "retire", map[string]string{"reason": "Farewell"}, &retired); code != http.StatusOK || retired.Asset.Status != services.StatusRetired {
+		t.Errorf("retire = %d with status %q, want 200 retired", code, retired.Asset.Status)
+	}
+	// Retirement is final
+	for _, action := range []string{"resume", "pause", "retire"} {
+		if code := change(token, action, nil, &failed); code != http.StatusConflict {
+			t.Errorf("%s of a retired asset = %d, want 409", action, code)
+		}
+	}
+
+	var history historyResponse
+	if w := doRequest(t, r, http.MethodGet, base+"/history", nil, &history); w.Code != http.StatusOK {
+		t.Fatalf("history = %d: %s", w.Code, w.Body.String())
+	}
+	want := []repository.StatusChange{
+		{From: "", To: "active", Actor: owner},
+		{From: "active", To: "paused", Actor: owner, Reason: "Tour postponed"},
+		{From: "paused", To: "active", Actor: owner},
+		{From: "active", To: "retired", Actor: owner, Reason: "Farewell"},
+	}
+	if history.Status != services.StatusRetired || len(history.History) != len(want) {
+		t.Fatalf("history = %+v, want %d changes ending retired", history, len(want))
+	}
+	for i, got := range history.History {
+		if got.From != want[i].From || got.To != want[i].To || got.Actor != want[i].Actor || got.Reason != want[i].Reason || got.At.IsZero() {
+			t.Errorf("change %d = %+v, want %+v", i, got, want[i])
+		}
+		if i > 0 && got.At.Before(history.History[i-1].At) {
+			t.Errorf("change %d at %s precedes the one before", i, got.At)
+		}
+	}
+
+	// Seeded assets start their history as active
+	var seeded historyResponse
+	doRequest(t, r, http.MethodGet, "/api/v1/assets/1/history", nil, &seeded)
+	if len(seeded.History) != 1 || seeded.History[0].To != services.StatusActive || seeded.History[0].Actor != services.ActorSystem {
+		t.Errorf("seeded history = %+v, want one active change by the system", seeded.History)
+	}
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/assets/missing/history", nil, &failed); w.Code != http.StatusNotFound {
+		t.Errorf("history of a missing asset = %d, want 404", w.Code)
+	}
+}
+
+func TestAssetDrafts(t *testing.T) {
+	r := newTestRouter(t, testConfig(t))
+	token := signIn(t, r, testOwnerKey)
+	other := signIn(t, r, testOtherKey)
+	owner := parseKey(t, testOwnerKey).Address().Hex()
+
+	type draftResponse struct {
+		DraftID string           `json:"draftId"`
+		Draft   repository.Draft `json:"draft"`
+	}
+	type draftList struct {
+		Drafts []repository.Draft `json:"drafts"`
+	}
+
+	var failed errorResponse
+	if w := doRequest(t, r, http.MethodPost, "/api/v1/drafts", map[string]string{"useCase": "fans"}, &failed); w.Code != http.StatusUnauthorized {
+		t.Fatalf("draft without a session = %d, want 401", w.Code)
+	}
+
+	var started draftResponse
+	if w := doRequestAs(t, r, token, http.MethodPost, "/api/v1/drafts", map[string]interface{}{
+		"step": 1, "useCase": "A touring band rewarding its fans",
+	}, &started); w.Code != http.StatusCreated {
+		t.Fatalf("create draft = %d: %s", w.Code, w.Body.String())
+	}
+	if started.Draft.Owner != owner || started.Draft.UseCase == "" || started.Draft.Step != 1 {
+		t.Fatalf("draft = %+v, want the use case at step 1 owned by %s", started.Draft, owner)
+	}
+	base := "/api/v1/drafts/" + started.DraftID
+
+	// Later steps only send what they collect
+	var updated draftResponse
+	doRequestAs(t, r, token, http.MethodPatch, base, map[string]interface{}{
+		"step":       2,
+		"suggestion": map[string]interface{}{"rank": 1, "name": "Touring Fans", "symbol": "TOUR"},
+		"name":       "Touring Fans",
+		"symbol":     "TOUR",
+		"whitepaper": "# Touring Fans",
+	}, &updated)
+	if d := updated.Draft; d.UseCase != started.Draft.UseCase || d.Suggestion == nil || d.Suggestion.Symbol != "TOUR" || d.Name != "Touring Fans" || d.Step != 2 {
+		t.Errorf("updated draft = %+v, want the suggestion added to the use case", d)
+	}
+	if w := doRequestAs(t, r, token, http.MethodPatch, base, map[string]string{"whitepaper": strings.Repeat("x", services.MaxWhitepaperLength+1)}, &failed); w.Code != http.StatusBadRequest {
+		t.Errorf("oversized whitepaper = %d, want 400", w.Code)
+	}
+
+	// Drafts are private to their owner
+	if w := doRequestAs(t, r, other, http.MethodGet, base, nil, &failed); w.Code != http.StatusNotFound || failed.Error.Code != "draft_not_found" {
+		t.Errorf("draft of another wallet = %d %+v, want 404 draft_not_found", w.Code, failed.Error)
+	}
+	if w := doRequestAs(t, r, other, http.MethodPatch, base, map[string]string{"name": "Stolen"}, &failed); w.Code != http.StatusNotFound {
+		t.Errorf("update by another wallet = %d, want 404", w.Code)
+	}
+	var listed draftList
+	doRequestAs(t, r, other, http.MethodGet, "/api/v1/drafts", nil, &listed)
+	if len(listed.Drafts) != 0 {
+		t.Errorf("drafts of another wallet = %+v, want none", listed.Drafts)
+	}
+
+	// Publishing validates the draft like a creation
+	if w := doRequestAs(t, r, token, http.MethodPost, base+"/publish", nil, &failed); w.Code != http.StatusBadRequest || failed.Error.Details[0].Field != "totalSupply" {
+		t.Fatalf("publish without a supply = %d %+v, want 400 on totalSupply", w.Code, failed.Error)
+	}
+
+	doRequestAs(t, r, token, http.MethodPatch, base, map[string]string{"totalSupply": "1,000"}, &updated)
+	var published struct {
+		DraftID string       `json:"draftId"`
+		AssetID string       `json:"assetId"`
+		Asset   client.Asset `json:"asset"`
+	}
+	if w := doRequestAs(t, r, token, http.MethodPost, base+"/publish", nil, &published); w.Code != http.StatusCreated {
+		t.Fatalf("publish = %d: %s", w.Code, w.Body.String())
+	}
+	if a := published.Asset; a.ID == "" || a.Name != "Touring Fans" || a.Symbol != "TOUR" || a.CreatorAddress != owner || a.TotalSupply != "1000000000000000000000" {
+		t.Errorf("published asset = %+v, want the drafted one owned by %s", a, owner)
+	}
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/assets/"+published.AssetID, nil, nil); w.Code != http.StatusOK {
+		t.Errorf("published asset lookup = %d, want 200", w.Code)
+	}
+
+	// A published draft is kept, linked to its asset, but frozen
+	if w := doRequestAs(t, r, token, http.MethodPost, base+"/publish", nil, &failed); w.Code != http.StatusConflict || failed.Error.Code != "draft_published" {
+		t.Errorf("second publish = %d %+v, want 409 draft_published", w.Code, failed.Error)
+	}
+	if w := doRequestAs(t, r, token, http.MethodPatch, base, map[string]string{"name": "Renamed"}, &failed); w.Code != http.StatusConflict {
+		t.Errorf("update after publishing = %d, want 409", w.Code)
+	}
+	doRequestAs(t, r, token, http.MethodGet, "/api/v1/drafts", nil, &listed)
+	if len(listed.Drafts) != 1 || listed.Drafts[0].AssetID != published.AssetID {
+		t.Errorf("drafts = %+v, want the published one linked to %s", listed.Drafts, published.AssetID)
+	}
+
+	if w := doRequestAs(t, r, token, http.MethodDelete, base, nil, nil); w.Code != http.StatusOK {
+		t.Errorf("delete = %d, want 200", w.Code)
+	}
+	if w := doRequestAs(t, r, token, http.MethodGet, base, nil, nil); w.Code != http.StatusNotFound {
+		t.Errorf("deleted draft = %d, want 404", w.Code)
+	}
+}
+
+func TestAssetWhitepaper(t *testing.T) {
+	r := newTestRouter(t, testConfig(t))
+	token := signIn(t, r, testOwnerKey)
+	owner := parseKey(t, testOwnerKey).Address().Hex()
+
+	type whitepaperResponse struct {
+		Whitepaper services.Whitepaper `json:"whitepaper"`
+	}
+	type diffResponse struct {
+		Diff services.WhitepaperDiff `json:"diff"`
+	}
+	sha := func(content string) string {
+		sum := sha256.Sum256([]byte(content))
+		return hex.EncodeToString(sum[:])
+	}
+
+	var created assetResponse
+	doRequestAs(t, r, token, http.MethodPost, "/api/v1/assets/create", map[string]string{
+		"name": "Touring Fans", "symbol": "TOUR", "totalSupply": "100",
+	}, &created)
+	base := "/api/v1/assets/" + created.AssetID + "/whitepaper"
+
+	var failed errorResponse
+	if w := doRequest(t, r, http.MethodGet, base, nil, &failed); w.Code != http.StatusNotFound || failed.Error.Code != "whitepaper_not_found" {
+		t.Errorf("whitepaper before any = %d %+v, want 404 whitepaper_not_found", w.Code, failed.Error)
+	}
+	if w := doRequest(t, r, http.MethodPut, base, map[string]string{"content": "# Stolen"}, &failed); w.Code != http.StatusUnauthorized {
+		t.Errorf("publish without a session = %d, want 401", w.Code)
+	}
+	if w := doRequestAs(t, r, signIn(t, r, testOtherKey), http.MethodPut, base, map[string]string{"content": "# Stolen"}, &failed); w.Code != http.StatusForbidden {
+		t.Errorf("publish by another wallet = %d, want 403", w.Code)
+	}
+	if w := doRequestAs(t, r, token, http.MethodPut, base, map[string]string{"content": "  \n"}, &failed); w.Code != http.StatusBadRequest {
+		t.Errorf("publish of a blank whitepaper = %d, want 400", w.Code)
+	}
+
+	first := "# Touring Fans\n\nTokens for the fans on the road.\n"
+	second := "# Touring Fans\n\nTokens for the fans on the road.\n\n## Roadmap\n\nA world tour.\n"
+	var published assetResponse
+	for i, content := range []string{first, first, second} {
+		if w := doRequestAs(t, r, token, http.MethodPut, base, map[string]string{"content": content}, &published); w.Code != http.StatusOK {
+			t.Fatalf("publish %d = %d: %s", i, w.Code, w.Body.String())
+		}
+	}
+	// Publishing the same content twice keeps a single version
+	if wp := published.Asset.Whitepaper; wp == nil || wp.Version != 2 || wp.ContentHash != sha(second) || wp.PublishedAt == "" {
+		t.Fatalf("published whitepaper = %+v, want version 2 hashing the second content", wp)
+	}
+	var asset assetResponse
+	doRequest(t, r, http.MethodGet, "/api/v1/assets/"+created.AssetID, nil, &asset)
+	if wp := asset.Asset.Whitepaper; wp == nil || wp.ContentHash != sha(second) {
+		t.Errorf("asset whitepaper = %+v, want the hash of the second content", wp)
+	}
+
+	var latest, older whitepaperResponse
+	doRequest(t, r, http.MethodGet, base, nil, &latest)
+	if wp := latest.Whitepaper; wp.Version != 2 || wp.Versions != 2 || !wp.Published || wp.Content != second || wp.ContentHash != sha(second) || wp.Author != owner {
+		t.Errorf("latest whitepaper = %+v, want the published second version", wp)
+	}
+	doRequest(t, r, http.MethodGet, base+"?version=1", nil, &older)
+	if wp := older.Whitepaper; wp.Version != 1 || wp.Published || wp.Content != first {
+		t.Errorf("whitepaper version 1 = %+v, want the unpublished first version", wp)
+	}
+	if w := doRequest(t, r, http.MethodGet, base+"?version=3", nil, &failed); w.Code != http.StatusNotFound {
+		t.Errorf("missing version = %d, want 404", w.Code)
+	}
+	if w := doRequest(t, r, http.MethodGet, base+"?version=latest", nil, &failed); w.Code != http.StatusBadRequest || failed.Error.Details[0].Field != "version" {
+		t.Errorf("invalid version = %d %+v, want 400 on version", w.Code, failed.Error)
+	}
+
+	var diff diffResponse
+	doRequest(t, r, http.MethodGet, base+"/diff", nil, &diff)
+	if d := diff.Diff; d.From != 1 || d.To != 2 || d.Insertions != 4 || d.Deletions != 0 || !strings.Contains(d.Diff, "+## Roadmap\n") {
+		t.Errorf("diff = %+v, want the roadmap added from version 1 to 2", d)
+	}
+	doRequest(t, r, http.MethodGet, base+"/diff?from=2&to=1", nil, &diff)
+	if d := diff.Diff; d.Deletions != 4 || !strings.Contains(d.Diff, "--- version 2\n+++ version 1\n") {
+		t.Errorf("reverse diff = %+v, want the roadmap removed", d)
+	}
+
+	// Concurrent publishes of the same content add a single version
+	third := second + "\n## Team\n\nThe fans.\n"
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			doRequestAs(t, r, token, http.MethodPut, base, map[string]string{"content": third}, nil)
+		}()
+	}
+	wg.Wait()
+	doRequest(t, r, http.MethodGet, base, nil, &latest)
+	if wp := latest.Whitepaper; wp.Version != 3 || wp.Versions != 3 || wp.Content != third {
+		t.Errorf("whitepaper after concurrent publishes = %+v, want version 3 of the third content", wp)
+	}
+
+	// A draft keeps every whitepaper it saved and publishes the last one
+	var draft struct {
+		DraftID string `json:"draftId"`
+	}
+	doRequestAs(t, r, token, http.MethodPost, "/api/v1/drafts", map[string]string{
+		"name": "Encore", "symbol": "ENCR", "totalSupply": "10", "whitepaper": first,
+	}, &draft)
+	drafted := "/api/v1/drafts/" + draft.DraftID
+	doRequestAs(t, r, token, http.MethodPatch, drafted, map[string]string{"whitepaper": second}, nil)
+	doRequestAs(t, r, token, http.MethodPatch, drafted, map[string]string{"description": "Encore!"}, nil)
+
+	var drafts whitepaperResponse
+	doRequestAs(t, r, token, http.MethodGet, drafted+"/whitepaper", nil, &drafts)
+	if wp := drafts.Whitepaper; wp.Versions != 2 || wp.Content != second || wp.Published {
+		t.Errorf("draft whitepaper = %+v, want the second of two versions", wp)
+	}
+	doRequestAs(t, r, token, http.MethodGet, drafted+"/whitepaper/diff", nil, &diff)
+	if diff.Diff.Insertions != 4 {
+		t.Errorf("draft diff = %+v, want the roadmap added", diff.Diff)
+	}
+	if w := doRequestAs(t, r, signIn(t, r, testOtherKey), http.MethodGet, drafted+"/whitepaper", nil, &failed); w.Code != http.StatusNotFound || failed.Error.Code != "draft_not_found" {
+		t.Errorf("draft whitepaper of another wallet = %d %+v, want 404 draft_not_found", w.Code, failed.Error)
+	}
+
+	var fromDraft assetResponse
+	if w := doRequestAs(t, r, token, http.MethodPost, drafted+"/publish", nil, &fromDraft); w.Code != http.StatusCreated {
+		t.Fatalf("publish draft = %d: %s", w.Code, w.Body.String())
+	}
+	if wp := fromDraft.Asset.Whitepaper; wp == nil || wp.Version != 1 || wp.ContentHash != sha(second) {
+		t.Errorf("whitepaper of the published draft = %+v, want version 1 of the last draft whitepaper", wp)
+	}
+	doRequest(t, r, http.MethodGet, "/api/v1/assets/"+fromDraft.AssetID+"/whitepaper", nil, &latest)
+	if latest.Whitepaper.Content != second || !latest.Whitepaper.Published {
+		t.Errorf("asset whitepaper from draft = %+v, want the published draft whitepaper", latest.Whitepaper)
+	}
+}
+
+func TestAssetIconRoute(t *testing.T) {
+	r := newTestRouter(t, testConfig(t))
+	token := signIn(t, r, testOwnerKey)
+
+	var created assetResponse
+	doRequestAs(t, r, token, http.MethodPost, "/api/v1/assets/create", map[string]string{
+		"name":        "Icon Fans",
+		"symbol":      "ICON",
+		"totalSupply": "1000",
+		"iconData":    testPNG(t, 512, 256),
+	}, &created)
+
+	w := doRequest(t, r, http.MethodGet, created.IconURL, nil, nil)
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("get icon = %d %s, want 200 image/png", w.Code, w.Header().Get("Content-Type"))
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("icon response has no ETag")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, created.IconURL, nil)
+	req.Header.Set("If-None-Match", etag)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("conditional get = %d, want 304", rec.Code)
+	}
+
+	w = doRequest(t, r, http.MethodGet, created.IconURL+"?size=thumb", nil, nil)
+	thumb, err := png.DecodeConfig(w.Body)
+	if err != nil {
+		t.Fatalf("decode thumbnail: %v", err)
+	}
+	if thumb.Width != 128 || thumb.Height != 64 {
+		t.Errorf("thumbnail is %dx%d, want 128x64", thumb.Width, thumb.Height)
+	}
+
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/assets/1/icon", nil, nil); w.Code != http.StatusNotFound {
+		t.Errorf("get icon of asset without one = %d, want 404", w.Code)
+	}
+}
+
+func TestAssetRoutesLiveMode(t *testing.T) {
+	fake, server := newFakeExSat(t, client.Asset{ID: "exsat_0", Name: "Upstream", Symbol: "UP", CreatedAt: "2023-01-01T00:00:00Z"})
+	fake.pageSize = 1 // the sync must follow exSat cursors
+	cfg := testConfig(t)
+	cfg.ExSat.APIURL = server.URL
+	cfg.ExSat.APIKey = testExSatAPIKey
+	r, app := newTestApp(t, cfg)
+	token := signIn(t, r, testOwnerKey)
+
+	var created assetResponse
+	w := doRequestAs(t, r, token, http.MethodPost, "/api/v1/assets/create", map[string]string{
+		"name":        "Live Fans",
+		"symbol":      "LIVE",
+		"totalSupply": "5000",
+	}, &created)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create = %d, want 201: %s", w.Code, w.Body.String())
+	}
+	if created.AssetID != "exsat_2" {
+		t.Errorf("assetId = %q, want the exSat ID exsat_2", created.AssetID)
+	}
+
+	// Listings are served from the store, which the sync fills from exSat
+	if err := app.assetSync.SyncOnce(context.Background()); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	calls := fake.callCount()
+	var list assetsResponse
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/assets/", nil, &list); w.Code != http.StatusOK {
+		t.Fatalf("list = %d, want 200", w.Code)
+	}
+	if len(list.Assets) != 2 {
+		t.Fatalf("list returned %d assets, want 2 (no seeded mock assets in live mode)", len(list.Assets))
+	}
+	if fake.callCount() != calls {
+		t.Errorf("list made %d exSat calls, want none", fake.callCount()-calls)
+	}
+
+	var fetched assetResponse
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/assets/exsat_0", nil, &fetched); w.Code != http.StatusOK {
+		t.Fatalf("get = %d, want 200", w.Code)
+	}
+	if fetched.Asset.Name != "Upstream" {
+		t.Errorf("fetched asset name = %q, want Upstream", fetched.Asset.Name)
+	}
+
+	var missing errorResponse
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/assets/exsat_404", nil, &missing); w.Code != http.StatusNotFound {
+		t.Errorf("get asset unknown to exSat = %d, want 404", w.Code)
+	}
+	if missing.Error.Code != "asset_not_found" || strings.Contains(missing.Error.Message, "success") {
+		t.Errorf("missing asset error = %+v, want asset_not_found without the upstream body", missing.Error)
+	}
+
+	// A refresh from exSat keeps a status change made while it was running
+	held := make(chan struct{})
+	fake.mu.Lock()
+	fake.held = held
+	upstream := fake.assets[created.AssetID]
+	upstream.Description = "Changed on exSat"
+	fake.assets[created.AssetID] = upstream
+	fake.mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		doRequest(t, r, http.MethodGet, "/api/v1/assets/"+created.AssetID, nil, nil)
+	}()
+	<-held
+	if w := doRequestAs(t, r, token, http.MethodPost, "/api/v1/assets/"+created.AssetID+"/pause", map[string]string{}, nil); w.Code != http.StatusOK {
+		t.Fatalf("pause = %d: %s", w.Code, w.Body.String())
+	}
+	held <- struct{}{}
+	<-done
+	app.assetSync.SyncOnce(context.Background())
+	if stored, err := app.assets.Get(created.AssetID); err != nil || stored.Status != "paused" || stored.Description != upstream.Description {
+		t.Errorf("asset after a refresh racing its pause = %+v, %v, want paused with the exSat description", stored, err)
+	}
+
+	// Once exSat is down, the stored copy is still served
+	before := fake.callCount()
+	server.Close()
+	if w := doRequest(t, r, http.MethodGet, "/api/v1/assets/"+created.AssetID, nil, &fetched); w.Code != http.StatusOK {
+		t.Fatalf("get with exSat down = %d, want 200", w.Code)
+	}
+	if fetched.Asset.Symbol != "LIVE" || fake.callCount() != before {
+		t.Errorf("fetched %+v after %d new calls, want stored LIVE asset", fetched.Asset, fake.callCount()-before)
+	}
+}
+
+func TestExSatReadTimeout(t *testing.T) {
+	fake, server := newFakeExSat(t)
+	fake.delay = time.Second
+	cfg := testConfig(t)
+	cfg.ExSat.APIURL = server.URL
+	cfg.ExSat.APIKey = testExSatAPIKey
+	cfg.Timeouts.ExSatRead = config.Duration{Duration: 50 * time.Millisecond}
+	r := newTestRouter(t, cfg)
+
+	start := time.Now()
+	var timeout errorResponse
+	w := doRequest(t, r, http.MethodGet, "/api/v1/assets/exsat_0", nil, &timeout)
+	if w.Code != http.StatusGatewayTimeout || timeout.Error.Code != "timeout" {
+		t.Fatalf("get with slow exSat = %d %+v, want 504 timeout", w.Code, timeout.Error)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("get took %s, want the 50ms read timeout to apply", elapsed)
+	}
+}
+
+func TestAIRoutesMockMode(t *testing.T) {
+	r := newTestRouter(t, testConfig(t))
+
+	var whitepaper struct {
+		Content  string `json:"content"`
+		MockMode bool   `json:"mockMode"`
+	}
+	w := doRequest(t, r, http.MethodPost, "/api/v1/ai/generate-whitepaper", map[string]string{
+		"name": "Moon Fans", "symbol": "MOON", "useCase": "celebrate anniversaries",
+	}, &whitepaper)
+	if w.Code != http.StatusOK {
+		t.Fatalf("generate whitepaper = %d, want 200", w.Code)
+	}
+	if !whitepaper.MockMode || !strings.Contains(whitepaper.Content, "# Moon Fans Whitepaper") {
+		t.Errorf("whitepaper = %+v, want mock content for Moon Fans", whitepaper)
+	}
+
+	if w := doRequest(t, r, http.MethodPost, "/api/v1/ai/generate-whitepaper", map[string]string{"name": "x"}, nil); w.Code != http.StatusBadRequest {
+		t.Errorf("generate whitepaper without symbol = %d, want 400", w.Code)
+	}
+
+	var suggestions struct {
+		Suggestions []struct {
+			Rank   int    `json:"rank"`
+			Symbol string `json:"symbol"`
+		} `json:"suggestions"`
+	}
+	w = doRequest(t, r, http.MethodPost, "/api/v1/ai/token-suggestion", map[string]string{"useCase": "k"}, &suggestions)
+	if w.Code != http.StatusOK {
+		t.Fatalf("token suggestion = %d, want 200", w.Code)
+	}
+	if len(suggestions.Suggestions) < 2 || suggestions.Suggestions[0].Rank != 1 {
+		t.Errorf("suggestions = %+v, want several ranked suggestions", suggestions.Suggestions)
+	}
+
+	w = doRequest(t, r, http.MethodGet, "/api/v1/ai/generate-whitepaper/stream?name=Moon&symbol=MOON&useCase=fans", nil, nil)
+	body := w.Body.String()
+	if !strings.Contains(body, "event:chunk") || !strings.Contains(body, "event:done") {
+		t.Errorf("stream body lacks chunk or done events:\n%s", body)
+	}
+}
+
+func TestAIRoutesLiveMode(t *testing.T) {
+	valid := `{"suggestions":[
+		{"name":"Moon Token","symbol":"moon","description":"For moon fans","marketPotential":"High"},
+		{"name":"Star Token","symbol":"STAR","description":"For star fans","useCase":"stars","marketPotential":"Medium"}
+	]}`
+	cfg := testConfig(t)
+	cfg.AI.Provider = "openai-compatible"
+	cfg.AI.BaseURL = newFakeOpenAI(t, valid).URL + "/v1"
+	r := newTestRouter(t, cfg)
+
+	var suggestions struct {
+		Provider    string `json:"provider"`
+		Suggestions []struct {
+			Rank      int    `json:"rank"`
+			Symbol    string `json:"symbol"`
+			UseCase   string `json:"useCase"`
+			Available bool   `json:"available"`
+		} `json:"suggestions"`
+	}
+	w := doRequest(t, r, http.MethodPost, "/api/v1/ai/token-suggestion", map[string]string{"useCase": "moon fandom"}, &suggestions)
+	if w.Code != http.StatusOK {
+		t.Fatalf("token suggestion = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	if suggestions.Provider != "openai-compatible" || len(suggestions.Suggestions) != 2 {
+		t.Fatalf("response = %+v, want 2 suggestions from openai-compatible", suggestions)
+	}
+	first := suggestions.Suggestions[0]
+	if first.Rank != 1 || first.Symbol != "MOON" || first.UseCase != "moon fandom" || !first.Available {
+		t.Errorf("first suggestion = %+v, want rank 1, available symbol MOON and the request use case", first)
+	}
+
+	w = doRequest(t, r, http.MethodPost, "/api/v1/ai/generate-whitepaper/stream", map[string]string{
+		"name": "Moon", "symbol": "MOON", "useCase": "fans",
+	}, nil)
+	body := w.Body.String()
+	if !strings.Contains(body, "Streamed by a fake model") || !strings.Contains(body, `"totalTokens":18`) {
+		t.Errorf("stream body lacks fake content or usage:\n%s", body)
+	}
+
+	invalid := testConfig(t)
+	invalid.AI.Provider = "openai-compatible"
+	invalid.AI.BaseURL = newFakeOpenAI(t, `{"suggestions":[{"name":"No Symbol"}]}`).URL + "/v1"
+	r = newTestRouter(t, invalid)
+	if w := doRequest(t, r, http.MethodPost, "/api/v1/ai/token-suggestion", map[string]string{"useCase": "x"}, nil); w.Code != http.StatusBadGateway {
+		t.Errorf("token suggestion with invalid model reply = %d, want 502", w.Code)
+	}
+}
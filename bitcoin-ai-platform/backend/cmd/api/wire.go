@@ -0,0 +1,189 @@
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"log"
+
+	"github.com/yourusername/bitcoin-ai-platform/internal/config"
+	"github.com/yourusername/bitcoin-ai-platform/internal/handlers"
+	"github.com/yourusername/bitcoin-ai-platform/internal/repository"
+	"github.com/yourusername/bitcoin-ai-platform/internal/services"
+	"github.com/yourusername/bitcoin-ai-platform/internal/storage"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/address"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/ai"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/evm"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
+)
+
+// app holds the wired handlers and the resources to release on shutdown
+type app struct {
+	authHandler       *handlers.AuthHandler
+	assetHandler      *handlers.AssetHandler
+	aiHandler         *handlers.AIHandler
+	chainHandler      *handlers.ChainHandler
+	walletHandler     *handlers.WalletHandler
+	draftHandler      *handlers.DraftHandler
+	whitepaperHandler *handlers.WhitepaperHandler
+	assetSync         *services.AssetSync
+	indexer           *services.HolderIndexer
+	deployer          *services.TokenDeployer // nil when deployment is disabled
+	assets            repository.AssetRepository
+	exSat             *client.ExSatClient
+	mockMode          bool
+}
+
+// newApp builds every service and handler from the configuration. It
+// fails when the configured storage cannot be opened rather than run on
+// storage that loses everything on restart.
+func newApp(cfg *config.Config) (*app, error) {
+	if cfg.ExSat.MockMode() {
+		log.Println("Warning: EXSAT_API_KEY not set. Running in mock mode.")
+	}
+
+	assets, err := newAssetRepository(cfg.Storage.AssetDBPath)
+	if err != nil {
+		return nil, err
+	}
+	iconStore, err := storage.NewFileBlobStore(cfg.Storage.IconDir)
+	if err != nil {
+		assets.Close()
+		return nil, fmt.Errorf("unable to open icon storage at %s: %w", cfg.Storage.IconDir, err)
+	}
+	icons := services.NewIconService(iconStore)
+	exSat := client.NewExSatClient(cfg.ExSat.APIURL, cfg.ExSat.APIKey)
+	exSat.ReadTimeout = cfg.Timeouts.ExSatRead.Duration
+	exSat.WriteTimeout = cfg.Timeouts.ExSatWrite.Duration
+	exSat.Retry.MaxAttempts = cfg.ExSat.MaxAttempts
+	exSat.Breaker = client.NewCircuitBreaker(cfg.ExSat.BreakerThreshold, cfg.ExSat.BreakerCooldown.Duration)
+
+	chain := evm.NewClient(cfg.Chain.RPCURL)
+	chain.Timeout = cfg.Timeouts.ChainRead.Duration
+	deployer := newTokenDeployer(cfg.Chain, chain, assets)
+	network, err := address.ParseNetwork(cfg.ExSat.BitcoinNetwork)
+	if err != nil {
+		log.Fatalf("Invalid Bitcoin network: %s", err.Error())
+	}
+	symbols := services.NewSymbolRegistry(assets, cfg.Assets.BlockedSymbols)
+	symbols.ReservationTTL = cfg.Assets.SymbolReservation.Duration
+	assetService := services.NewAssetService(exSat, assets, icons, deployer, symbols, network, cfg.Chain.ExplorerURL, cfg.ExSat.MockMode())
+
+	whitepaperService := services.NewWhitepaperService(assets, assetService)
+	draftService := services.NewDraftService(assets, assetService, whitepaperService)
+
+	authHandler := handlers.NewAuthHandler(newAuthService(cfg.Auth, cfg.Chain.ChainID))
+
+	aiService := services.NewAIService(newAIProvider(cfg.AI, cfg.Timeouts), symbols)
+
+	chainService := services.NewChainService(chain, assets, cfg.Chain.Tokens)
+	portfolioService := services.NewPortfolioService(assets, network, cfg.Chain.ExplorerURL)
+	indexer := services.NewHolderIndexer(chain, assets)
+	indexer.Confirmations = uint64(cfg.Chain.Confirmations)
+	indexer.BlockRange = uint64(cfg.Chain.LogBlockRange)
+	indexer.StartBlock = cfg.Chain.IndexStartBlock
+
+	return &app{
+		authHandler:       authHandler,
+		assetHandler:      handlers.NewAssetHandler(assetService, authHandler.RequireAuth()),
+		aiHandler:         handlers.NewAIHandler(aiService),
+		chainHandler:      handlers.NewChainHandler(chainService),
+		walletHandler:     handlers.NewWalletHandler(portfolioService),
+		draftHandler:      handlers.NewDraftHandler(draftService, authHandler.RequireAuth()),
+		whitepaperHandler: handlers.NewWhitepaperHandler(whitepaperService, authHandler.RequireAuth()),
+		assetSync:         services.NewAssetSync(exSat, assets),
+		indexer:           indexer,
+		deployer:          deployer,
+		assets:            assets,
+		exSat:             exSat,
+		mockMode:          cfg.ExSat.MockMode(),
+	}, nil
+}
+
+// close releases the resources held by the app
+func (a *app) close() {
+	if err := a.assets.Close(); err != nil {
+		log.Printf("Warning: Failed to close asset store: %v", err)
+	}
+}
+
+// newAssetRepository opens the bbolt asset store at path. Only "memory"
+// selects the in-memory store.
+func newAssetRepository(path string) (repository.AssetRepository, error) {
+	if path == "memory" {
+		return repository.NewMemoryAssetRepository(), nil
+	}
+
+	repo, err := repository.NewBoltAssetRepository(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open asset store at %s: %w", path, err)
+	}
+	return repo, nil
+}
+
+// newAuthService creates the Sign-In with Ethereum service. Without a
+// configured secret sessions are signed with a random one and do not
+// survive a restart.
+func newAuthService(cfg config.AuthConfig, chainID int64) *services.AuthService {
+	secret := []byte(cfg.SessionSecret)
+	if len(secret) == 0 {
+		log.Println("Warning: SESSION_SECRET not set. Sessions end when the server restarts.")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Fatalf("Unable to generate a session secret: %s", err.Error())
+		}
+	}
+
+	auth := services.NewAuthService(cfg.Domain, chainID, secret)
+	auth.SessionTTL = cfg.SessionTTL.Duration
+	auth.NonceTTL = cfg.NonceTTL.Duration
+	return auth
+}
+
+// newTokenDeployer creates the deployer of fan token contracts, or returns
+// nil when no factory is configured
+func newTokenDeployer(cfg config.ChainConfig, chain *evm.Client, assets repository.AssetRepository) *services.TokenDeployer {
+	if cfg.Factory == "" {
+		return nil
+	}
+	factory, err := evm.ParseAddress(cfg.Factory)
+	if err != nil {
+		log.Printf("Warning: Invalid factory address: %v. Token deployment disabled.", err)
+		return nil
+	}
+
+	// Without a server key tokens are only deployed from owners' wallets
+	var key *evm.PrivateKey
+	if cfg.DeployerKey != "" {
+		if key, err = evm.ParsePrivateKey(cfg.DeployerKey); err != nil {
+			log.Printf("Warning: Invalid deployer key: %v. Server-signed deployment disabled.", err)
+			key = nil
+		}
+	}
+
+	deployer := services.NewTokenDeployer(chain, assets, key, factory, cfg.ChainID)
+	deployer.Confirmations = uint64(cfg.Confirmations)
+	deployer.MetadataBaseURL = cfg.MetadataBaseURL
+	if key != nil {
+		log.Printf("Deploying fan tokens through factory %s from %s", factory.Hex(), key.Address().Hex())
+	} else {
+		log.Printf("Deploying fan tokens through factory %s from owner wallets", factory.Hex())
+	}
+	return deployer
+}
+
+// newAIProvider creates the configured AI provider, falling back to the mock
+func newAIProvider(cfg config.AIConfig, timeouts config.TimeoutConfig) ai.Provider {
+	provider, err := ai.NewProvider(ai.ProviderConfig{
+		Name:          cfg.Provider,
+		APIKey:        cfg.APIKey,
+		BaseURL:       cfg.BaseURL,
+		Model:         cfg.Model,
+		Timeout:       timeouts.AIGenerate.Duration,
+		StreamTimeout: timeouts.AIStream.Duration,
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to initialize AI provider: %v. Using mock mode.", err)
+		return ai.NewMockProvider()
+	}
+	return provider
+}
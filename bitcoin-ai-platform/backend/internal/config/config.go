@@ -0,0 +1,608 @@
+package config
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Supported values of Config.Env
+const (
+	EnvDevelopment = "development"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+	EnvTest        = "test"
+)
+
+// AI provider names, mirrored from pkg/ai to keep this package dependency free
+const (
+	providerOpenAI           = "openai"
+	providerOpenAICompatible = "openai-compatible"
+	providerMock             = "mock"
+)
+
+// redacted replaces secrets in printed configurations
+const redacted = "[REDACTED]"
+
+// Config is the complete application configuration
+type Config struct {
+	Env      string        `yaml:"env" toml:"env"`
+	Port     string        `yaml:"port" toml:"port"`
+	ExSat    ExSatConfig   `yaml:"exsat" toml:"exsat"`
+	AI       AIConfig      `yaml:"ai" toml:"ai"`
+	Chain    ChainConfig   `yaml:"chain" toml:"chain"`
+	Auth     AuthConfig    `yaml:"auth" toml:"auth"`
+	Assets   AssetsConfig  `yaml:"assets" toml:"assets"`
+	Storage  StorageConfig `yaml:"storage" toml:"storage"`
+	Timeouts TimeoutConfig `yaml:"timeouts" toml:"timeouts"`
+}
+
+// ExSatConfig configures the exSat API client
+type ExSatConfig struct {
+	APIURL string `yaml:"apiUrl" toml:"apiUrl"`
+	APIKey string `yaml:"apiKey" toml:"apiKey"`
+
+	// MaxAttempts bounds retries of failed calls, 1 disables them
+	MaxAttempts int `yaml:"maxAttempts" toml:"maxAttempts"`
+	// BreakerThreshold consecutive failures open the circuit breaker for BreakerCooldown
+	BreakerThreshold int      `yaml:"breakerThreshold" toml:"breakerThreshold"`
+	BreakerCooldown  Duration `yaml:"breakerCooldown" toml:"breakerCooldown"`
+	// SyncInterval is the pause between copies of the exSat asset listing
+	// into the asset store, 0 disables the sync
+	SyncInterval Duration `yaml:"syncInterval" toml:"syncInterval"`
+
+	// BitcoinNetwork is the network Bitcoin owner addresses must belong
+	// to: mainnet, testnet or signet
+	BitcoinNetwork string `yaml:"bitcoinNetwork" toml:"bitcoinNetwork"`
+}
+
+// AIConfig configures the AI provider
+type AIConfig struct {
+	Provider string `yaml:"provider" toml:"provider"` // openai, openai-compatible or mock
+	APIKey   string `yaml:"apiKey" toml:"apiKey"`
+	BaseURL  string `yaml:"baseUrl" toml:"baseUrl"`
+	Model    string `yaml:"model" toml:"model"`
+}
+
+// ChainConfig configures access to the exSat EVM chain
+type ChainConfig struct {
+	RPCURL  string `yaml:"rpcUrl" toml:"rpcUrl"`
+	ChainID int64  `yaml:"chainId" toml:"chainId"`
+	// ExplorerURL is the block explorer transactions are linked to
+	ExplorerURL string `yaml:"explorerUrl" toml:"explorerUrl"`
+	// Tokens are ERC-20 contracts reported by wallet balances next to the
+	// tokens issued through FansMint
+	Tokens []string `yaml:"tokens" toml:"tokens"`
+
+	// IndexInterval is the pause between holder indexing passes, 0 disables
+	// the indexer. It defaults to 0 in mock mode unless RPCURL is set.
+	IndexInterval Duration `yaml:"indexInterval" toml:"indexInterval"`
+	// Confirmations is how many blocks behind the head indexing stays
+	Confirmations int `yaml:"confirmations" toml:"confirmations"`
+	// LogBlockRange is the widest block span of one eth_getLogs call
+	LogBlockRange int `yaml:"logBlockRange" toml:"logBlockRange"`
+	// IndexStartBlock is where the indexing of a new asset starts
+	IndexStartBlock uint64 `yaml:"indexStartBlock" toml:"indexStartBlock"`
+
+	// Factory is the FansMint token factory contract. When set, fan tokens
+	// are deployed through it: by the server when DeployerKey is set, and
+	// from the owner's wallet through the prepare and submit routes.
+	Factory string `yaml:"factory" toml:"factory"`
+	// DeployerKey is the hex private key of the server deploying account;
+	// empty leaves deployment to the owners' wallets
+	DeployerKey string `yaml:"deployerKey" toml:"deployerKey"`
+	// MetadataBaseURL followed by the asset ID is the token metadata URI
+	MetadataBaseURL string `yaml:"metadataBaseUrl" toml:"metadataBaseUrl"`
+	// DeployPollInterval is the pause between checks of pending deployments
+	DeployPollInterval Duration `yaml:"deployPollInterval" toml:"deployPollInterval"`
+}
+
+// AuthConfig configures Sign-In with Ethereum and browser access
+type AuthConfig struct {
+	// Domain is the host, with its port when not the default, that SIWE
+	// messages must be issued for: the frontend's
+	Domain string `yaml:"domain" toml:"domain"`
+	// SessionSecret signs session tokens. When empty a random secret is
+	// generated at startup, outside of staging and production.
+	SessionSecret string   `yaml:"sessionSecret" toml:"sessionSecret"`
+	SessionTTL    Duration `yaml:"sessionTtl" toml:"sessionTtl"`
+	NonceTTL      Duration `yaml:"nonceTtl" toml:"nonceTtl"`
+	// AllowedOrigins are the browser origins allowed by CORS
+	AllowedOrigins []string `yaml:"allowedOrigins" toml:"allowedOrigins"`
+}
+
+// minSessionSecret is the shortest accepted session secret, in bytes
+const minSessionSecret = 32
+
+// AssetsConfig configures the rules new assets follow
+type AssetsConfig struct {
+	// BlockedSymbols may not be used by any asset, whatever their case
+	BlockedSymbols []string `yaml:"blockedSymbols" toml:"blockedSymbols"`
+	// SymbolReservation is how long a symbol stays reserved for the
+	// creation that claimed it
+	SymbolReservation Duration `yaml:"symbolReservation" toml:"symbolReservation"`
+}
+
+// StorageConfig configures where FansMint keeps its own data
+type StorageConfig struct {
+	AssetDBPath string `yaml:"assetDbPath" toml:"assetDbPath"` // bbolt file, or "memory"
+	IconDir     string `yaml:"iconDir" toml:"iconDir"`
+}
+
+// TimeoutConfig holds the deadline of each kind of outbound operation
+type TimeoutConfig struct {
+	ExSatRead  Duration `yaml:"exsatRead" toml:"exsatRead"`
+	ExSatWrite Duration `yaml:"exsatWrite" toml:"exsatWrite"`
+	ChainRead  Duration `yaml:"chainRead" toml:"chainRead"`
+	AIGenerate Duration `yaml:"aiGenerate" toml:"aiGenerate"`
+	AIStream   Duration `yaml:"aiStream" toml:"aiStream"`
+}
+
+// MockMode returns true when no exSat API key is configured
+func (c ExSatConfig) MockMode() bool {
+	return c.APIKey == ""
+}
+
+// Default returns the configuration used when nothing else is set
+func Default() *Config {
+	return &Config{
+		Env:  EnvDevelopment,
+		Port: "8080",
+		ExSat: ExSatConfig{
+			APIURL:           "https://api.exsat.network",
+			MaxAttempts:      3,
+			BreakerThreshold: 5,
+			BreakerCooldown:  Duration{30 * time.Second},
+			SyncInterval:     Duration{time.Minute},
+			BitcoinNetwork:   "testnet",
+		},
+		Chain: ChainConfig{
+			RPCURL:      "https://evm-tst3.exsat.network",
+			ChainID:     839999,
+			ExplorerURL: "https://scan-testnet.exsat.network",
+			Tokens: []string{
+				"0x8266f2fbc720012e5Ac038aD3dbb29d2d613c459", // XSAT
+				"0x4aa4365da82ACD46e378A6f3c92a863f3e763d34", // XBTC
+				"0xA7366BE06B2867a207c0C4F37481fF7B0cE62D87", // USDT
+				"0x893AfC357b656EdD4F0c028670516F846FE89CFb", // USDC
+				"0x81e1Da8BDEbC4686B9025839c72c7FB0229F180C", // WETH
+			},
+			IndexInterval: Duration{15 * time.Second},
+			Confirmations: 3,
+			LogBlockRange: 5000,
+
+			DeployPollInterval: Duration{5 * time.Second},
+		},
+		Auth: AuthConfig{
+			Domain:         "localhost:3000",
+			SessionTTL:     Duration{24 * time.Hour},
+			NonceTTL:       Duration{10 * time.Minute},
+			AllowedOrigins: []string{"http://localhost:3000"},
+		},
+		Assets: AssetsConfig{
+			BlockedSymbols:    []string{"BTC", "XBTC", "WBTC", "SAT", "SATS", "XSAT", "EXSAT", "ETH", "WETH", "USDT", "USDC", "DAI"},
+			SymbolReservation: Duration{10 * time.Minute},
+		},
+		Storage: StorageConfig{
+			AssetDBPath: "data/fansmint.db",
+			IconDir:     "data/icons",
+		},
+		Timeouts: TimeoutConfig{
+			ExSatRead:  Duration{10 * time.Second},
+			ExSatWrite: Duration{30 * time.Second},
+			ChainRead:  Duration{10 * time.Second},
+			AIGenerate: Duration{60 * time.Second},
+			AIStream:   Duration{3 * time.Minute},
+		},
+	}
+}
+
+// Load builds the configuration from, in increasing order of precedence:
+// defaults, the optional YAML or TOML file at path, the .env file and the
+// process environment. The result is validated before being returned.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	// godotenv never overrides variables already set in the environment
+	if err := godotenv.Load(); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("error loading .env file: %w", err)
+		}
+		log.Println("Warning: .env file not found")
+	}
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	cfg.resolveDefaults()
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadFile overlays a YAML or TOML file, chosen by extension, onto cfg.
+// Unknown keys are rejected so a misspelt setting is not silently ignored.
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err = decoder.Decode(c); errors.Is(err, io.EOF) {
+			err = nil // an empty file
+		}
+	case ".toml":
+		err = toml.NewDecoder(bytes.NewReader(data)).DisallowUnknownFields().Decode(c)
+		var missing *toml.StrictMissingError
+		if errors.As(err, &missing) {
+			keys := make([]string, len(missing.Errors))
+			for i, e := range missing.Errors {
+				keys[i] = strings.Join(e.Key(), ".")
+			}
+			err = fmt.Errorf("unknown keys %s", strings.Join(keys, ", "))
+		}
+	default:
+		return fmt.Errorf("unsupported config file %s: expected .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// applyEnv overrides fields with the environment variables that are set
+func (c *Config) applyEnv() error {
+	setFromEnv(&c.Env, "ENV")
+	setFromEnv(&c.Port, "PORT")
+	setFromEnv(&c.ExSat.APIURL, "EXSAT_API_URL")
+	setFromEnv(&c.ExSat.APIKey, "EXSAT_API_KEY")
+	setFromEnv(&c.ExSat.BitcoinNetwork, "BITCOIN_NETWORK")
+	setFromEnv(&c.AI.Provider, "AI_PROVIDER")
+	setFromEnv(&c.AI.APIKey, "OPENAI_API_KEY")
+	setFromEnv(&c.AI.APIKey, "AI_API_KEY")
+	setFromEnv(&c.AI.BaseURL, "AI_BASE_URL")
+	setFromEnv(&c.AI.Model, "AI_MODEL")
+	setFromEnv(&c.Chain.RPCURL, "EVM_RPC_URL")
+	setFromEnv(&c.Chain.ExplorerURL, "EVM_EXPLORER_URL")
+	setFromEnv(&c.Chain.Factory, "FANSMINT_FACTORY_ADDRESS")
+	setFromEnv(&c.Chain.DeployerKey, "DEPLOYER_PRIVATE_KEY")
+	setFromEnv(&c.Chain.MetadataBaseURL, "TOKEN_METADATA_BASE_URL")
+	if tokens := os.Getenv("EVM_TOKENS"); tokens != "" {
+		c.Chain.Tokens = splitList(tokens)
+	}
+	setFromEnv(&c.Auth.Domain, "SIWE_DOMAIN")
+	setFromEnv(&c.Auth.SessionSecret, "SESSION_SECRET")
+	if origins := os.Getenv("CORS_ALLOWED_ORIGINS"); origins != "" {
+		c.Auth.AllowedOrigins = splitList(origins)
+	}
+	if symbols := os.Getenv("BLOCKED_SYMBOLS"); symbols != "" {
+		c.Assets.BlockedSymbols = splitList(symbols)
+	}
+	setFromEnv(&c.Storage.AssetDBPath, "ASSET_DB_PATH")
+	setFromEnv(&c.Storage.IconDir, "ICON_STORAGE_DIR")
+
+	durations := []struct {
+		field *Duration
+		key   string
+	}{
+		{&c.Timeouts.ExSatRead, "EXSAT_READ_TIMEOUT"},
+		{&c.Timeouts.ExSatWrite, "EXSAT_WRITE_TIMEOUT"},
+		{&c.Timeouts.ChainRead, "EVM_RPC_TIMEOUT"},
+		{&c.Timeouts.AIGenerate, "AI_TIMEOUT"},
+		{&c.Timeouts.AIStream, "AI_STREAM_TIMEOUT"},
+		{&c.ExSat.BreakerCooldown, "EXSAT_BREAKER_COOLDOWN"},
+		{&c.ExSat.SyncInterval, "EXSAT_SYNC_INTERVAL"},
+		{&c.Chain.IndexInterval, "EVM_INDEX_INTERVAL"},
+		{&c.Chain.DeployPollInterval, "DEPLOY_POLL_INTERVAL"},
+		{&c.Auth.SessionTTL, "SESSION_TTL"},
+		{&c.Auth.NonceTTL, "SIWE_NONCE_TTL"},
+		{&c.Assets.SymbolReservation, "SYMBOL_RESERVATION_TTL"},
+	}
+	for _, d := range durations {
+		if value := os.Getenv(d.key); value != "" {
+			if err := d.field.UnmarshalText([]byte(value)); err != nil {
+				return fmt.Errorf("invalid configuration: %s: %w", d.key, err)
+			}
+		}
+	}
+
+	ints := []struct {
+		field *int
+		key   string
+	}{
+		{&c.ExSat.MaxAttempts, "EXSAT_MAX_ATTEMPTS"},
+		{&c.ExSat.BreakerThreshold, "EXSAT_BREAKER_THRESHOLD"},
+		{&c.Chain.Confirmations, "EVM_CONFIRMATIONS"},
+		{&c.Chain.LogBlockRange, "EVM_LOG_BLOCK_RANGE"},
+	}
+	for _, i := range ints {
+		if value := os.Getenv(i.key); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid configuration: %s: %w", i.key, err)
+			}
+			*i.field = n
+		}
+	}
+
+	if value := os.Getenv("EVM_CHAIN_ID"); value != "" {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid configuration: EVM_CHAIN_ID: %w", err)
+		}
+		c.Chain.ChainID = n
+	}
+	if value := os.Getenv("EVM_INDEX_START_BLOCK"); value != "" {
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid configuration: EVM_INDEX_START_BLOCK: %w", err)
+		}
+		c.Chain.IndexStartBlock = n
+	}
+	return nil
+}
+
+// splitList splits a comma separated value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// setFromEnv assigns the value of key to field when it is set and non-empty
+func setFromEnv(field *string, key string) {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		*field = value
+	}
+}
+
+// resolveDefaults fills fields whose default depends on other fields
+func (c *Config) resolveDefaults() {
+	if c.AI.Provider == "" {
+		c.AI.Provider = providerMock
+		if c.AI.APIKey != "" {
+			c.AI.Provider = providerOpenAI
+		}
+	}
+
+	// Mock assets are never issued on chain, so in mock mode the indexer
+	// only polls the public node when it or another node was asked for
+	defaults := Default().Chain
+	if c.ExSat.MockMode() && c.Chain.RPCURL == defaults.RPCURL && c.Chain.IndexInterval == defaults.IndexInterval {
+		c.Chain.IndexInterval = Duration{}
+	}
+}
+
+// Validate checks the configuration and reports every problem at once
+func (c *Config) Validate() error {
+	var problems []string
+
+	switch c.Env {
+	case EnvDevelopment, EnvStaging, EnvProduction, EnvTest:
+	default:
+		problems = append(problems, fmt.Sprintf("env: must be one of development, staging, production or test, got %q", c.Env))
+	}
+
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		problems = append(problems, fmt.Sprintf("port: must be a number between 1 and 65535, got %q", c.Port))
+	}
+
+	if !validURL(c.ExSat.APIURL) {
+		problems = append(problems, fmt.Sprintf("exsat.apiUrl: must be an absolute http(s) URL, got %q", c.ExSat.APIURL))
+	}
+
+	if c.ExSat.MaxAttempts < 1 {
+		problems = append(problems, fmt.Sprintf("exsat.maxAttempts: must be at least 1, got %d", c.ExSat.MaxAttempts))
+	}
+	if c.ExSat.BreakerThreshold < 1 {
+		problems = append(problems, fmt.Sprintf("exsat.breakerThreshold: must be at least 1, got %d", c.ExSat.BreakerThreshold))
+	}
+	if c.ExSat.BreakerCooldown.Duration <= 0 {
+		problems = append(problems, fmt.Sprintf("exsat.breakerCooldown: must be a positive duration, got %s", c.ExSat.BreakerCooldown.Duration))
+	}
+	if c.ExSat.SyncInterval.Duration < 0 {
+		problems = append(problems, fmt.Sprintf("exsat.syncInterval: must not be negative, got %s", c.ExSat.SyncInterval.Duration))
+	}
+	switch strings.ToLower(c.ExSat.BitcoinNetwork) {
+	case "mainnet", "testnet", "signet":
+	default:
+		problems = append(problems, fmt.Sprintf("exsat.bitcoinNetwork: must be mainnet, testnet or signet, got %q", c.ExSat.BitcoinNetwork))
+	}
+
+	switch c.AI.Provider {
+	case providerOpenAI:
+		if c.AI.APIKey == "" {
+			problems = append(problems, "ai.apiKey: required by the openai provider")
+		}
+	case providerOpenAICompatible:
+		if !validURL(c.AI.BaseURL) {
+			problems = append(problems, fmt.Sprintf("ai.baseUrl: the openai-compatible provider needs an absolute http(s) URL, got %q", c.AI.BaseURL))
+		}
+	case providerMock:
+	default:
+		problems = append(problems, fmt.Sprintf("ai.provider: must be openai, openai-compatible or mock, got %q", c.AI.Provider))
+	}
+
+	if !validURL(c.Chain.RPCURL) {
+		problems = append(problems, fmt.Sprintf("chain.rpcUrl: must be an absolute http(s) URL, got %q", c.Chain.RPCURL))
+	}
+	if !validURL(c.Chain.ExplorerURL) {
+		problems = append(problems, fmt.Sprintf("chain.explorerUrl: must be an absolute http(s) URL, got %q", c.Chain.ExplorerURL))
+	}
+	if c.Chain.ChainID < 1 {
+		problems = append(problems, fmt.Sprintf("chain.chainId: must be positive, got %d", c.Chain.ChainID))
+	}
+	for i, token := range c.Chain.Tokens {
+		if !validEVMAddress(token) {
+			problems = append(problems, fmt.Sprintf("chain.tokens[%d]: must be a 0x-prefixed 40 digit hex address, got %q", i, token))
+		}
+	}
+	if c.Chain.IndexInterval.Duration < 0 {
+		problems = append(problems, fmt.Sprintf("chain.indexInterval: must not be negative, got %s", c.Chain.IndexInterval.Duration))
+	}
+	if c.Chain.Confirmations < 0 {
+		problems = append(problems, fmt.Sprintf("chain.confirmations: must not be negative, got %d", c.Chain.Confirmations))
+	}
+	if c.Chain.LogBlockRange < 1 {
+		problems = append(problems, fmt.Sprintf("chain.logBlockRange: must be at least 1, got %d", c.Chain.LogBlockRange))
+	}
+	if c.Chain.Factory != "" {
+		if !validEVMAddress(c.Chain.Factory) {
+			problems = append(problems, fmt.Sprintf("chain.factory: must be a 0x-prefixed 40 digit hex address, got %q", c.Chain.Factory))
+		}
+		if c.Chain.DeployerKey != "" && !validPrivateKey(c.Chain.DeployerKey) {
+			problems = append(problems, "chain.deployerKey: must be a 64 digit hex private key")
+		}
+		if c.Chain.DeployPollInterval.Duration <= 0 {
+			problems = append(problems, fmt.Sprintf("chain.deployPollInterval: must be a positive duration, got %s", c.Chain.DeployPollInterval.Duration))
+		}
+	}
+	if c.Chain.MetadataBaseURL != "" && !validURL(c.Chain.MetadataBaseURL) {
+		problems = append(problems, fmt.Sprintf("chain.metadataBaseUrl: must be an absolute http(s) URL, got %q", c.Chain.MetadataBaseURL))
+	}
+
+	if c.Auth.Domain == "" || strings.ContainsAny(c.Auth.Domain, "/ ") {
+		problems = append(problems, fmt.Sprintf("auth.domain: must be a host with an optional port, got %q", c.Auth.Domain))
+	}
+	switch {
+	case c.Auth.SessionSecret != "" && len(c.Auth.SessionSecret) < minSessionSecret:
+		problems = append(problems, fmt.Sprintf("auth.sessionSecret: must be at least %d characters", minSessionSecret))
+	case c.Auth.SessionSecret == "" && (c.Env == EnvStaging || c.Env == EnvProduction):
+		problems = append(problems, "auth.sessionSecret: required in "+c.Env)
+	}
+	if c.Auth.SessionTTL.Duration <= 0 {
+		problems = append(problems, fmt.Sprintf("auth.sessionTtl: must be a positive duration, got %s", c.Auth.SessionTTL.Duration))
+	}
+	if c.Auth.NonceTTL.Duration <= 0 {
+		problems = append(problems, fmt.Sprintf("auth.nonceTtl: must be a positive duration, got %s", c.Auth.NonceTTL.Duration))
+	}
+	for i, origin := range c.Auth.AllowedOrigins {
+		if !validOrigin(origin) {
+			problems = append(problems, fmt.Sprintf("auth.allowedOrigins[%d]: must be an http(s) origin without a path, got %q", i, origin))
+		}
+	}
+
+	for i, symbol := range c.Assets.BlockedSymbols {
+		if strings.TrimSpace(symbol) == "" {
+			problems = append(problems, fmt.Sprintf("assets.blockedSymbols[%d]: must not be empty", i))
+		}
+	}
+	if c.Assets.SymbolReservation.Duration <= 0 {
+		problems = append(problems, fmt.Sprintf("assets.symbolReservation: must be a positive duration, got %s", c.Assets.SymbolReservation.Duration))
+	}
+
+	if c.Storage.AssetDBPath == "" {
+		problems = append(problems, "storage.assetDbPath: must not be empty")
+	}
+	if c.Storage.IconDir == "" {
+		problems = append(problems, "storage.iconDir: must not be empty")
+	}
+
+	timeouts := []struct {
+		name  string
+		value Duration
+	}{
+		{"timeouts.exsatRead", c.Timeouts.ExSatRead},
+		{"timeouts.exsatWrite", c.Timeouts.ExSatWrite},
+		{"timeouts.chainRead", c.Timeouts.ChainRead},
+		{"timeouts.aiGenerate", c.Timeouts.AIGenerate},
+		{"timeouts.aiStream", c.Timeouts.AIStream},
+	}
+	for _, t := range timeouts {
+		if t.value.Duration <= 0 {
+			problems = append(problems, fmt.Sprintf("%s: must be a positive duration, got %s", t.name, t.value.Duration))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// validURL reports whether s is an absolute http or https URL
+func validURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// validOrigin reports whether s is a browser origin: scheme and host only
+func validOrigin(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && validURL(s) && u.Path == "" && u.RawQuery == "" && u.Fragment == "" && u.User == nil
+}
+
+// validEVMAddress reports whether s looks like a hex EVM address
+func validEVMAddress(s string) bool {
+	if len(s) != 42 || !strings.HasPrefix(s, "0x") {
+		return false
+	}
+	_, err := hex.DecodeString(s[2:])
+	return err == nil
+}
+
+// validPrivateKey reports whether s looks like a hex secp256k1 private key
+func validPrivateKey(s string) bool {
+	s = strings.TrimPrefix(s, "0x")
+	if len(s) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// Redacted returns a copy of the configuration with secrets masked
+func (c *Config) Redacted() *Config {
+	out := *c
+	if out.ExSat.APIKey != "" {
+		out.ExSat.APIKey = redacted
+	}
+	if out.AI.APIKey != "" {
+		out.AI.APIKey = redacted
+	}
+	if out.Chain.DeployerKey != "" {
+		out.Chain.DeployerKey = redacted
+	}
+	if out.Auth.SessionSecret != "" {
+		out.Auth.SessionSecret = redacted
+	}
+	return &out
+}
+
+// YAML renders the configuration with secrets redacted
+func (c *Config) YAML() (string, error) {
+	data, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,258 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// envKeys are every variable Load reads
+var envKeys = []string{
+	"ENV", "PORT", "EXSAT_API_URL", "EXSAT_API_KEY", "BITCOIN_NETWORK",
+	"AI_PROVIDER", "OPENAI_API_KEY", "AI_API_KEY", "AI_BASE_URL", "AI_MODEL",
+	"EVM_RPC_URL", "EVM_EXPLORER_URL", "FANSMINT_FACTORY_ADDRESS", "DEPLOYER_PRIVATE_KEY",
+	"TOKEN_METADATA_BASE_URL", "EVM_TOKENS", "SIWE_DOMAIN", "SESSION_SECRET",
+	"CORS_ALLOWED_ORIGINS", "BLOCKED_SYMBOLS", "ASSET_DB_PATH", "ICON_STORAGE_DIR",
+	"EXSAT_READ_TIMEOUT", "EXSAT_WRITE_TIMEOUT", "EVM_RPC_TIMEOUT", "AI_TIMEOUT",
+	"AI_STREAM_TIMEOUT", "EXSAT_BREAKER_COOLDOWN", "EXSAT_SYNC_INTERVAL", "EVM_INDEX_INTERVAL", "DEPLOY_POLL_INTERVAL",
+	"SESSION_TTL", "SIWE_NONCE_TTL", "SYMBOL_RESERVATION_TTL", "EXSAT_MAX_ATTEMPTS",
+	"EXSAT_BREAKER_THRESHOLD", "EVM_CONFIRMATIONS", "EVM_LOG_BLOCK_RANGE", "EVM_CHAIN_ID",
+	"EVM_INDEX_START_BLOCK",
+}
+
+// isolate clears the variables Load reads, empty counting as unset, and
+// runs the test from a directory without a .env file
+func isolate(t *testing.T) {
+	t.Helper()
+	for _, key := range envKeys {
+		t.Setenv(key, "")
+	}
+	t.Chdir(t.TempDir())
+}
+
+// writeFile writes a config file named name and returns its path
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	isolate(t)
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := Default()
+	want.AI.Provider = providerMock
+	want.Chain.IndexInterval = Duration{}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Load() = %+v, want the defaults %+v", cfg, want)
+	}
+	if !cfg.ExSat.MockMode() {
+		t.Error("MockMode() = false without an exSat API key")
+	}
+}
+
+func TestLoadOverrides(t *testing.T) {
+	isolate(t)
+	yamlFile := writeFile(t, "fansmint.yaml", `
+port: "9000"
+chain:
+  confirmations: 7
+  indexInterval: 1m
+auth:
+  allowedOrigins: ["https://fansmint.example"]
+`)
+	tomlFile := writeFile(t, "fansmint.toml", `
+port = "9001"
+[storage]
+iconDir = "/srv/icons"
+`)
+
+	// The environment wins over the file
+	t.Setenv("EVM_CONFIRMATIONS", "12")
+	t.Setenv("EVM_TOKENS", " 0x8266f2fbc720012e5Ac038aD3dbb29d2d613c459, ,0x4aa4365da82ACD46e378A6f3c92a863f3e763d34")
+	t.Setenv("EXSAT_READ_TIMEOUT", "2s")
+	t.Setenv("EVM_CHAIN_ID", "7200")
+	t.Setenv("EVM_INDEX_START_BLOCK", "1234")
+	t.Setenv("AI_API_KEY", "sk-test")
+
+	cfg, err := Load(yamlFile)
+	if err != nil {
+		t.Fatalf("Load yaml: %v", err)
+	}
+	if cfg.Port != "9000" || cfg.Chain.Confirmations != 12 || cfg.Chain.IndexInterval.Duration != time.Minute {
+		t.Errorf("port %q, confirmations %d, index interval %s, want the file with the environment on top", cfg.Port, cfg.Chain.Confirmations, cfg.Chain.IndexInterval.Duration)
+	}
+	if len(cfg.Chain.Tokens) != 2 || cfg.Auth.AllowedOrigins[0] != "https://fansmint.example" {
+		t.Errorf("tokens %v, origins %v", cfg.Chain.Tokens, cfg.Auth.AllowedOrigins)
+	}
+	if cfg.Timeouts.ExSatRead.Duration != 2*time.Second || cfg.Chain.ChainID != 7200 || cfg.Chain.IndexStartBlock != 1234 {
+		t.Errorf("read timeout %s, chain %d, start block %d", cfg.Timeouts.ExSatRead.Duration, cfg.Chain.ChainID, cfg.Chain.IndexStartBlock)
+	}
+	// An AI key without a provider selects OpenAI
+	if cfg.AI.Provider != providerOpenAI {
+		t.Errorf("provider = %q, want openai", cfg.AI.Provider)
+	}
+
+	cfg, err = Load(tomlFile)
+	if err != nil {
+		t.Fatalf("Load toml: %v", err)
+	}
+	if cfg.Port != "9001" || cfg.Storage.IconDir != "/srv/icons" || cfg.Storage.AssetDBPath != Default().Storage.AssetDBPath {
+		t.Errorf("toml port %q, storage %+v", cfg.Port, cfg.Storage)
+	}
+
+	// In mock mode the indexer only runs against a node asked for
+	if cfg.Chain.IndexInterval.Duration != 0 {
+		t.Errorf("mock mode index interval = %s, want the indexer disabled", cfg.Chain.IndexInterval.Duration)
+	}
+	t.Setenv("EVM_RPC_URL", "http://localhost:8545")
+	if cfg, err = Load(tomlFile); err != nil {
+		t.Fatalf("Load toml with a node: %v", err)
+	}
+	if cfg.Chain.IndexInterval != Default().Chain.IndexInterval {
+		t.Errorf("mock mode index interval with a node = %s, want the default", cfg.Chain.IndexInterval.Duration)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string // content of a .yaml file, if any
+		toml string // content of a .toml file, if any
+		path string // used instead of file when set
+		env  map[string]string
+		want string
+	}{
+		{name: "bad integer", env: map[string]string{"EXSAT_MAX_ATTEMPTS": "three"}, want: "EXSAT_MAX_ATTEMPTS"},
+		{name: "bad duration", env: map[string]string{"SESSION_TTL": "a day"}, want: "SESSION_TTL"},
+		{name: "bad chain ID", env: map[string]string{"EVM_CHAIN_ID": "exsat"}, want: "EVM_CHAIN_ID"},
+		{name: "bad start block", env: map[string]string{"EVM_INDEX_START_BLOCK": "-1"}, want: "EVM_INDEX_START_BLOCK"},
+		{name: "bad yaml", file: "port: [", want: "error parsing config file"},
+		{name: "unknown yaml key", file: "port: \"9000\"\nexsat:\n  apiKye: secret\n", want: "apiKye"},
+		{name: "unknown toml key", toml: "port = \"9000\"\n[exsat]\napiKye = \"secret\"\n", want: "apiKye"},
+		{name: "unknown extension", path: "fansmint.json", want: "unsupported config file"},
+		{name: "missing file", path: "missing.yaml", want: "error reading config file"},
+		{name: "invalid value", env: map[string]string{"PORT": "http"}, want: "port: must be a number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolate(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			path := tt.path
+			if tt.file != "" {
+				path = writeFile(t, "fansmint.yaml", tt.file)
+			}
+			if tt.toml != "" {
+				path = writeFile(t, "fansmint.toml", tt.toml)
+			}
+			if tt.path == "fansmint.json" {
+				path = writeFile(t, tt.path, "{}")
+			}
+
+			_, err := Load(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load = %v, want an error mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(c *Config)
+		want   string // expected in the error, empty when valid
+	}{
+		{"defaults", func(c *Config) {}, ""},
+		{"env", func(c *Config) { c.Env = "prod" }, "env: must be one of"},
+		{"port range", func(c *Config) { c.Port = "70000" }, "port: must be a number between 1 and 65535"},
+		{"exsat url", func(c *Config) { c.ExSat.APIURL = "api.exsat.network" }, "exsat.apiUrl"},
+		{"attempts", func(c *Config) { c.ExSat.MaxAttempts = 0 }, "exsat.maxAttempts"},
+		{"breaker threshold", func(c *Config) { c.ExSat.BreakerThreshold = 0 }, "exsat.breakerThreshold"},
+		{"breaker cooldown", func(c *Config) { c.ExSat.BreakerCooldown.Duration = 0 }, "exsat.breakerCooldown"},
+		{"sync interval", func(c *Config) { c.ExSat.SyncInterval.Duration = -time.Second }, "exsat.syncInterval"},
+		{"bitcoin network", func(c *Config) { c.ExSat.BitcoinNetwork = "regtest" }, "exsat.bitcoinNetwork"},
+		{"openai key", func(c *Config) { c.AI.Provider = providerOpenAI }, "ai.apiKey: required"},
+		{"compatible url", func(c *Config) { c.AI.Provider = providerOpenAICompatible }, "ai.baseUrl"},
+		{"provider", func(c *Config) { c.AI.Provider = "claude" }, "ai.provider"},
+		{"rpc url", func(c *Config) { c.Chain.RPCURL = "" }, "chain.rpcUrl"},
+		{"explorer url", func(c *Config) { c.Chain.ExplorerURL = "ftp://scan" }, "chain.explorerUrl"},
+		{"chain ID", func(c *Config) { c.Chain.ChainID = 0 }, "chain.chainId"},
+		{"token", func(c *Config) { c.Chain.Tokens = []string{"0x1234"} }, "chain.tokens[0]"},
+		{"index interval", func(c *Config) { c.Chain.IndexInterval.Duration = -time.Second }, "chain.indexInterval"},
+		{"confirmations", func(c *Config) { c.Chain.Confirmations = -1 }, "chain.confirmations"},
+		{"log range", func(c *Config) { c.Chain.LogBlockRange = 0 }, "chain.logBlockRange"},
+		{"factory", func(c *Config) { c.Chain.Factory = "factory" }, "chain.factory"},
+		{"deployer key", func(c *Config) {
+			c.Chain.Factory = "0x8266f2fbc720012e5Ac038aD3dbb29d2d613c459"
+			c.Chain.DeployerKey = "0xabc"
+		}, "chain.deployerKey"},
+		{"metadata url", func(c *Config) { c.Chain.MetadataBaseURL = "/metadata/" }, "chain.metadataBaseUrl"},
+		{"domain", func(c *Config) { c.Auth.Domain = "https://fansmint.example/" }, "auth.domain"},
+		{"short secret", func(c *Config) { c.Auth.SessionSecret = "secret" }, "auth.sessionSecret: must be at least"},
+		{"production secret", func(c *Config) { c.Env = EnvProduction }, "auth.sessionSecret: required in production"},
+		{"session ttl", func(c *Config) { c.Auth.SessionTTL.Duration = 0 }, "auth.sessionTtl"},
+		{"nonce ttl", func(c *Config) { c.Auth.NonceTTL.Duration = 0 }, "auth.nonceTtl"},
+		{"origin", func(c *Config) { c.Auth.AllowedOrigins = []string{"http://localhost:3000/app"} }, "auth.allowedOrigins[0]"},
+		{"blocked symbol", func(c *Config) { c.Assets.BlockedSymbols = []string{" "} }, "assets.blockedSymbols[0]"},
+		{"reservation", func(c *Config) { c.Assets.SymbolReservation.Duration = 0 }, "assets.symbolReservation"},
+		{"db path", func(c *Config) { c.Storage.AssetDBPath = "" }, "storage.assetDbPath"},
+		{"icon dir", func(c *Config) { c.Storage.IconDir = "" }, "storage.iconDir"},
+		{"timeout", func(c *Config) { c.Timeouts.AIStream.Duration = 0 }, "timeouts.aiStream"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.AI.Provider = providerMock
+			tt.change(cfg)
+
+			err := cfg.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate = %v, want valid", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate = %v, want an error mentioning %q", err, tt.want)
+			}
+		})
+	}
+
+	// Every problem is reported at once
+	cfg := Default()
+	cfg.Port, cfg.Chain.ChainID = "", 0
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "port:") || !strings.Contains(err.Error(), "chain.chainId") || !strings.Contains(err.Error(), "ai.provider") {
+		t.Errorf("Validate = %v, want the port, chain ID and provider problems", err)
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := Default()
+	cfg.ExSat.APIKey, cfg.AI.APIKey, cfg.Auth.SessionSecret = "exsat-key", "ai-key", strings.Repeat("s", 32)
+
+	out, err := cfg.YAML()
+	if err != nil {
+		t.Fatalf("YAML: %v", err)
+	}
+	for _, secret := range []string{"exsat-key", "ai-key", cfg.Auth.SessionSecret} {
+		if strings.Contains(out, secret) {
+			t.Errorf("YAML leaks %q", secret)
+		}
+	}
+	if cfg.ExSat.APIKey != "exsat-key" {
+		t.Error("Redacted changed the original configuration")
+	}
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"time"
+)
+
+// Duration is a time.Duration written as a Go duration string, e.g. "30s",
+// in YAML, TOML and environment variables
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalText parses a duration string
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
+
+// MarshalText formats the duration as a string
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.Duration.String()), nil
+}
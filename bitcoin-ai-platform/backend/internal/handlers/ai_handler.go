@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/bitcoin-ai-platform/internal/services"
+)
+
+// AIHandler handles requests related to AI generation
+type AIHandler struct {
+	aiService *services.AIService
+}
+
+// NewAIHandler creates a new AI handler
+func NewAIHandler(aiService *services.AIService) *AIHandler {
+	return &AIHandler{
+		aiService: aiService,
+	}
+}
+
+// RegisterRoutes registers AI routes with the provided router
+func (h *AIHandler) RegisterRoutes(router *gin.RouterGroup) {
+	ai := router.Group("/ai")
+	{
+		ai.POST("/generate-whitepaper", h.GenerateWhitepaper)
+		ai.GET("/generate-whitepaper/stream", h.StreamWhitepaper)
+		ai.POST("/generate-whitepaper/stream", h.StreamWhitepaper)
+		ai.POST("/token-suggestion", h.GenerateTokenSuggestions)
+	}
+}
+
+// GenerateWhitepaper handles POST /api/v1/ai/generate-whitepaper
+func (h *AIHandler) GenerateWhitepaper(c *gin.Context) {
+	var req services.WhitepaperRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	content, err := h.aiService.GenerateWhitepaper(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Whitepaper generated successfully",
+		"content":  content,
+		"mockMode": h.aiService.MockMode(),
+		"provider": h.aiService.Provider(),
+	})
+}
+
+// StreamWhitepaper handles GET/POST /api/v1/ai/generate-whitepaper/stream
+//
+// The whitepaper is sent as Server-Sent Events: a "chunk" event per markdown
+// fragment, then a "done" event with token usage or an "error" event.
+// GET requests take the fields as query parameters, POST requests as JSON.
+func (h *AIHandler) StreamWhitepaper(c *gin.Context) {
+	var req services.WhitepaperRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	ctx := c.Request.Context()
+	usage, err := h.aiService.StreamWhitepaper(ctx, req, func(chunk string) error {
+		c.SSEvent("chunk", gin.H{"content": chunk})
+		c.Writer.Flush()
+		return ctx.Err()
+	})
+	if ctx.Err() != nil {
+		// The client went away, nobody is left to read the final event
+		return
+	}
+	if err != nil {
+		// Headers are already sent, so the envelope travels as an event
+		apiErr := toAPIError(err)
+		apiErr.RequestID = RequestIDFrom(c)
+		log.Printf("Error: request %s streaming whitepaper: %v", apiErr.RequestID, err)
+		c.SSEvent("error", ErrorResponse{Error: apiErr})
+		c.Writer.Flush()
+		return
+	}
+
+	c.SSEvent("done", gin.H{
+		"message":  "Whitepaper generated successfully",
+		"usage":    usage,
+		"mockMode": h.aiService.MockMode(),
+		"provider": h.aiService.Provider(),
+	})
+	c.Writer.Flush()
+}
+
+// GenerateTokenSuggestions handles POST /api/v1/ai/token-suggestion
+func (h *AIHandler) GenerateTokenSuggestions(c *gin.Context) {
+	var req services.TokenSuggestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	suggestions, err := h.aiService.GenerateTokenSuggestions(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Token suggestions generated successfully",
+		"suggestions": suggestions,
+		"mockMode":    h.aiService.MockMode(),
+		"provider":    h.aiService.Provider(),
+	})
+}
@@ -2,23 +2,27 @@ package handlers
 
 import (
 	"fmt"
-	"math/rand"
 	"net/http"
-	"time"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/bitcoin-ai-platform/internal/repository"
 	"github.com/yourusername/bitcoin-ai-platform/internal/services"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/imaging"
 )
 
 // AssetHandler handles requests related to assets
 type AssetHandler struct {
-	assetService *services.AssetService
+	assetService services.AssetService
+	requireAuth  gin.HandlerFunc
 }
 
-// NewAssetHandler creates a new asset handler
-func NewAssetHandler() *AssetHandler {
+// NewAssetHandler creates a new asset handler. requireAuth guards the
+// routes acting for a wallet, see AuthHandler.RequireAuth.
+func NewAssetHandler(assetService services.AssetService, requireAuth gin.HandlerFunc) *AssetHandler {
 	return &AssetHandler{
-		assetService: services.NewAssetService(),
+		assetService: assetService,
+		requireAuth:  requireAuth,
 	}
 }
 
@@ -26,160 +30,342 @@ func NewAssetHandler() *AssetHandler {
 func (h *AssetHandler) RegisterRoutes(router *gin.RouterGroup) {
 	assets := router.Group("/assets")
 	{
-		assets.GET("/", h.GetAssets)
+		assets.GET("/", h.ListAssets)
 		assets.GET("/:id", h.GetAsset)
-		assets.POST("/create", h.CreateAsset)
+		assets.GET("/:id/icon", h.GetAssetIcon)
+		assets.GET("/:id/holders", h.GetAssetHolders)
+		assets.GET("/:id/transactions", h.GetAssetTransactions)
+		assets.GET("/:id/history", h.GetAssetHistory)
+		assets.POST("/:id/pause", h.requireAuth, h.ChangeAssetStatus(services.StatusPaused))
+		assets.POST("/:id/resume", h.requireAuth, h.ChangeAssetStatus(services.StatusActive))
+		assets.POST("/:id/retire", h.requireAuth, h.ChangeAssetStatus(services.StatusRetired))
+		assets.GET("/symbols/:symbol/availability", h.GetSymbolAvailability)
+		assets.POST("/create", h.requireAuth, h.CreateAsset)
+		assets.POST("/create/prepare", h.requireAuth, h.PrepareAsset)
+		assets.POST("/create/submit", h.requireAuth, h.SubmitAsset)
 	}
 }
 
-// GetAssets handles GET /api/v1/assets
-func (h *AssetHandler) GetAssets(c *gin.Context) {
-	// Check if we're in mock mode
-	if h.assetService.MockMode() {
-		// Return mock assets
-		mockAssets := getMockAssets()
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Get asset list",
-			"assets":  mockAssets,
-		})
-		return
+// ListAssets handles GET /api/v1/assets
+//
+// Query parameters: limit, cursor (nextCursor of the previous page), status
+// (pending_review, deploying, active, paused, failed or retired), owner,
+// symbol (prefix) and sort=createdAt|holders|supply.
+func (h *AssetHandler) ListAssets(c *gin.Context) {
+	query := repository.AssetQuery{
+		Status:       c.Query("status"),
+		Owner:        c.Query("owner"),
+		SymbolPrefix: c.Query("symbol"),
+		Sort:         c.Query("sort"),
+		Cursor:       c.Query("cursor"),
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			c.Error(&services.ValidationError{Fields: []services.FieldError{{Field: "limit", Message: "must be a positive integer"}}})
+			return
+		}
+		query.Limit = n
 	}
 
-	// Use the real API
-	assets, err := h.assetService.GetAssets()
+	page, err := h.assetService.ListAssets(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": fmt.Sprintf("Failed to get assets: %v", err),
-		})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Get asset list",
-		"assets":  assets,
+		"message":    "Get asset list",
+		"assets":     page.Assets,
+		"nextCursor": page.NextCursor,
 	})
 }
 
 // GetAsset handles GET /api/v1/assets/:id
 func (h *AssetHandler) GetAsset(c *gin.Context) {
 	id := c.Param("id")
-	if id == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Asset ID is required",
-		})
+	asset, err := h.assetService.GetAsset(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	// Check if we're in mock mode
-	if h.assetService.MockMode() {
-		// Return a mock asset
-		c.JSON(http.StatusOK, gin.H{
-			"message": fmt.Sprintf("Get asset details: %s", id),
-			"asset": map[string]interface{}{
-				"id":                id,
-				"name":              "ExampleToken",
-				"symbol":            "EXT",
-				"totalSupply":       "1000000",
-				"createdAt":         time.Now().Format(time.RFC3339),
-				"holdersCount":      "120",
-				"circulatingSupply": "750000",
-				"description":       "Example token description",
-				"contractAddress":   "0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
-				"status":            "active",
-				"iconUrl":           "",
-			},
-		})
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Get asset details: %s", id),
+		"asset":   asset,
+	})
+}
+
+// GetAssetHolders handles GET /api/v1/assets/:id/holders
+//
+// Holders are sorted by balance, largest first. Query parameters: limit and
+// cursor (nextCursor of the previous page).
+func (h *AssetHandler) GetAssetHolders(c *gin.Context) {
+	id := c.Param("id")
+	query := repository.HolderQuery{Cursor: c.Query("cursor")}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			c.Error(&services.ValidationError{Fields: []services.FieldError{{Field: "limit", Message: "must be a positive integer"}}})
+			return
+		}
+		query.Limit = n
+	}
+
+	holders, err := h.assetService.GetHolders(c.Request.Context(), id, query)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	// Use the real API
-	asset, err := h.assetService.GetAsset(id)
+	c.JSON(http.StatusOK, gin.H{
+		"message":      fmt.Sprintf("Get asset holders: %s", id),
+		"assetId":      holders.AssetID,
+		"indexedBlock": holders.IndexedBlock,
+		"holderCount":  holders.HolderCount,
+		"holders":      holders.Holders,
+		"nextCursor":   holders.NextCursor,
+	})
+}
+
+// GetAssetTransactions handles GET /api/v1/assets/:id/transactions
+//
+// Transfers are sorted newest first. Query parameters: limit, cursor
+// (nextCursor of the previous page), address, direction=in|out relative to
+// the address, and sinceBlock to only return transfers in later blocks;
+// polling with the indexedBlock of the last response returns what is new.
+func (h *AssetHandler) GetAssetTransactions(c *gin.Context) {
+	id := c.Param("id")
+	query := repository.TransferQuery{
+		Address:   c.Query("address"),
+		Direction: c.Query("direction"),
+		Cursor:    c.Query("cursor"),
+	}
+	var invalid services.ValidationError
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			invalid.Add("limit", "must be a positive integer")
+		}
+		query.Limit = n
+	}
+	if since := c.Query("sinceBlock"); since != "" {
+		n, err := strconv.ParseUint(since, 10, 64)
+		if err != nil {
+			invalid.Add("sinceBlock", "must be a block number")
+		}
+		query.SinceBlock = n
+	}
+	if err := invalid.Err(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	transfers, err := h.assetService.GetTransfers(c.Request.Context(), id, query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      fmt.Sprintf("Get asset transactions: %s", id),
+		"assetId":      transfers.AssetID,
+		"indexedBlock": transfers.IndexedBlock,
+		"transactions": transfers.Transfers,
+		"nextCursor":   transfers.NextCursor,
+	})
+}
+
+// GetAssetHistory handles GET /api/v1/assets/:id/history
+//
+// Lists the status changes of the asset, oldest first, with when, by whom
+// and why each was made.
+func (h *AssetHandler) GetAssetHistory(c *gin.Context) {
+	id := c.Param("id")
+	history, err := h.assetService.GetHistory(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": fmt.Sprintf("Failed to get asset: %v", err),
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Get asset history: %s", id),
+		"assetId": history.AssetID,
+		"status":  history.Status,
+		"history": history.Changes,
+	})
+}
+
+// StatusChangeRequest is the optional body of the asset status routes
+type StatusChangeRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ChangeAssetStatus returns the handler of POST /api/v1/assets/:id/pause,
+// /resume and /retire, moving the asset to status
+//
+// Requires a session of the asset owner. The body may give a reason,
+// recorded in the asset history.
+func (h *AssetHandler) ChangeAssetStatus(status string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req StatusChangeRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.Error(invalidRequest(err))
+				return
+			}
+		}
+
+		asset, err := h.assetService.ChangeStatus(c.Request.Context(), c.Param("id"), SessionFrom(c).Address, status, req.Reason)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": fmt.Sprintf("Asset is now %s", asset.Status),
+			"assetId": asset.ID,
+			"asset":   asset,
 		})
+	}
+}
+
+// GetSymbolAvailability handles GET /api/v1/assets/symbols/:symbol/availability
+//
+// Reports whether a new asset may use the symbol, and the reason when not:
+// invalid, blocked, taken or reserved by a creation in progress.
+func (h *AssetHandler) GetSymbolAvailability(c *gin.Context) {
+	availability, err := h.assetService.SymbolAvailability(c.Param("symbol"))
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
+	c.Header("Cache-Control", "no-store")
 	c.JSON(http.StatusOK, gin.H{
-		"message": fmt.Sprintf("Get asset details: %s", id),
-		"asset":   asset,
+		"message":   fmt.Sprintf("Symbol availability: %s", availability.Symbol),
+		"symbol":    availability.Symbol,
+		"available": availability.Available,
+		"reason":    availability.Reason,
+		"detail":    availability.Message,
 	})
 }
 
 // CreateAsset handles POST /api/v1/assets/create
+//
+// Requires a session; the signed-in wallet owns the new asset.
 func (h *AssetHandler) CreateAsset(c *gin.Context) {
 	var req services.AssetCreationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": fmt.Sprintf("Invalid request: %v", err),
-		})
+		c.Error(invalidRequest(err))
 		return
 	}
 
-	// Check if we're in mock mode
-	if h.assetService.MockMode() {
-		// 生成资产ID
-		assetId := fmt.Sprintf("ast_%d", rand.Intn(100000))
+	req.Wallet = SessionFrom(c).Address
+	req.IdempotencyKey = c.GetHeader("Idempotency-Key")
 
-		// 如果提供了图标数据，在实际环境中这里会存储图片并生成URL
-		iconUrl := ""
-		if req.IconData != "" {
-			// 在真实环境中，这里会将base64解码并保存为文件
-			// 这里仅模拟返回一个URL
-			iconUrl = fmt.Sprintf("/api/v1/assets/%s/icon", assetId)
-		}
+	asset, err := h.assetService.CreateAsset(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
 
-		// Return a mock asset creation response
-		c.JSON(http.StatusCreated, gin.H{
-			"message": "Asset created successfully",
-			"assetId": assetId,
-			"iconUrl": iconUrl,
-		})
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Asset created successfully",
+		"assetId": asset.ID,
+		"iconUrl": asset.IconUrl,
+		"asset":   asset,
+	})
+}
+
+// PrepareAsset handles POST /api/v1/assets/create/prepare
+//
+// The asset is created like by CreateAsset, and the reply carries the
+// unsigned EIP-1559 transaction deploying its token, ready for the owner's
+// wallet (eth_sendTransaction or eth_signTransaction).
+func (h *AssetHandler) PrepareAsset(c *gin.Context) {
+	var req services.AssetCreationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
 		return
 	}
 
-	// Use the real API
-	asset, err := h.assetService.CreateAsset(req)
+	req.Wallet = SessionFrom(c).Address
+	req.IdempotencyKey = c.GetHeader("Idempotency-Key")
+
+	prepared, err := h.assetService.PrepareAsset(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": fmt.Sprintf("Failed to create asset: %v", err),
-		})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"message": "Asset created successfully",
+		"message":     "Asset created, sign the transaction to deploy its token",
+		"assetId":     prepared.Asset.ID,
+		"asset":       prepared.Asset,
+		"transaction": prepared.Transaction,
+	})
+}
+
+// SubmitAssetRequest is the body of POST /api/v1/assets/create/submit
+type SubmitAssetRequest struct {
+	AssetID string `json:"assetId" binding:"required"`
+	services.DeploymentSubmission
+}
+
+// SubmitAsset handles POST /api/v1/assets/create/submit
+//
+// Accepts the signed deployment transaction of a prepared asset, either raw
+// to be broadcast or as the hash of a transaction the wallet broadcast. The
+// deployment is then tracked until confirmed.
+func (h *AssetHandler) SubmitAsset(c *gin.Context) {
+	var req SubmitAssetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	asset, err := h.assetService.SubmitDeployment(c.Request.Context(), req.AssetID, SessionFrom(c).Address, req.DeploymentSubmission)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Deployment submitted",
 		"assetId": asset.ID,
+		"asset":   asset,
 	})
 }
 
-// getMockAssets returns mock assets for development purposes
-func getMockAssets() []map[string]interface{} {
-	return []map[string]interface{}{
-		{
-			"id":                "1",
-			"name":              "ExampleToken",
-			"symbol":            "EXT",
-			"totalSupply":       "1000000",
-			"createdAt":         "2023-05-19T10:00:00Z",
-			"holdersCount":      "120",
-			"circulatingSupply": "750000",
-			"contractAddress":   "0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
-			"status":            "active",
-			"iconUrl":           "/api/v1/assets/1/icon",
-		},
-		{
-			"id":                "2",
-			"name":              "BitcoinUtility",
-			"symbol":            "BTU",
-			"totalSupply":       "2100000",
-			"createdAt":         "2023-05-15T14:30:00Z",
-			"holdersCount":      "89",
-			"circulatingSupply": "1050000",
-			"contractAddress":   "0x5aeda56215b167893e80b4fe645ba6d5bab767de",
-			"status":            "active",
-			"iconUrl":           "/api/v1/assets/2/icon",
-		},
+// GetAssetIcon handles GET /api/v1/assets/:id/icon
+//
+// ?size=thumb serves the thumbnail instead of the original upload.
+// Responses carry an ETag and honor If-None-Match.
+func (h *AssetHandler) GetAssetIcon(c *gin.Context) {
+	variant := services.IconOriginal
+	if c.Query("size") == services.IconThumbnail {
+		variant = services.IconThumbnail
+	}
+
+	icon, err := h.assetService.GetIcon(c.Param("id"), variant)
+	if err != nil {
+		c.Error(err)
+		return
 	}
+
+	c.Header("ETag", icon.ETag)
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Header("X-Content-Type-Options", "nosniff")
+	if icon.ContentType == imaging.ContentTypeSVG {
+		// Defense in depth on top of sanitization when the SVG is opened directly
+		c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
+	}
+
+	if match := c.GetHeader("If-None-Match"); match != "" && (match == icon.ETag || match == "*") {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, icon.ContentType, icon.Data)
 }
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/bitcoin-ai-platform/internal/services"
+)
+
+// sessionKey is the context key holding the authenticated session
+const sessionKey = "session"
+
+// AuthHandler handles Sign-In with Ethereum requests
+type AuthHandler struct {
+	authService *services.AuthService
+}
+
+// NewAuthHandler creates a new auth handler
+func NewAuthHandler(authService *services.AuthService) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+	}
+}
+
+// RegisterRoutes registers auth routes with the provided router
+func (h *AuthHandler) RegisterRoutes(router *gin.RouterGroup) {
+	auth := router.Group("/auth")
+	{
+		auth.GET("/nonce", h.GetNonce)
+		auth.POST("/verify", h.Verify)
+		auth.GET("/session", h.RequireAuth(), h.GetSession)
+	}
+}
+
+// RequireAuth rejects requests without a valid "Authorization: Bearer"
+// session token, and makes the session available to SessionFrom
+func (h *AuthHandler) RequireAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			c.Error(fmt.Errorf("%w: missing bearer session token", services.ErrUnauthorized))
+			c.Abort()
+			return
+		}
+
+		session, err := h.authService.Authenticate(strings.TrimSpace(token))
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Set(sessionKey, session)
+		c.Next()
+	}
+}
+
+// SessionFrom returns the session set by RequireAuth, nil on routes
+// without it
+func SessionFrom(c *gin.Context) *services.Session {
+	session, _ := c.Get(sessionKey)
+	s, _ := session.(*services.Session)
+	return s
+}
+
+// GetNonce handles GET /api/v1/auth/nonce
+//
+// The nonce is single use and goes in the Nonce field of the EIP-4361
+// message, along with the returned domain and chain ID.
+func (h *AuthHandler) GetNonce(c *gin.Context) {
+	challenge, err := h.authService.Nonce()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Sign an EIP-4361 message with this nonce",
+		"nonce":     challenge.Nonce,
+		"domain":    challenge.Domain,
+		"chainId":   challenge.ChainID,
+		"expiresAt": challenge.ExpiresAt,
+	})
+}
+
+// VerifyRequest is the body of POST /api/v1/auth/verify
+type VerifyRequest struct {
+	Message   string `json:"message" binding:"required"`
+	Signature string `json:"signature" binding:"required"`
+}
+
+// Verify handles POST /api/v1/auth/verify
+//
+// Checks the personal_sign signature of an EIP-4361 message and returns a
+// session token, sent back as "Authorization: Bearer <token>".
+func (h *AuthHandler) Verify(c *gin.Context) {
+	var req VerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	session, err := h.authService.SignIn(req.Message, req.Signature)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Signed in",
+		"token":     session.Token,
+		"address":   session.Address,
+		"expiresAt": session.ExpiresAt,
+	})
+}
+
+// GetSession handles GET /api/v1/auth/session
+func (h *AuthHandler) GetSession(c *gin.Context) {
+	session := SessionFrom(c)
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Signed in",
+		"address":   session.Address,
+		"expiresAt": session.ExpiresAt,
+	})
+}
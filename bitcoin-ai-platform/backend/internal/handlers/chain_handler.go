@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/bitcoin-ai-platform/internal/services"
+)
+
+// ChainHandler handles requests reading exSat EVM state
+type ChainHandler struct {
+	chainService *services.ChainService
+}
+
+// NewChainHandler creates a new chain handler
+func NewChainHandler(chainService *services.ChainService) *ChainHandler {
+	return &ChainHandler{
+		chainService: chainService,
+	}
+}
+
+// RegisterRoutes registers chain routes with the provided router
+func (h *ChainHandler) RegisterRoutes(router *gin.RouterGroup) {
+	chain := router.Group("/chain")
+	{
+		chain.GET("/tokens/:address", h.GetToken)
+		chain.GET("/balances/:wallet", h.GetBalances)
+	}
+}
+
+// GetToken handles GET /api/v1/chain/tokens/:address
+func (h *ChainHandler) GetToken(c *gin.Context) {
+	token, err := h.chainService.GetToken(c.Request.Context(), c.Param("address"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Get token details",
+		"token":   token,
+	})
+}
+
+// GetBalances handles GET /api/v1/chain/balances/:wallet
+func (h *ChainHandler) GetBalances(c *gin.Context) {
+	balances, err := h.chainService.GetBalances(c.Request.Context(), c.Param("wallet"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Get wallet balances",
+		"wallet":      balances.Wallet,
+		"blockNumber": balances.BlockNumber,
+		"balances":    balances.Balances,
+	})
+}
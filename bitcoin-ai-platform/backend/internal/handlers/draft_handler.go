@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/bitcoin-ai-platform/internal/services"
+)
+
+// DraftHandler handles requests about the asset creations in progress of
+// the signed-in wallet
+type DraftHandler struct {
+	draftService *services.DraftService
+	requireAuth  gin.HandlerFunc
+}
+
+// NewDraftHandler creates a new draft handler. requireAuth guards every
+// draft route, see AuthHandler.RequireAuth.
+func NewDraftHandler(draftService *services.DraftService, requireAuth gin.HandlerFunc) *DraftHandler {
+	return &DraftHandler{
+		draftService: draftService,
+		requireAuth:  requireAuth,
+	}
+}
+
+// RegisterRoutes registers draft routes with the provided router
+func (h *DraftHandler) RegisterRoutes(router *gin.RouterGroup) {
+	drafts := router.Group("/drafts", h.requireAuth)
+	{
+		drafts.GET("", h.ListDrafts)
+		drafts.POST("", h.CreateDraft)
+		drafts.GET("/:id", h.GetDraft)
+		drafts.PATCH("/:id", h.UpdateDraft)
+		drafts.DELETE("/:id", h.DeleteDraft)
+		drafts.POST("/:id/publish", h.PublishDraft)
+		drafts.GET("/:id/whitepaper", h.GetDraftWhitepaper)
+		drafts.GET("/:id/whitepaper/diff", h.DiffDraftWhitepaper)
+	}
+}
+
+// ListDrafts handles GET /api/v1/drafts
+//
+// Lists the drafts of the signed-in wallet, most recently updated first,
+// without their icons. Published drafts carry the assetId they became.
+func (h *DraftHandler) ListDrafts(c *gin.Context) {
+	drafts, err := h.draftService.ListDrafts(c.Request.Context(), SessionFrom(c).Address)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Get draft list",
+		"drafts":  drafts,
+	})
+}
+
+// CreateDraft handles POST /api/v1/drafts
+//
+// The body may give any of the draft fields; the rest are filled in later
+// with PATCH.
+func (h *DraftHandler) CreateDraft(c *gin.Context) {
+	var update services.DraftUpdate
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&update); err != nil {
+			c.Error(invalidRequest(err))
+			return
+		}
+	}
+
+	draft, err := h.draftService.CreateDraft(c.Request.Context(), SessionFrom(c).Address, update)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Draft saved",
+		"draftId": draft.ID,
+		"draft":   draft,
+	})
+}
+
+// GetDraft handles GET /api/v1/drafts/:id
+func (h *DraftHandler) GetDraft(c *gin.Context) {
+	id := c.Param("id")
+	draft, err := h.draftService.GetDraft(c.Request.Context(), id, SessionFrom(c).Address)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Get draft details: %s", id),
+		"draft":   draft,
+	})
+}
+
+// UpdateDraft handles PATCH /api/v1/drafts/:id
+//
+// Only the fields present in the body change.
+func (h *DraftHandler) UpdateDraft(c *gin.Context) {
+	var update services.DraftUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	draft, err := h.draftService.UpdateDraft(c.Request.Context(), c.Param("id"), SessionFrom(c).Address, update)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Draft saved",
+		"draftId": draft.ID,
+		"draft":   draft,
+	})
+}
+
+// DeleteDraft handles DELETE /api/v1/drafts/:id
+func (h *DraftHandler) DeleteDraft(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.draftService.DeleteDraft(c.Request.Context(), id, SessionFrom(c).Address); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Draft deleted",
+		"draftId": id,
+	})
+}
+
+// PublishDraft handles POST /api/v1/drafts/:id/publish
+//
+// Creates the asset the draft describes like POST /api/v1/assets/create,
+// failing with the same validation errors while fields are missing.
+func (h *DraftHandler) PublishDraft(c *gin.Context) {
+	id := c.Param("id")
+	asset, err := h.draftService.PublishDraft(c.Request.Context(), id, SessionFrom(c).Address, c.GetHeader("Idempotency-Key"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Draft published",
+		"draftId": id,
+		"assetId": asset.ID,
+		"iconUrl": asset.IconUrl,
+		"asset":   asset,
+	})
+}
+
+// GetDraftWhitepaper handles GET /api/v1/drafts/:id/whitepaper
+//
+// Every whitepaper saved in the draft is kept as a version. Serves the
+// latest, or the one given by ?version=n.
+func (h *DraftHandler) GetDraftWhitepaper(c *gin.Context) {
+	var invalid services.ValidationError
+	version := versionQuery(c, "version", &invalid)
+	if err := invalid.Err(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	id := c.Param("id")
+	whitepaper, err := h.draftService.GetWhitepaper(c.Request.Context(), id, SessionFrom(c).Address, version)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    fmt.Sprintf("Get draft whitepaper: %s", id),
+		"draftId":    id,
+		"whitepaper": whitepaper,
+	})
+}
+
+// DiffDraftWhitepaper handles GET /api/v1/drafts/:id/whitepaper/diff
+//
+// Takes the same from and to parameters as the asset whitepaper diff.
+func (h *DraftHandler) DiffDraftWhitepaper(c *gin.Context) {
+	var invalid services.ValidationError
+	from, to := versionQuery(c, "from", &invalid), versionQuery(c, "to", &invalid)
+	if err := invalid.Err(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	id := c.Param("id")
+	diff, err := h.draftService.DiffWhitepaper(c.Request.Context(), id, SessionFrom(c).Address, from, to)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Diff draft whitepaper: %s", id),
+		"draftId": id,
+		"diff":    diff,
+	})
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/yourusername/bitcoin-ai-platform/internal/services"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/ai"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/evm"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/imaging"
+)
+
+// statusClientClosedRequest is reported when the caller went away before the reply
+const statusClientClosedRequest = 499
+
+// ErrorResponse is the body of every failed API response
+type ErrorResponse struct {
+	Error *APIError `json:"error"`
+}
+
+// APIError is the error envelope returned to API consumers. Handlers attach
+// errors with c.Error and ErrorHandler turns them into an APIError, so
+// upstream response bodies and internal details never reach the client.
+type APIError struct {
+	Status    int         `json:"-"`
+	Code      string      `json:"code"`
+	Message   string      `json:"message"`
+	Details   interface{} `json:"details,omitempty"`
+	RequestID string      `json:"requestId,omitempty"`
+
+	retryAfter int // seconds, sent as Retry-After
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// ErrorHandler writes the error attached last to the context as an
+// ErrorResponse, unless the handler already wrote a response
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		apiErr := toAPIError(err)
+		apiErr.RequestID = RequestIDFrom(c)
+		if apiErr.Status >= http.StatusInternalServerError {
+			log.Printf("Error: request %s %s %s: %v", apiErr.RequestID, c.Request.Method, c.Request.URL.Path, err)
+		}
+		if apiErr.retryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(apiErr.retryAfter))
+		}
+		c.AbortWithStatusJSON(apiErr.Status, ErrorResponse{Error: apiErr})
+	}
+}
+
+// NotFound answers requests for unknown routes
+func NotFound(c *gin.Context) {
+	c.Error(&APIError{
+		Status:  http.StatusNotFound,
+		Code:    "route_not_found",
+		Message: fmt.Sprintf("No route for %s %s", c.Request.Method, c.Request.URL.Path),
+	})
+}
+
+// invalidRequest wraps a request binding error
+func invalidRequest(err error) *APIError {
+	apiErr := &APIError{
+		Status:  http.StatusBadRequest,
+		Code:    "invalid_request",
+		Message: "The request body or parameters could not be read",
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		fields := make([]services.FieldError, len(fieldErrs))
+		for i, fe := range fieldErrs {
+			fields[i] = services.FieldError{Field: lowerFirst(fe.Field()), Message: validationMessage(fe)}
+		}
+		apiErr.Code = "validation_failed"
+		apiErr.Message = "The request has invalid fields"
+		apiErr.Details = fields
+	}
+	return apiErr
+}
+
+// toAPIError maps service, client and provider errors to their API form
+func toAPIError(err error) *APIError {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	var validationErr *services.ValidationError
+	if errors.As(err, &validationErr) {
+		return &APIError{
+			Status:  http.StatusBadRequest,
+			Code:    "validation_failed",
+			Message: "The request has invalid fields",
+			Details: validationErr.Fields,
+		}
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return &APIError{Status: statusClientClosedRequest, Code: "request_canceled", Message: "The request was canceled"}
+	case errors.Is(err, context.DeadlineExceeded):
+		return &APIError{Status: http.StatusGatewayTimeout, Code: "timeout", Message: "An upstream service did not answer in time"}
+	case errors.Is(err, services.ErrAssetNotFound):
+		return &APIError{Status: http.StatusNotFound, Code: "asset_not_found", Message: "Asset not found"}
+	case errors.Is(err, services.ErrUnauthorized):
+		return &APIError{Status: http.StatusUnauthorized, Code: "unauthorized", Message: "Sign in with your wallet to use this route"}
+	case errors.Is(err, services.ErrSignInFailed):
+		return &APIError{Status: http.StatusUnauthorized, Code: "sign_in_failed", Message: err.Error()}
+	case errors.Is(err, services.ErrNotAssetOwner):
+		return &APIError{Status: http.StatusForbidden, Code: "not_asset_owner", Message: "The signed-in wallet does not own this asset"}
+	case errors.Is(err, services.ErrDeploymentDisabled):
+		return &APIError{Status: http.StatusServiceUnavailable, Code: "deployment_disabled", Message: "Token deployment is not enabled on this server"}
+	case errors.Is(err, services.ErrDeploymentConflict):
+		return &APIError{Status: http.StatusConflict, Code: "deployment_conflict", Message: err.Error()}
+	case errors.Is(err, services.ErrInvalidTransition):
+		return &APIError{Status: http.StatusConflict, Code: "invalid_status_transition", Message: err.Error()}
+	case errors.Is(err, services.ErrDraftNotFound):
+		return &APIError{Status: http.StatusNotFound, Code: "draft_not_found", Message: "Draft not found"}
+	case errors.Is(err, services.ErrDraftPublished):
+		return &APIError{Status: http.StatusConflict, Code: "draft_published", Message: err.Error()}
+	case errors.Is(err, services.ErrTooManyDrafts):
+		return &APIError{Status: http.StatusConflict, Code: "too_many_drafts", Message: err.Error()}
+	case errors.Is(err, services.ErrWhitepaperNotFound):
+		return &APIError{Status: http.StatusNotFound, Code: "whitepaper_not_found", Message: err.Error()}
+	case errors.Is(err, services.ErrIconNotFound):
+		return &APIError{Status: http.StatusNotFound, Code: "icon_not_found", Message: "Icon not found"}
+	case errors.Is(err, imaging.ErrInvalidImage):
+		return &APIError{Status: http.StatusBadRequest, Code: "invalid_image", Message: err.Error()}
+	case errors.Is(err, ai.ErrInvalidResponse):
+		return &APIError{Status: http.StatusBadGateway, Code: "ai_invalid_response", Message: "The AI model returned an unusable response"}
+	case errors.Is(err, ai.ErrProvider):
+		return &APIError{Status: http.StatusBadGateway, Code: "ai_unavailable", Message: "The AI provider request failed"}
+	case errors.Is(err, evm.ErrNotContract), errors.Is(err, evm.ErrABI):
+		return &APIError{Status: http.StatusNotFound, Code: "token_not_found", Message: "No ERC-20 token at this address"}
+	case errors.Is(err, evm.ErrRPC):
+		return &APIError{Status: http.StatusBadGateway, Code: "chain_unavailable", Message: "The exSat EVM node request failed"}
+	case errors.Is(err, client.ErrCircuitOpen):
+		return &APIError{Status: http.StatusServiceUnavailable, Code: "upstream_unavailable", Message: "exSat is temporarily unavailable"}
+	}
+
+	var exSatErr *client.APIError
+	if errors.As(err, &exSatErr) {
+		return exSatAPIError(exSatErr)
+	}
+	if errors.Is(err, client.ErrUpstream) {
+		return &APIError{Status: http.StatusBadGateway, Code: "upstream_error", Message: "The exSat request failed"}
+	}
+
+	return &APIError{Status: http.StatusInternalServerError, Code: "internal_error", Message: "An unexpected error occurred"}
+}
+
+// exSatAPIError maps an exSat error response. Only validation messages,
+// which describe the caller's own input, are passed through.
+func exSatAPIError(err *client.APIError) *APIError {
+	switch {
+	case errors.Is(err, client.ErrNotFound):
+		return &APIError{Status: http.StatusNotFound, Code: "not_found", Message: "The resource does not exist on exSat"}
+	case errors.Is(err, client.ErrValidation):
+		apiErr := &APIError{Status: http.StatusBadRequest, Code: "validation_failed", Message: "exSat rejected the request"}
+		if err.Message != "" {
+			apiErr.Message = err.Message
+		}
+		if len(err.Fields) > 0 {
+			apiErr.Details = err.Fields
+		}
+		return apiErr
+	case errors.Is(err, client.ErrRateLimited):
+		return &APIError{
+			Status:     http.StatusTooManyRequests,
+			Code:       "rate_limited",
+			Message:    "Too many requests to exSat, try again later",
+			retryAfter: int(err.RetryAfter.Seconds()),
+		}
+	case errors.Is(err, client.ErrUnauthorized):
+		// Our credentials were refused, which is not the caller's fault
+		return &APIError{Status: http.StatusBadGateway, Code: "upstream_unauthorized", Message: "exSat refused the platform credentials"}
+	default:
+		return &APIError{Status: http.StatusBadGateway, Code: "upstream_error", Message: "The exSat request failed"}
+	}
+}
+
+// validationMessage describes a failed binding rule
+func validationMessage(fe validator.FieldError) string {
+	if fe.Tag() == "required" {
+		return "is required"
+	}
+	return fmt.Sprintf("failed the %s check", fe.Tag())
+}
+
+// lowerFirst turns a Go field name into its JSON spelling
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
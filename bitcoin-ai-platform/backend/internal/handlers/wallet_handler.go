@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/bitcoin-ai-platform/internal/services"
+)
+
+// WalletHandler handles requests about the FansMint tokens of a wallet
+type WalletHandler struct {
+	portfolioService *services.PortfolioService
+}
+
+// NewWalletHandler creates a new wallet handler
+func NewWalletHandler(portfolioService *services.PortfolioService) *WalletHandler {
+	return &WalletHandler{
+		portfolioService: portfolioService,
+	}
+}
+
+// RegisterRoutes registers wallet routes with the provided router
+func (h *WalletHandler) RegisterRoutes(router *gin.RouterGroup) {
+	wallets := router.Group("/wallets")
+	{
+		wallets.GET("/:address/portfolio", h.GetPortfolio)
+	}
+}
+
+// GetPortfolio handles GET /api/v1/wallets/:address/portfolio
+//
+// Lists the FansMint tokens the address holds, the assets it created and
+// its first and last transfers, all from indexed chain data.
+func (h *WalletHandler) GetPortfolio(c *gin.Context) {
+	portfolio, err := h.portfolioService.GetPortfolio(c.Request.Context(), c.Param("address"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Get wallet portfolio",
+		"address":       portfolio.Address,
+		"kind":          portfolio.Kind,
+		"holdings":      portfolio.Holdings,
+		"created":       portfolio.Created,
+		"transferCount": portfolio.TransferCount,
+		"firstActivity": portfolio.FirstActivity,
+		"lastActivity":  portfolio.LastActivity,
+	})
+}
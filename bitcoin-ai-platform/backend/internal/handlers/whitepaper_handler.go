@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/bitcoin-ai-platform/internal/services"
+)
+
+// WhitepaperHandler handles requests about the whitepapers of assets
+type WhitepaperHandler struct {
+	whitepaperService *services.WhitepaperService
+	requireAuth       gin.HandlerFunc
+}
+
+// NewWhitepaperHandler creates a new whitepaper handler. requireAuth guards
+// the routes acting for a wallet, see AuthHandler.RequireAuth.
+func NewWhitepaperHandler(whitepaperService *services.WhitepaperService, requireAuth gin.HandlerFunc) *WhitepaperHandler {
+	return &WhitepaperHandler{
+		whitepaperService: whitepaperService,
+		requireAuth:       requireAuth,
+	}
+}
+
+// RegisterRoutes registers whitepaper routes with the provided router
+func (h *WhitepaperHandler) RegisterRoutes(router *gin.RouterGroup) {
+	assets := router.Group("/assets")
+	{
+		assets.GET("/:id/whitepaper", h.GetWhitepaper)
+		assets.PUT("/:id/whitepaper", h.requireAuth, h.PublishWhitepaper)
+		assets.GET("/:id/whitepaper/diff", h.DiffWhitepaper)
+	}
+}
+
+// PublishWhitepaperRequest is the body of PUT /api/v1/assets/:id/whitepaper
+type PublishWhitepaperRequest struct {
+	Content string `json:"content"` // markdown
+}
+
+// GetWhitepaper handles GET /api/v1/assets/:id/whitepaper
+//
+// Serves the latest version, or the one given by ?version=n. contentHash is
+// the hex SHA-256 of content; the asset records the hash of the published
+// version.
+func (h *WhitepaperHandler) GetWhitepaper(c *gin.Context) {
+	var invalid services.ValidationError
+	version := versionQuery(c, "version", &invalid)
+	if err := invalid.Err(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	id := c.Param("id")
+	whitepaper, err := h.whitepaperService.GetAssetWhitepaper(c.Request.Context(), id, version)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    fmt.Sprintf("Get asset whitepaper: %s", id),
+		"assetId":    id,
+		"whitepaper": whitepaper,
+	})
+}
+
+// PublishWhitepaper handles PUT /api/v1/assets/:id/whitepaper
+//
+// Requires a session of the asset owner. The content is saved as a new
+// version and published; sending the latest content again changes nothing.
+func (h *WhitepaperHandler) PublishWhitepaper(c *gin.Context) {
+	var req PublishWhitepaperRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	asset, err := h.whitepaperService.PublishAssetWhitepaper(c.Request.Context(), c.Param("id"), SessionFrom(c).Address, req.Content)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    fmt.Sprintf("Whitepaper version %d published", asset.Whitepaper.Version),
+		"assetId":    asset.ID,
+		"whitepaper": asset.Whitepaper,
+		"asset":      asset,
+	})
+}
+
+// DiffWhitepaper handles GET /api/v1/assets/:id/whitepaper/diff
+//
+// Compares ?from=n with ?to=m as a unified diff. to defaults to the latest
+// version and from to the one before it.
+func (h *WhitepaperHandler) DiffWhitepaper(c *gin.Context) {
+	var invalid services.ValidationError
+	from, to := versionQuery(c, "from", &invalid), versionQuery(c, "to", &invalid)
+	if err := invalid.Err(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	id := c.Param("id")
+	diff, err := h.whitepaperService.DiffAssetWhitepaper(c.Request.Context(), id, from, to)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Diff asset whitepaper: %s", id),
+		"assetId": id,
+		"diff":    diff,
+	})
+}
+
+// versionQuery parses the whitepaper version in query parameter name, 0
+// when absent
+func versionQuery(c *gin.Context, name string, invalid *services.ValidationError) int {
+	value := c.Query(name)
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		invalid.Add(name, "must be a positive integer")
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
+)
+
+// ErrNotFound is returned when a record does not exist in the store
+var ErrNotFound = errors.New("record not found")
+
+// AssetRepository persists the assets created through FansMint
+type AssetRepository interface {
+	// Save inserts or replaces an asset, keyed by its ID
+	Save(asset client.Asset) error
+	// Get returns the asset with the given ID or ErrNotFound
+	Get(id string) (*client.Asset, error)
+	// List returns every stored asset, newest first
+	List() ([]client.Asset, error)
+	// Query returns a filtered, sorted page of assets
+	Query(q AssetQuery) (*AssetPage, error)
+	// Update applies fn to the asset with the given ID and saves the result
+	// along with the status change fn returns, if any, atomically. An error
+	// from fn leaves the asset unchanged and is returned as is.
+	Update(id string, fn func(asset *client.Asset) (*StatusChange, error)) (*client.Asset, error)
+	// RecordStatus appends a change to the status history of an asset
+	RecordStatus(assetID string, change StatusChange) error
+	// History returns the status changes of an asset, oldest first
+	History(assetID string) ([]StatusChange, error)
+
+	// AssetsWithSymbol returns the IDs of the assets whose symbol matches,
+	// compared by NormalizeSymbol
+	AssetsWithSymbol(symbol string) ([]string, error)
+	// AssetsWithName returns the IDs of the assets whose name matches,
+	// compared by NormalizeName
+	AssetsWithName(name string) ([]string, error)
+	// RecordCreation records that the creation identified by key, which
+	// its retries share, made the asset assetID
+	RecordCreation(key, assetID string) error
+	// Creation returns the ID of the asset made by the creation identified
+	// by key, or ErrNotFound
+	Creation(key string) (string, error)
+
+	// ApplyTransfers records transfers observed up to block inclusive,
+	// applies their balance changes and records block as indexed, atomically.
+	// Transfers already recorded, by block and log index, are skipped, so
+	// replaying a batch changes nothing.
+	ApplyTransfers(assetID string, transfers []Transfer, block uint64) error
+	// IndexedBlock returns the last block applied for an asset, 0 if none
+	IndexedBlock(assetID string) (uint64, error)
+	// Holders returns every holder of an asset, largest balance first
+	Holders(assetID string) ([]Holder, error)
+	// QueryHolders returns a page of Holders
+	QueryHolders(assetID string, q HolderQuery) (*HolderPage, error)
+	// QueryTransfers returns a filtered page of the recorded transfers of
+	// an asset, newest first
+	QueryTransfers(assetID string, q TransferQuery) (*TransferPage, error)
+	// Account returns the balance and transfer activity of address, in its
+	// EIP-55 form, in an asset
+	Account(assetID, address string) (*Account, error)
+
+	// SaveDraft inserts or replaces a draft, keyed by its ID
+	SaveDraft(draft Draft) error
+	// GetDraft returns the draft with the given ID or ErrNotFound
+	GetDraft(id string) (*Draft, error)
+	// UpdateDraft applies fn to the draft with the given ID and saves the
+	// result atomically. An error from fn leaves the draft unchanged and is
+	// returned as is.
+	UpdateDraft(id string, fn func(draft *Draft) error) (*Draft, error)
+	// ListDrafts returns the drafts of owner, most recently updated first.
+	// Icons are left out; GetDraft has them.
+	ListDrafts(owner string) ([]Draft, error)
+	// DeleteDraft removes the draft with the given ID or returns ErrNotFound
+	DeleteDraft(id string) error
+
+	// AddWhitepaper appends version to the whitepaper of doc, an asset or a
+	// draft, numbering it after the last one, and drops the oldest versions
+	// beyond the latest keep. When the latest version has the same
+	// ContentHash nothing is added and that version is returned.
+	AddWhitepaper(doc string, version WhitepaperVersion, keep int) (*WhitepaperVersion, error)
+	// Whitepaper returns the given version of the whitepaper of doc, the
+	// latest for 0, or ErrNotFound
+	Whitepaper(doc string, version int) (*WhitepaperVersion, error)
+	// Whitepapers returns every version of the whitepaper of doc, oldest first
+	Whitepapers(doc string) ([]WhitepaperVersion, error)
+	// DeleteWhitepapers removes every version of the whitepaper of doc
+	DeleteWhitepapers(doc string) error
+	// Close releases the underlying storage
+	Close() error
+}
+
+// sortAssets orders assets by creation time, newest first
+func sortAssets(assets []client.Asset) {
+	sort.SliceStable(assets, func(i, j int) bool {
+		if assets[i].CreatedAt == assets[j].CreatedAt {
+			return assets[i].ID < assets[j].ID
+		}
+		return assets[i].CreatedAt > assets[j].CreatedAt
+	})
+}
@@ -0,0 +1,860 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	assetsBucket = []byte("assets")
+	// holdersBucket holds a nested bucket per asset mapping address to balance
+	holdersBucket = []byte("holders")
+	// indexedBucket maps asset ID to the last block applied to its holders
+	indexedBucket = []byte("indexed_blocks")
+	// transfersBucket holds a nested bucket per asset mapping block and log
+	// index, big-endian, to a transfer
+	transfersBucket = []byte("transfers")
+	// historyBucket holds a nested bucket per asset mapping a big-endian
+	// sequence number to a status change
+	historyBucket = []byte("status_history")
+	// draftsBucket maps draft ID to a draft
+	draftsBucket = []byte("drafts")
+	// ownerDraftsBucket indexes drafts by owner, keyed by the owner, a zero
+	// byte and the draft ID
+	ownerDraftsBucket = []byte("owner_drafts")
+	// whitepapersBucket holds a nested bucket per document mapping a
+	// big-endian version number to a whitepaper version
+	whitepapersBucket = []byte("whitepapers")
+	// symbolsBucket and namesBucket index assets by their normalized symbol
+	// and name, keyed by the value, a zero byte and the asset ID
+	symbolsBucket = []byte("asset_symbols")
+	namesBucket   = []byte("asset_names")
+	// creationsBucket maps the key of a creation to the asset it made
+	creationsBucket = []byte("creations")
+	// accountTransfersBucket holds a nested bucket per asset indexing its
+	// transfers by account, keyed by the lowercase address, a zero byte and
+	// the key of the transfer in transfersBucket
+	accountTransfersBucket = []byte("account_transfers")
+	// accountsBucket holds a nested bucket per asset mapping lowercase
+	// address to the transfer activity of the account
+	accountsBucket = []byte("accounts")
+)
+
+// BoltAssetRepository is an AssetRepository backed by an embedded bbolt database
+type BoltAssetRepository struct {
+	db *bolt.DB
+}
+
+// NewBoltAssetRepository opens (creating if needed) the database at path
+func NewBoltAssetRepository(path string) (*BoltAssetRepository, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("error creating database directory: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("error opening database: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		// Databases from before an index get it built once
+		namesIndexed := tx.Bucket(symbolsBucket) != nil
+		transfersIndexed := tx.Bucket(accountTransfersBucket) != nil
+		activityRecorded := tx.Bucket(accountsBucket) != nil
+		draftsIndexed := tx.Bucket(ownerDraftsBucket) != nil
+		for _, bucket := range [][]byte{assetsBucket, holdersBucket, indexedBucket, transfersBucket, historyBucket, draftsBucket, ownerDraftsBucket, whitepapersBucket, symbolsBucket, namesBucket, creationsBucket, accountTransfersBucket, accountsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		if !draftsIndexed {
+			err := tx.Bucket(draftsBucket).ForEach(func(_, data []byte) error {
+				var draft Draft
+				if err := json.Unmarshal(data, &draft); err != nil {
+					return err
+				}
+				return indexEntry(tx.Bucket(ownerDraftsBucket), draft.Owner, draft.ID, true)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if !namesIndexed {
+			err := tx.Bucket(assetsBucket).ForEach(func(_, data []byte) error {
+				var asset client.Asset
+				if err := json.Unmarshal(data, &asset); err != nil {
+					return err
+				}
+				return indexNames(tx, asset, true)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if transfersIndexed && activityRecorded {
+			return nil
+		}
+		return tx.Bucket(transfersBucket).ForEachBucket(func(assetID []byte) error {
+			var transfers []Transfer
+			err := tx.Bucket(transfersBucket).Bucket(assetID).ForEach(func(key, data []byte) error {
+				var t Transfer
+				if err := json.Unmarshal(data, &t); err != nil {
+					return err
+				}
+				transfers = append(transfers, t)
+				if transfersIndexed {
+					return nil
+				}
+				return indexTransfer(tx, string(assetID), key, t)
+			})
+			if err != nil || activityRecorded {
+				return err
+			}
+			return recordActivity(tx, string(assetID), transfers)
+		})
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error initializing database: %w", err)
+	}
+
+	return &BoltAssetRepository{db: db}, nil
+}
+
+// Save inserts or replaces an asset
+func (r *BoltAssetRepository) Save(asset client.Asset) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return putAsset(tx, asset)
+	})
+}
+
+// putAsset stores asset within tx, moving its symbol and name index entries
+func putAsset(tx *bolt.Tx, asset client.Asset) error {
+	bucket := tx.Bucket(assetsBucket)
+	if data := bucket.Get([]byte(asset.ID)); data != nil {
+		var old client.Asset
+		if err := json.Unmarshal(data, &old); err != nil {
+			return err
+		}
+		if err := indexNames(tx, old, false); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(asset)
+	if err != nil {
+		return fmt.Errorf("error marshaling asset: %w", err)
+	}
+	if err := bucket.Put([]byte(asset.ID), data); err != nil {
+		return err
+	}
+	return indexNames(tx, asset, true)
+}
+
+// indexNames adds, or removes, the symbol and name index entries of asset
+func indexNames(tx *bolt.Tx, asset client.Asset, add bool) error {
+	symbol, name := nameKeys(asset)
+	if err := indexEntry(tx.Bucket(symbolsBucket), symbol, asset.ID, add); err != nil {
+		return err
+	}
+	return indexEntry(tx.Bucket(namesBucket), name, asset.ID, add)
+}
+
+// indexEntry adds, or removes, the entry filing the asset id under value
+func indexEntry(bucket *bolt.Bucket, value, id string, add bool) error {
+	key := []byte(value + "\x00" + id)
+	if add {
+		return bucket.Put(key, []byte{})
+	}
+	return bucket.Delete(key)
+}
+
+// Get returns the asset with the given ID
+func (r *BoltAssetRepository) Get(id string) (*client.Asset, error) {
+	var asset client.Asset
+	err := r.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(assetsBucket).Get([]byte(id))
+		if data == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(data, &asset)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+// List returns every stored asset, newest first
+func (r *BoltAssetRepository) List() ([]client.Asset, error) {
+	assets := []client.Asset{}
+	err := r.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(assetsBucket).ForEach(func(_, data []byte) error {
+			var asset client.Asset
+			if err := json.Unmarshal(data, &asset); err != nil {
+				return err
+			}
+			assets = append(assets, asset)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortAssets(assets)
+	return assets, nil
+}
+
+// Query returns a filtered, sorted page of assets
+func (r *BoltAssetRepository) Query(q AssetQuery) (*AssetPage, error) {
+	assets, err := r.List()
+	if err != nil {
+		return nil, err
+	}
+	return queryAssets(assets, q)
+}
+
+// Update applies fn to an asset and saves it with its status change in a
+// single transaction
+func (r *BoltAssetRepository) Update(id string, fn func(asset *client.Asset) (*StatusChange, error)) (*client.Asset, error) {
+	var asset client.Asset
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		data := tx.Bucket(assetsBucket).Get([]byte(id))
+		if data == nil {
+			return ErrNotFound
+		}
+		if err := json.Unmarshal(data, &asset); err != nil {
+			return err
+		}
+		change, err := fn(&asset)
+		if err != nil {
+			return err
+		}
+
+		if err := putAsset(tx, asset); err != nil {
+			return err
+		}
+		if change == nil {
+			return nil
+		}
+		return appendStatus(tx, id, *change)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+// RecordStatus appends a change to the status history of an asset
+func (r *BoltAssetRepository) RecordStatus(assetID string, change StatusChange) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return appendStatus(tx, assetID, change)
+	})
+}
+
+// appendStatus appends a change to the history of an asset within tx
+func appendStatus(tx *bolt.Tx, assetID string, change StatusChange) error {
+	bucket, err := tx.Bucket(historyBucket).CreateBucketIfNotExists([]byte(assetID))
+	if err != nil {
+		return err
+	}
+	seq, err := bucket.NextSequence()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("error marshaling status change: %w", err)
+	}
+	return bucket.Put(binary.BigEndian.AppendUint64(nil, seq), data)
+}
+
+// History returns the status changes of an asset, oldest first
+func (r *BoltAssetRepository) History(assetID string) ([]StatusChange, error) {
+	history := []StatusChange{}
+	err := r.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(historyBucket).Bucket([]byte(assetID))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(_, data []byte) error {
+			var change StatusChange
+			if err := json.Unmarshal(data, &change); err != nil {
+				return err
+			}
+			history = append(history, change)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// AssetsWithSymbol returns the IDs of the assets using symbol
+func (r *BoltAssetRepository) AssetsWithSymbol(symbol string) ([]string, error) {
+	return r.indexed(symbolsBucket, NormalizeSymbol(symbol))
+}
+
+// AssetsWithName returns the IDs of the assets using name
+func (r *BoltAssetRepository) AssetsWithName(name string) ([]string, error) {
+	return r.indexed(namesBucket, NormalizeName(name))
+}
+
+// indexed returns the asset IDs filed under value in an index bucket
+func (r *BoltAssetRepository) indexed(bucket []byte, value string) ([]string, error) {
+	ids := []string{}
+	prefix := []byte(value + "\x00")
+	err := r.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucket).Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			// Values holding a zero byte themselves share the prefix
+			if id := k[len(prefix):]; bytes.IndexByte(id, 0) < 0 {
+				ids = append(ids, string(id))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// RecordCreation records the asset made by a creation
+func (r *BoltAssetRepository) RecordCreation(key, assetID string) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(creationsBucket).Put([]byte(key), []byte(assetID))
+	})
+}
+
+// Creation returns the ID of the asset made by a creation
+func (r *BoltAssetRepository) Creation(key string) (string, error) {
+	var id string
+	err := r.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(creationsBucket).Get([]byte(key))
+		if data == nil {
+			return ErrNotFound
+		}
+		id = string(data)
+		return nil
+	})
+	return id, err
+}
+
+// ApplyTransfers records transfers not recorded yet and applies their
+// balance changes up to block in a single transaction
+func (r *BoltAssetRepository) ApplyTransfers(assetID string, transfers []Transfer, block uint64) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		recorded, err := tx.Bucket(transfersBucket).CreateBucketIfNotExists([]byte(assetID))
+		if err != nil {
+			return err
+		}
+		// Transfers already recorded were applied by an earlier batch
+		var added []Transfer
+		for _, t := range transfers {
+			key := transferKey(t.Block, t.LogIndex)
+			if recorded.Get(key) != nil {
+				continue
+			}
+			added = append(added, t)
+			data, err := json.Marshal(t)
+			if err != nil {
+				return fmt.Errorf("error marshaling transfer: %w", err)
+			}
+			if err := recorded.Put(key, data); err != nil {
+				return err
+			}
+			if err := indexTransfer(tx, assetID, key, t); err != nil {
+				return err
+			}
+		}
+		if err := recordActivity(tx, assetID, added); err != nil {
+			return err
+		}
+
+		bucket, err := tx.Bucket(holdersBucket).CreateBucketIfNotExists([]byte(assetID))
+		if err != nil {
+			return err
+		}
+
+		// Load only the accounts touched by this batch
+		balances := make(map[string]*big.Int)
+		for _, t := range added {
+			for _, address := range []string{t.From, t.To} {
+				if data := bucket.Get([]byte(address)); data != nil {
+					balances[address] = decimalValue(string(data))
+				}
+			}
+		}
+		touched := make(map[string]bool)
+		for _, t := range added {
+			touched[t.From], touched[t.To] = true, true
+		}
+		applyTransfers(balances, added)
+
+		for address := range touched {
+			if address == ZeroAddress {
+				continue
+			}
+			balance, ok := balances[address]
+			if !ok {
+				if err := bucket.Delete([]byte(address)); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := bucket.Put([]byte(address), []byte(balance.String())); err != nil {
+				return err
+			}
+		}
+
+		return tx.Bucket(indexedBucket).Put([]byte(assetID), binary.BigEndian.AppendUint64(nil, block))
+	})
+}
+
+// IndexedBlock returns the last block applied for an asset
+func (r *BoltAssetRepository) IndexedBlock(assetID string) (uint64, error) {
+	var block uint64
+	err := r.db.View(func(tx *bolt.Tx) error {
+		if data := tx.Bucket(indexedBucket).Get([]byte(assetID)); len(data) == 8 {
+			block = binary.BigEndian.Uint64(data)
+		}
+		return nil
+	})
+	return block, err
+}
+
+// Holders returns every holder of an asset, largest balance first
+func (r *BoltAssetRepository) Holders(assetID string) ([]Holder, error) {
+	balances := make(map[string]*big.Int)
+	err := r.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(holdersBucket).Bucket([]byte(assetID))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(address, balance []byte) error {
+			balances[string(address)] = decimalValue(string(balance))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return holderList(balances), nil
+}
+
+// QueryHolders returns a page of holders
+func (r *BoltAssetRepository) QueryHolders(assetID string, q HolderQuery) (*HolderPage, error) {
+	holders, err := r.Holders(assetID)
+	if err != nil {
+		return nil, err
+	}
+	return queryHolders(holders, q)
+}
+
+// QueryTransfers returns a filtered page of the transfers of an asset,
+// reading only as far back as the page needs
+func (r *BoltAssetRepository) QueryTransfers(assetID string, q TransferQuery) (*TransferPage, error) {
+	pager, err := newTransferPager(q)
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.View(func(tx *bolt.Tx) error {
+		recorded := tx.Bucket(transfersBucket).Bucket([]byte(assetID))
+		if recorded == nil {
+			return nil
+		}
+		// With an address, walk its index entries instead of every transfer
+		walked, prefix := recorded, []byte(nil)
+		if q.Address != "" {
+			if walked = tx.Bucket(accountTransfersBucket).Bucket([]byte(assetID)); walked == nil {
+				return nil
+			}
+			prefix = accountPrefix(q.Address)
+		}
+
+		// Keys sort oldest first, so walk them backwards from the cursor
+		c := walked.Cursor()
+		var key, data []byte
+		if pager.after != nil {
+			key, data = seekBefore(c, append(prefix, transferKey(pager.after.block, pager.after.logIndex)...))
+		} else if prefix != nil {
+			// The entries of the address sort before its address and a one byte
+			end := append(bytes.Clone(prefix[:len(prefix)-1]), 1)
+			key, data = seekBefore(c, end)
+		} else {
+			key, data = c.Last()
+		}
+		for ; key != nil && bytes.HasPrefix(key, prefix); key, data = c.Prev() {
+			if prefix != nil {
+				data = recorded.Get(key[len(prefix):])
+			}
+			var t Transfer
+			if err := json.Unmarshal(data, &t); err != nil {
+				return err
+			}
+			// Every transfer further back is older still
+			if t.Block <= q.SinceBlock {
+				return nil
+			}
+			if pager.add(t) {
+				return nil
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pager.page, nil
+}
+
+// seekBefore moves c to the last key sorting before key
+func seekBefore(c *bolt.Cursor, key []byte) ([]byte, []byte) {
+	if k, _ := c.Seek(key); k == nil {
+		return c.Last()
+	}
+	return c.Prev()
+}
+
+// transferKey is the key of the transfer at block and logIndex, which
+// sorts transfers oldest first
+func transferKey(block, logIndex uint64) []byte {
+	return binary.BigEndian.AppendUint64(binary.BigEndian.AppendUint64(nil, block), logIndex)
+}
+
+// accountPrefix is the prefix of the index entries of the transfers of
+// address in accountTransfersBucket
+func accountPrefix(address string) []byte {
+	return []byte(strings.ToLower(address) + "\x00")
+}
+
+// indexTransfer files the transfer t stored under key by its sender and
+// recipient
+func indexTransfer(tx *bolt.Tx, assetID string, key []byte, t Transfer) error {
+	bucket, err := tx.Bucket(accountTransfersBucket).CreateBucketIfNotExists([]byte(assetID))
+	if err != nil {
+		return err
+	}
+	for _, address := range []string{t.From, t.To} {
+		if err := bucket.Put(append(accountPrefix(address), key...), []byte{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Account returns the balance and transfer activity of address in an asset
+func (r *BoltAssetRepository) Account(assetID, address string) (*Account, error) {
+	account := &Account{}
+	err := r.db.View(func(tx *bolt.Tx) error {
+		if accounts := tx.Bucket(accountsBucket).Bucket([]byte(assetID)); accounts != nil {
+			if data := accounts.Get([]byte(strings.ToLower(address))); data != nil {
+				if err := json.Unmarshal(data, account); err != nil {
+					return err
+				}
+			}
+		}
+		account.Balance = new(big.Int)
+		if holders := tx.Bucket(holdersBucket).Bucket([]byte(assetID)); holders != nil {
+			if data := holders.Get([]byte(address)); data != nil {
+				account.Balance = decimalValue(string(data))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// recordActivity counts transfers, newly recorded, in the activity of the
+// accounts involved
+func recordActivity(tx *bolt.Tx, assetID string, transfers []Transfer) error {
+	bucket, err := tx.Bucket(accountsBucket).CreateBucketIfNotExists([]byte(assetID))
+	if err != nil {
+		return err
+	}
+
+	accounts := make(map[string]*Account)
+	err = observeTransfers(transfers, func(address string) (*Account, error) {
+		key := strings.ToLower(address)
+		if account, ok := accounts[key]; ok {
+			return account, nil
+		}
+		account := &Account{}
+		if data := bucket.Get([]byte(key)); data != nil {
+			if err := json.Unmarshal(data, account); err != nil {
+				return nil, err
+			}
+		}
+		accounts[key] = account
+		return account, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for key, account := range accounts {
+		data, err := json.Marshal(account)
+		if err != nil {
+			return fmt.Errorf("error marshaling account: %w", err)
+		}
+		if err := bucket.Put([]byte(key), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveDraft inserts or replaces a draft
+func (r *BoltAssetRepository) SaveDraft(draft Draft) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return putDraft(tx, draft)
+	})
+}
+
+// putDraft stores draft within tx, moving its owner index entry
+func putDraft(tx *bolt.Tx, draft Draft) error {
+	bucket := tx.Bucket(draftsBucket)
+	owners := tx.Bucket(ownerDraftsBucket)
+	if data := bucket.Get([]byte(draft.ID)); data != nil {
+		var old Draft
+		if err := json.Unmarshal(data, &old); err != nil {
+			return err
+		}
+		if err := indexEntry(owners, old.Owner, old.ID, false); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(draft)
+	if err != nil {
+		return fmt.Errorf("error marshaling draft: %w", err)
+	}
+	if err := bucket.Put([]byte(draft.ID), data); err != nil {
+		return err
+	}
+	return indexEntry(owners, draft.Owner, draft.ID, true)
+}
+
+// GetDraft returns the draft with the given ID
+func (r *BoltAssetRepository) GetDraft(id string) (*Draft, error) {
+	var draft Draft
+	err := r.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(draftsBucket).Get([]byte(id))
+		if data == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(data, &draft)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+// UpdateDraft applies fn to a draft and saves it in a single transaction
+func (r *BoltAssetRepository) UpdateDraft(id string, fn func(draft *Draft) error) (*Draft, error) {
+	var draft Draft
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		data := tx.Bucket(draftsBucket).Get([]byte(id))
+		if data == nil {
+			return ErrNotFound
+		}
+		if err := json.Unmarshal(data, &draft); err != nil {
+			return err
+		}
+		if err := fn(&draft); err != nil {
+			return err
+		}
+		return putDraft(tx, draft)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+// ListDrafts returns the drafts of owner without their icons, most
+// recently updated first
+func (r *BoltAssetRepository) ListDrafts(owner string) ([]Draft, error) {
+	drafts := []Draft{}
+	err := r.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(draftsBucket)
+		prefix := []byte(owner + "\x00")
+		c := tx.Bucket(ownerDraftsBucket).Cursor()
+		for key, _ := c.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, _ = c.Next() {
+			data := bucket.Get(key[len(prefix):])
+			if data == nil {
+				continue
+			}
+			var draft Draft
+			if err := json.Unmarshal(data, &draft); err != nil {
+				return err
+			}
+			draft.IconData = ""
+			drafts = append(drafts, draft)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortDrafts(drafts)
+	return drafts, nil
+}
+
+// DeleteDraft removes a draft
+func (r *BoltAssetRepository) DeleteDraft(id string) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(draftsBucket)
+		data := bucket.Get([]byte(id))
+		if data == nil {
+			return ErrNotFound
+		}
+		var draft Draft
+		if err := json.Unmarshal(data, &draft); err != nil {
+			return err
+		}
+		if err := indexEntry(tx.Bucket(ownerDraftsBucket), draft.Owner, draft.ID, false); err != nil {
+			return err
+		}
+		return bucket.Delete([]byte(id))
+	})
+}
+
+// AddWhitepaper appends the next version of the whitepaper of doc, unless
+// it repeats the latest one, keeping the latest keep versions
+func (r *BoltAssetRepository) AddWhitepaper(doc string, version WhitepaperVersion, keep int) (*WhitepaperVersion, error) {
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.Bucket(whitepapersBucket).CreateBucketIfNotExists([]byte(doc))
+		if err != nil {
+			return err
+		}
+		if _, data := bucket.Cursor().Last(); data != nil {
+			var latest WhitepaperVersion
+			if err := json.Unmarshal(data, &latest); err != nil {
+				return err
+			}
+			if latest.ContentHash == version.ContentHash {
+				version = latest
+				return nil
+			}
+		}
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		version.Version = int(seq)
+		data, err := json.Marshal(version)
+		if err != nil {
+			return fmt.Errorf("error marshaling whitepaper: %w", err)
+		}
+		if err := bucket.Put(binary.BigEndian.AppendUint64(nil, seq), data); err != nil {
+			return err
+		}
+
+		// Versions are numbered in order, so the oldest come first
+		var dropped [][]byte
+		c := bucket.Cursor()
+		for key, _ := c.First(); key != nil && binary.BigEndian.Uint64(key)+uint64(keep) <= seq; key, _ = c.Next() {
+			dropped = append(dropped, key)
+		}
+		for _, key := range dropped {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// Whitepaper returns a version of the whitepaper of doc, the latest for 0
+func (r *BoltAssetRepository) Whitepaper(doc string, version int) (*WhitepaperVersion, error) {
+	if version < 0 {
+		return nil, ErrNotFound
+	}
+
+	var v WhitepaperVersion
+	err := r.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(whitepapersBucket).Bucket([]byte(doc))
+		if bucket == nil {
+			return ErrNotFound
+		}
+		var data []byte
+		if version == 0 {
+			_, data = bucket.Cursor().Last()
+		} else {
+			data = bucket.Get(binary.BigEndian.AppendUint64(nil, uint64(version)))
+		}
+		if data == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(data, &v)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Whitepapers returns every version of the whitepaper of doc, oldest first
+func (r *BoltAssetRepository) Whitepapers(doc string) ([]WhitepaperVersion, error) {
+	versions := []WhitepaperVersion{}
+	err := r.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(whitepapersBucket).Bucket([]byte(doc))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(_, data []byte) error {
+			var v WhitepaperVersion
+			if err := json.Unmarshal(data, &v); err != nil {
+				return err
+			}
+			versions = append(versions, v)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// DeleteWhitepapers removes the whitepaper of doc
+func (r *BoltAssetRepository) DeleteWhitepapers(doc string) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(whitepapersBucket)
+		if bucket.Bucket([]byte(doc)) == nil {
+			return nil
+		}
+		return bucket.DeleteBucket([]byte(doc))
+	})
+}
+
+// Close closes the database
+func (r *BoltAssetRepository) Close() error {
+	return r.db.Close()
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"github.com/yourusername/bitcoin-ai-platform/pkg/ai"
+)
+
+// Draft is an asset creation in progress, saved by the creation wizard as
+// its steps are filled in
+type Draft struct {
+	ID    string `json:"id"`
+	Owner string `json:"owner"` // EIP-55 wallet address of the creator
+	Step  int    `json:"step"`  // wizard step to resume at
+
+	UseCase      string              `json:"useCase"`
+	Suggestion   *ai.TokenSuggestion `json:"suggestion"` // the chosen AI suggestion, if any
+	Name         string              `json:"name"`
+	Symbol       string              `json:"symbol"`
+	TotalSupply  string              `json:"totalSupply"`
+	Decimals     *int                `json:"decimals"`
+	Description  string              `json:"description"`
+	OwnerAddress string              `json:"ownerAddress"`
+	IconData     string              `json:"iconData,omitempty"`
+	Whitepaper   string              `json:"whitepaper"` // markdown
+
+	AssetID   string    `json:"assetId,omitempty"` // set once published
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// sortDrafts orders drafts by last update, most recent first
+func sortDrafts(drafts []Draft) {
+	sort.SliceStable(drafts, func(i, j int) bool {
+		if drafts[i].UpdatedAt.Equal(drafts[j].UpdatedAt) {
+			return drafts[i].ID < drafts[j].ID
+		}
+		return drafts[i].UpdatedAt.After(drafts[j].UpdatedAt)
+	})
+}
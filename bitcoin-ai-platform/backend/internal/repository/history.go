@@ -0,0 +1,12 @@
+package repository
+
+import "time"
+
+// StatusChange is one transition in the lifecycle of an asset
+type StatusChange struct {
+	From   string    `json:"from"` // empty for the initial status
+	To     string    `json:"to"`
+	Actor  string    `json:"actor"` // wallet address, or system
+	Reason string    `json:"reason,omitempty"`
+	At     time.Time `json:"at"`
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"math/big"
+	"sort"
+	"time"
+)
+
+// ZeroAddress is the source of mints and destination of burns, never a holder
+const ZeroAddress = "0x0000000000000000000000000000000000000000"
+
+// Transfer is a token transfer observed on chain. Addresses use their
+// EIP-55 form so that each account has a single key.
+type Transfer struct {
+	From      string
+	To        string
+	Value     *big.Int
+	Block     uint64
+	Timestamp time.Time // of the block, zero when unknown
+	TxHash    string
+	LogIndex  uint64
+}
+
+// Holder is an account with a non-zero balance of an asset
+type Holder struct {
+	Address string
+	Balance *big.Int
+}
+
+// HolderQuery selects a page of holders, largest balance first
+type HolderQuery struct {
+	Limit  int    // DefaultPageSize when zero, capped at MaxPageSize
+	Cursor string // NextCursor of the previous page
+}
+
+// HolderPage is one page of holders
+type HolderPage struct {
+	Holders    []Holder
+	NextCursor string   // empty on the last page
+	Count      int      // holders across every page
+	Total      *big.Int // combined balance of every holder
+}
+
+// sortHolder is the cursor sort order of holder pages
+const sortHolder = "balance"
+
+// applyTransfers adds the balance changes of transfers to balances.
+// Accounts whose balance drops to zero or below are removed.
+func applyTransfers(balances map[string]*big.Int, transfers []Transfer) {
+	for _, t := range transfers {
+		if t.From != ZeroAddress {
+			adjustBalance(balances, t.From, new(big.Int).Neg(t.Value))
+		}
+		if t.To != ZeroAddress {
+			adjustBalance(balances, t.To, t.Value)
+		}
+	}
+}
+
+func adjustBalance(balances map[string]*big.Int, address string, delta *big.Int) {
+	balance := new(big.Int).Add(balanceOf(balances, address), delta)
+	if balance.Sign() <= 0 {
+		delete(balances, address)
+		return
+	}
+	balances[address] = balance
+}
+
+func balanceOf(balances map[string]*big.Int, address string) *big.Int {
+	if b, ok := balances[address]; ok {
+		return b
+	}
+	return new(big.Int)
+}
+
+// sortHolders orders holders by balance, largest first, ties broken by address
+func sortHolders(holders []Holder) {
+	sort.Slice(holders, func(i, j int) bool {
+		return compareHolders(holders[i].Balance, holders[i].Address, holders[j].Balance, holders[j].Address) < 0
+	})
+}
+
+// compareHolders orders two (balance, address) positions, negative when a comes first
+func compareHolders(aBalance *big.Int, aAddress string, bBalance *big.Int, bAddress string) int {
+	if c := bBalance.Cmp(aBalance); c != 0 {
+		return c
+	}
+	switch {
+	case aAddress < bAddress:
+		return -1
+	case aAddress > bAddress:
+		return 1
+	}
+	return 0
+}
+
+// queryHolders pages through holders sorted by sortHolders
+func queryHolders(holders []Holder, q HolderQuery) (*HolderPage, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	limit = min(limit, MaxPageSize)
+
+	start := 0
+	if q.Cursor != "" {
+		after, err := decodeCursor(q.Cursor, sortHolder)
+		if err != nil {
+			return nil, err
+		}
+		afterBalance := decimalValue(after.Key)
+		start = sort.Search(len(holders), func(i int) bool {
+			return compareHolders(holders[i].Balance, holders[i].Address, afterBalance, after.ID) > 0
+		})
+	}
+
+	total := new(big.Int)
+	for _, h := range holders {
+		total.Add(total, h.Balance)
+	}
+
+	end := min(start+limit, len(holders))
+	page := &HolderPage{Holders: holders[start:end], Count: len(holders), Total: total}
+	if end < len(holders) {
+		last := holders[end-1]
+		page.NextCursor = encodeCursor(cursor{Sort: sortHolder, Key: last.Balance.String(), ID: last.Address})
+	}
+	return page, nil
+}
+
+// holderList converts a balance map into sorted holders
+func holderList(balances map[string]*big.Int) []Holder {
+	holders := make([]Holder, 0, len(balances))
+	for address, balance := range balances {
+		holders = append(holders, Holder{Address: address, Balance: new(big.Int).Set(balance)})
+	}
+	sortHolders(holders)
+	return holders
+}
@@ -0,0 +1,417 @@
+package repository
+
+import (
+	"maps"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
+)
+
+// MemoryAssetRepository is an in-memory AssetRepository used for tests
+// and when no database is configured
+type MemoryAssetRepository struct {
+	mu        sync.RWMutex
+	assets    map[string]client.Asset
+	balances  map[string]map[string]*big.Int // asset ID -> address -> balance
+	transfers map[string][]Transfer          // asset ID -> transfers, newest first
+	recorded  map[string]map[transferPosition]bool
+	accounts  map[string]map[string]*Account // asset ID -> lowercase address -> activity
+	history   map[string][]StatusChange      // asset ID -> status changes, oldest first
+	drafts    map[string]Draft
+	papers    map[string][]WhitepaperVersion // document -> versions, oldest first
+	indexed   map[string]uint64
+	symbols   map[string]map[string]bool // normalized symbol -> asset IDs
+	names     map[string]map[string]bool // normalized name -> asset IDs
+	creations map[string]string          // creation key -> asset ID
+}
+
+// NewMemoryAssetRepository creates an empty in-memory repository
+func NewMemoryAssetRepository() *MemoryAssetRepository {
+	return &MemoryAssetRepository{
+		assets:    make(map[string]client.Asset),
+		balances:  make(map[string]map[string]*big.Int),
+		transfers: make(map[string][]Transfer),
+		recorded:  make(map[string]map[transferPosition]bool),
+		accounts:  make(map[string]map[string]*Account),
+		history:   make(map[string][]StatusChange),
+		drafts:    make(map[string]Draft),
+		papers:    make(map[string][]WhitepaperVersion),
+		indexed:   make(map[string]uint64),
+		symbols:   make(map[string]map[string]bool),
+		names:     make(map[string]map[string]bool),
+		creations: make(map[string]string),
+	}
+}
+
+// Save inserts or replaces an asset
+func (r *MemoryAssetRepository) Save(asset client.Asset) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.put(asset)
+	return nil
+}
+
+// put stores asset and indexes its symbol and name. Called with r.mu held.
+func (r *MemoryAssetRepository) put(asset client.Asset) {
+	if old, ok := r.assets[asset.ID]; ok {
+		symbol, name := nameKeys(old)
+		delete(r.symbols[symbol], old.ID)
+		delete(r.names[name], old.ID)
+	}
+	r.assets[asset.ID] = asset
+
+	symbol, name := nameKeys(asset)
+	addToIndex(r.symbols, symbol, asset.ID)
+	addToIndex(r.names, name, asset.ID)
+}
+
+// addToIndex records that the asset id uses key
+func addToIndex(index map[string]map[string]bool, key, id string) {
+	if index[key] == nil {
+		index[key] = make(map[string]bool)
+	}
+	index[key][id] = true
+}
+
+// Get returns the asset with the given ID
+func (r *MemoryAssetRepository) Get(id string) (*client.Asset, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	asset, ok := r.assets[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &asset, nil
+}
+
+// List returns every stored asset, newest first
+func (r *MemoryAssetRepository) List() ([]client.Asset, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	assets := make([]client.Asset, 0, len(r.assets))
+	for _, asset := range r.assets {
+		assets = append(assets, asset)
+	}
+	sortAssets(assets)
+	return assets, nil
+}
+
+// Query returns a filtered, sorted page of assets
+func (r *MemoryAssetRepository) Query(q AssetQuery) (*AssetPage, error) {
+	assets, err := r.List()
+	if err != nil {
+		return nil, err
+	}
+	return queryAssets(assets, q)
+}
+
+// Update applies fn to an asset and saves it with its status change
+func (r *MemoryAssetRepository) Update(id string, fn func(asset *client.Asset) (*StatusChange, error)) (*client.Asset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	asset, ok := r.assets[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	change, err := fn(&asset)
+	if err != nil {
+		return nil, err
+	}
+	r.put(asset)
+	if change != nil {
+		r.history[id] = append(r.history[id], *change)
+	}
+	return &asset, nil
+}
+
+// RecordStatus appends a change to the status history of an asset
+func (r *MemoryAssetRepository) RecordStatus(assetID string, change StatusChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.history[assetID] = append(r.history[assetID], change)
+	return nil
+}
+
+// History returns the status changes of an asset, oldest first
+func (r *MemoryAssetRepository) History(assetID string) ([]StatusChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]StatusChange{}, r.history[assetID]...), nil
+}
+
+// AssetsWithSymbol returns the IDs of the assets using symbol
+func (r *MemoryAssetRepository) AssetsWithSymbol(symbol string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.Sorted(maps.Keys(r.symbols[NormalizeSymbol(symbol)])), nil
+}
+
+// AssetsWithName returns the IDs of the assets using name
+func (r *MemoryAssetRepository) AssetsWithName(name string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.Sorted(maps.Keys(r.names[NormalizeName(name)])), nil
+}
+
+// RecordCreation records the asset made by a creation
+func (r *MemoryAssetRepository) RecordCreation(key, assetID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.creations[key] = assetID
+	return nil
+}
+
+// Creation returns the ID of the asset made by a creation
+func (r *MemoryAssetRepository) Creation(key string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.creations[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return id, nil
+}
+
+// ApplyTransfers records transfers not recorded yet and applies their
+// balance changes up to block
+func (r *MemoryAssetRepository) ApplyTransfers(assetID string, transfers []Transfer, block uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen, ok := r.recorded[assetID]
+	if !ok {
+		seen = make(map[transferPosition]bool)
+		r.recorded[assetID] = seen
+	}
+	var added []Transfer
+	for _, t := range transfers {
+		position := transferPosition{block: t.Block, logIndex: t.LogIndex}
+		if seen[position] {
+			continue
+		}
+		seen[position] = true
+		added = append(added, t)
+	}
+	transfers = added
+
+	recorded := append(r.transfers[assetID], transfers...)
+	sortTransfers(recorded)
+	r.transfers[assetID] = recorded
+
+	balances, ok := r.balances[assetID]
+	if !ok {
+		balances = make(map[string]*big.Int)
+		r.balances[assetID] = balances
+	}
+	applyTransfers(balances, transfers)
+
+	accounts, ok := r.accounts[assetID]
+	if !ok {
+		accounts = make(map[string]*Account)
+		r.accounts[assetID] = accounts
+	}
+	observeTransfers(transfers, func(address string) (*Account, error) {
+		key := strings.ToLower(address)
+		if accounts[key] == nil {
+			accounts[key] = &Account{}
+		}
+		return accounts[key], nil
+	})
+	r.indexed[assetID] = block
+	return nil
+}
+
+// IndexedBlock returns the last block applied for an asset
+func (r *MemoryAssetRepository) IndexedBlock(assetID string) (uint64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.indexed[assetID], nil
+}
+
+// Holders returns every holder of an asset, largest balance first
+func (r *MemoryAssetRepository) Holders(assetID string) ([]Holder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return holderList(r.balances[assetID]), nil
+}
+
+// QueryHolders returns a page of holders
+func (r *MemoryAssetRepository) QueryHolders(assetID string, q HolderQuery) (*HolderPage, error) {
+	holders, err := r.Holders(assetID)
+	if err != nil {
+		return nil, err
+	}
+	return queryHolders(holders, q)
+}
+
+// QueryTransfers returns a filtered page of the transfers of an asset
+func (r *MemoryAssetRepository) QueryTransfers(assetID string, q TransferQuery) (*TransferPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return queryTransfers(r.transfers[assetID], q)
+}
+
+// Account returns the balance and transfer activity of address in an asset
+func (r *MemoryAssetRepository) Account(assetID, address string) (*Account, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	account := &Account{Balance: new(big.Int)}
+	if balance, ok := r.balances[assetID][address]; ok {
+		account.Balance.Set(balance)
+	}
+	if activity, ok := r.accounts[assetID][strings.ToLower(address)]; ok {
+		account.Transfers = activity.Transfers
+		first, last := *activity.First, *activity.Last
+		account.First, account.Last = &first, &last
+	}
+	return account, nil
+}
+
+// SaveDraft inserts or replaces a draft
+func (r *MemoryAssetRepository) SaveDraft(draft Draft) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.drafts[draft.ID] = draft
+	return nil
+}
+
+// GetDraft returns the draft with the given ID
+func (r *MemoryAssetRepository) GetDraft(id string) (*Draft, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	draft, ok := r.drafts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &draft, nil
+}
+
+// UpdateDraft applies fn to a draft and saves it
+func (r *MemoryAssetRepository) UpdateDraft(id string, fn func(draft *Draft) error) (*Draft, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	draft, ok := r.drafts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if err := fn(&draft); err != nil {
+		return nil, err
+	}
+	r.drafts[id] = draft
+	return &draft, nil
+}
+
+// ListDrafts returns the drafts of owner without their icons, most
+// recently updated first
+func (r *MemoryAssetRepository) ListDrafts(owner string) ([]Draft, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	drafts := []Draft{}
+	for _, draft := range r.drafts {
+		if draft.Owner == owner {
+			draft.IconData = ""
+			drafts = append(drafts, draft)
+		}
+	}
+	sortDrafts(drafts)
+	return drafts, nil
+}
+
+// DeleteDraft removes a draft
+func (r *MemoryAssetRepository) DeleteDraft(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.drafts[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.drafts, id)
+	return nil
+}
+
+// AddWhitepaper appends the next version of the whitepaper of doc, unless
+// it repeats the latest one, keeping the latest keep versions
+func (r *MemoryAssetRepository) AddWhitepaper(doc string, version WhitepaperVersion, keep int) (*WhitepaperVersion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	versions := r.papers[doc]
+	version.Version = 1
+	if len(versions) > 0 {
+		latest := versions[len(versions)-1]
+		if latest.ContentHash == version.ContentHash {
+			return &latest, nil
+		}
+		version.Version = latest.Version + 1
+	}
+	versions = append(versions, version)
+	if len(versions) > keep {
+		versions = append([]WhitepaperVersion{}, versions[len(versions)-keep:]...)
+	}
+	r.papers[doc] = versions
+	return &version, nil
+}
+
+// Whitepaper returns a version of the whitepaper of doc, the latest for 0
+func (r *MemoryAssetRepository) Whitepaper(doc string, version int) (*WhitepaperVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := r.papers[doc]
+	if len(versions) == 0 {
+		return nil, ErrNotFound
+	}
+	// Versions are numbered on from the oldest kept
+	i := len(versions) - 1
+	if version != 0 {
+		i = version - versions[0].Version
+	}
+	if i < 0 || i >= len(versions) {
+		return nil, ErrNotFound
+	}
+	v := versions[i]
+	return &v, nil
+}
+
+// Whitepapers returns every version of the whitepaper of doc, oldest first
+func (r *MemoryAssetRepository) Whitepapers(doc string) ([]WhitepaperVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]WhitepaperVersion{}, r.papers[doc]...), nil
+}
+
+// DeleteWhitepapers removes the whitepaper of doc
+func (r *MemoryAssetRepository) DeleteWhitepapers(doc string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.papers, doc)
+	return nil
+}
+
+// Close is a no-op for the in-memory repository
+func (r *MemoryAssetRepository) Close() error {
+	return nil
+}
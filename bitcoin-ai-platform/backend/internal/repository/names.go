@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
+)
+
+// NormalizeSymbol folds the case of a symbol, as symbols are compared
+func NormalizeSymbol(symbol string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol))
+}
+
+// NormalizeName folds the case and runs of whitespace of a name, as names
+// are compared
+func NormalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// nameKeys returns the symbol and name index keys of an asset
+func nameKeys(asset client.Asset) (symbol, name string) {
+	return NormalizeSymbol(asset.Symbol), NormalizeName(asset.Name)
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
+)
+
+// Sort orders accepted by AssetQuery. Every order is descending: newest,
+// most held or largest supply first, ties broken by ID.
+const (
+	SortCreatedAt = "createdAt"
+	SortHolders   = "holders"
+	SortSupply    = "supply"
+)
+
+// Page sizes of AssetQuery
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// ErrInvalidCursor is returned for a cursor not produced by the same sort order
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// AssetQuery selects a filtered, sorted page of assets
+type AssetQuery struct {
+	Status       string // exact match, case-insensitive
+	Owner        string // creator address, case-insensitive
+	SymbolPrefix string // case-insensitive
+	Sort         string // one of the Sort constants, SortCreatedAt when empty
+	Limit        int    // DefaultPageSize when zero, capped at MaxPageSize
+	Cursor       string // NextCursor of the previous page
+}
+
+// AssetPage is one page of a query result
+type AssetPage struct {
+	Assets     []client.Asset
+	NextCursor string // empty on the last page
+}
+
+// ValidSort reports whether sort is a supported order
+func ValidSort(sort string) bool {
+	switch sort {
+	case "", SortCreatedAt, SortHolders, SortSupply:
+		return true
+	}
+	return false
+}
+
+// cursor is the position after which the next page starts. It holds the
+// sort key of the last returned asset so inserts do not shift pages.
+type cursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k"`
+	ID   string `json:"id"`
+}
+
+// queryAssets applies q to a full asset listing
+func queryAssets(assets []client.Asset, q AssetQuery) (*AssetPage, error) {
+	sortBy := q.Sort
+	if sortBy == "" {
+		sortBy = SortCreatedAt
+	}
+	if !ValidSort(sortBy) {
+		return nil, errors.New("unsupported sort order " + strconv.Quote(sortBy))
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	limit = min(limit, MaxPageSize)
+
+	matched := make([]client.Asset, 0, len(assets))
+	for _, asset := range assets {
+		if q.matches(asset) {
+			matched = append(matched, asset)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return comparePositions(sortBy, sortKey(sortBy, matched[i]), matched[i].ID, sortKey(sortBy, matched[j]), matched[j].ID) < 0
+	})
+
+	start := 0
+	if q.Cursor != "" {
+		after, err := decodeCursor(q.Cursor, sortBy)
+		if err != nil {
+			return nil, err
+		}
+		start = sort.Search(len(matched), func(i int) bool {
+			return comparePositions(sortBy, sortKey(sortBy, matched[i]), matched[i].ID, after.Key, after.ID) > 0
+		})
+	}
+
+	end := min(start+limit, len(matched))
+	page := &AssetPage{Assets: matched[start:end]}
+	if end < len(matched) {
+		last := matched[end-1]
+		page.NextCursor = encodeCursor(cursor{Sort: sortBy, Key: sortKey(sortBy, last), ID: last.ID})
+	}
+	return page, nil
+}
+
+// matches reports whether asset passes the filters of q
+func (q AssetQuery) matches(asset client.Asset) bool {
+	if q.Status != "" && !strings.EqualFold(asset.Status, q.Status) {
+		return false
+	}
+	if q.Owner != "" && !strings.EqualFold(asset.CreatorAddress, q.Owner) {
+		return false
+	}
+	if q.SymbolPrefix != "" && !strings.HasPrefix(strings.ToUpper(asset.Symbol), strings.ToUpper(q.SymbolPrefix)) {
+		return false
+	}
+	return true
+}
+
+// sortKey returns the value asset is ordered by
+func sortKey(sortBy string, asset client.Asset) string {
+	switch sortBy {
+	case SortHolders:
+		return strconv.FormatInt(asset.Holders, 10)
+	case SortSupply:
+		return supplyKey(asset).String()
+	default:
+		return asset.CreatedAt
+	}
+}
+
+// comparePositions orders two (key, ID) positions, negative when a comes
+// first. Keys are compared descending, IDs ascending.
+func comparePositions(sortBy, aKey, aID, bKey, bID string) int {
+	var c int
+	switch sortBy {
+	case SortHolders, SortSupply:
+		c = decimalValue(bKey).Cmp(decimalValue(aKey))
+	default:
+		c = strings.Compare(bKey, aKey)
+	}
+	if c != 0 {
+		return c
+	}
+	return strings.Compare(aID, bID)
+}
+
+// supplyScale is the precision supplies are compared at, so that assets
+// with different decimals sort by their number of tokens
+const supplyScale = 18
+
+// supplyKey returns the total supply of asset in units of 10^-supplyScale
+// tokens
+func supplyKey(asset client.Asset) *big.Int {
+	n := decimalValue(asset.TotalSupply)
+	if asset.Decimals <= supplyScale {
+		return n.Mul(n, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(supplyScale-asset.Decimals)), nil))
+	}
+	return n.Quo(n, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(asset.Decimals-supplyScale)), nil))
+}
+
+// decimalValue parses a decimal amount, treating anything unparsable as zero
+func decimalValue(s string) *big.Int {
+	n, ok := new(big.Int).SetString(strings.ReplaceAll(s, ",", ""), 10)
+	if !ok {
+		return new(big.Int)
+	}
+	return n
+}
+
+// encodeCursor serializes c into an opaque token
+func encodeCursor(c cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a token from encodeCursor, checking it was made for sortBy
+func decodeCursor(token, sortBy string) (cursor, error) {
+	var c cursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || json.Unmarshal(data, &c) != nil || c.ID == "" {
+		return cursor{}, ErrInvalidCursor
+	}
+	if c.Sort != sortBy {
+		return cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
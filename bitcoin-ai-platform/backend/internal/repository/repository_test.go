@@ -0,0 +1,506 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
+	bolt "go.etcd.io/bbolt"
+)
+
+// testStores runs test against the memory store and a bolt store in a
+// temporary directory. reopen closes the store and opens it again, which
+// for the memory store keeps the same one.
+func testStores(t *testing.T, test func(t *testing.T, repo AssetRepository, reopen func() AssetRepository)) {
+	t.Run("memory", func(t *testing.T) {
+		repo := NewMemoryAssetRepository()
+		test(t, repo, func() AssetRepository { return repo })
+	})
+	t.Run("bolt", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "fansmint.db")
+		repo := openBolt(t, path)
+		t.Cleanup(func() { repo.Close() })
+		test(t, repo, func() AssetRepository {
+			if err := repo.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+			repo = openBolt(t, path)
+			return repo
+		})
+	})
+}
+
+func openBolt(t *testing.T, path string) *BoltAssetRepository {
+	t.Helper()
+
+	repo, err := NewBoltAssetRepository(path)
+	if err != nil {
+		t.Fatalf("NewBoltAssetRepository: %v", err)
+	}
+	return repo
+}
+
+// dropBuckets deletes buckets from repo, as a database from before they
+// existed would lack them
+func dropBuckets(t *testing.T, repo *BoltAssetRepository, buckets ...[]byte) {
+	t.Helper()
+
+	err := repo.db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range buckets {
+			if err := tx.DeleteBucket(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("delete buckets: %v", err)
+	}
+}
+
+var (
+	alice = "0xA11CE00000000000000000000000000000000001"
+	bob   = "0xB0B0000000000000000000000000000000000002"
+	carol = "0xCa40100000000000000000000000000000000003"
+)
+
+func TestAssets(t *testing.T) {
+	testStores(t, func(t *testing.T, repo AssetRepository, reopen func() AssetRepository) {
+		if _, err := repo.Get("a1"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get of a missing asset = %v, want ErrNotFound", err)
+		}
+		for i, symbol := range []string{"MOON", "STAR", "SUN"} {
+			asset := client.Asset{
+				ID:             fmt.Sprintf("a%d", i+1),
+				Name:           symbol + " Fans",
+				Symbol:         symbol,
+				Status:         "active",
+				CreatorAddress: alice,
+				CreatedAt:      fmt.Sprintf("2026-01-0%dT00:00:00Z", i+1),
+			}
+			if err := repo.Save(asset); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+		}
+
+		at := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+		updated, err := repo.Update("a2", func(asset *client.Asset) (*StatusChange, error) {
+			asset.Status = "paused"
+			return &StatusChange{From: "active", To: "paused", Actor: alice, At: at}, nil
+		})
+		if err != nil || updated.Status != "paused" {
+			t.Fatalf("Update = %+v, %v, want paused", updated, err)
+		}
+		failed := errors.New("refused")
+		if _, err := repo.Update("a3", func(asset *client.Asset) (*StatusChange, error) {
+			asset.Status = "retired"
+			return nil, failed
+		}); !errors.Is(err, failed) {
+			t.Errorf("failed Update = %v, want the error of fn", err)
+		}
+		if _, err := repo.Update("missing", func(*client.Asset) (*StatusChange, error) { return nil, nil }); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Update of a missing asset = %v, want ErrNotFound", err)
+		}
+
+		repo = reopen()
+		assets, err := repo.List()
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		var ids, statuses []string
+		for _, asset := range assets {
+			ids, statuses = append(ids, asset.ID), append(statuses, asset.Status)
+		}
+		if !slices.Equal(ids, []string{"a3", "a2", "a1"}) || !slices.Equal(statuses, []string{"active", "paused", "active"}) {
+			t.Errorf("List after reopen = %v %v, want a3 a2 a1 with a2 paused", ids, statuses)
+		}
+		history, err := repo.History("a2")
+		if err != nil || len(history) != 1 || history[0] != (StatusChange{From: "active", To: "paused", Actor: alice, At: at}) {
+			t.Errorf("History after reopen = %+v, %v, want the pause", history, err)
+		}
+
+		page, err := repo.Query(AssetQuery{SymbolPrefix: "s", Limit: 1})
+		if err != nil || len(page.Assets) != 1 || page.Assets[0].ID != "a3" || page.NextCursor == "" {
+			t.Fatalf("Query = %+v, %v, want a3 and a cursor", page, err)
+		}
+		if page, err = repo.Query(AssetQuery{SymbolPrefix: "s", Limit: 1, Cursor: page.NextCursor}); err != nil || len(page.Assets) != 1 || page.Assets[0].ID != "a2" || page.NextCursor != "" {
+			t.Errorf("second Query page = %+v, %v, want a2 alone", page, err)
+		}
+	})
+}
+
+func TestNameIndex(t *testing.T) {
+	testStores(t, func(t *testing.T, repo AssetRepository, reopen func() AssetRepository) {
+		repo.Save(client.Asset{ID: "a1", Symbol: "MOON", Name: "Moon  Fans"})
+		repo.Save(client.Asset{ID: "a2", Symbol: "MOONX", Name: "Moon"})
+		repo.Save(client.Asset{ID: "a3", Symbol: "moon", Name: "Other"})
+		// Renaming moves the index entries
+		repo.Update("a3", func(asset *client.Asset) (*StatusChange, error) {
+			asset.Symbol, asset.Name = "STAR", "moon fans"
+			return nil, nil
+		})
+		if err := repo.RecordCreation("0xa/key", "a1"); err != nil {
+			t.Fatalf("RecordCreation: %v", err)
+		}
+
+		check := func(repo AssetRepository) {
+			t.Helper()
+			for _, tt := range []struct {
+				lookup func(string) ([]string, error)
+				value  string
+				want   []string
+			}{
+				{repo.AssetsWithSymbol, " moon ", []string{"a1"}},
+				{repo.AssetsWithSymbol, "MoonX", []string{"a2"}},
+				{repo.AssetsWithSymbol, "star", []string{"a3"}},
+				{repo.AssetsWithSymbol, "MOO", []string{}},
+				{repo.AssetsWithName, "MOON fans", []string{"a1", "a3"}},
+				{repo.AssetsWithName, "moon", []string{"a2"}},
+				{repo.AssetsWithName, "other", []string{}},
+			} {
+				ids, err := tt.lookup(tt.value)
+				if err != nil || !slices.Equal(ids, tt.want) {
+					t.Errorf("lookup of %q = %v, %v, want %v", tt.value, ids, err, tt.want)
+				}
+			}
+			if id, err := repo.Creation("0xa/key"); err != nil || id != "a1" {
+				t.Errorf("Creation = %q, %v, want a1", id, err)
+			}
+			if _, err := repo.Creation("0xa/other"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Creation of an unknown key = %v, want ErrNotFound", err)
+			}
+		}
+		check(repo)
+		repo = reopen()
+		check(repo)
+
+		// Databases from before the index build it when opened
+		if b, ok := repo.(*BoltAssetRepository); ok {
+			dropBuckets(t, b, symbolsBucket, namesBucket)
+			check(reopen())
+		}
+	})
+}
+
+// randomTransfers returns transfers over blocks between a few accounts,
+// starting with mints, in block order
+func randomTransfers(rng *rand.Rand, blocks int) [][]Transfer {
+	accounts := []string{alice, bob, carol}
+	batches := make([][]Transfer, blocks)
+	for block := range batches {
+		for i := range rng.Intn(4) {
+			from := ZeroAddress
+			if block > 0 {
+				from = accounts[rng.Intn(len(accounts))]
+			}
+			batches[block] = append(batches[block], Transfer{
+				From:     from,
+				To:       accounts[rng.Intn(len(accounts))],
+				Value:    big.NewInt(1),
+				Block:    uint64(block + 1),
+				TxHash:   fmt.Sprintf("0x%02x%02x", block, i),
+				LogIndex: uint64(i),
+			})
+		}
+	}
+	return batches
+}
+
+// newestFirst returns the transfers of batches matching q, newest first
+func newestFirst(batches [][]Transfer, q TransferQuery) []Transfer {
+	var transfers []Transfer
+	for _, batch := range batches {
+		for _, t := range batch {
+			if matchesTransfer(t, q) {
+				transfers = append(transfers, t)
+			}
+		}
+	}
+	slices.Reverse(transfers)
+	return transfers
+}
+
+func TestTransfers(t *testing.T) {
+	batches := randomTransfers(rand.New(rand.NewSource(1)), 40)
+
+	testStores(t, func(t *testing.T, repo AssetRepository, reopen func() AssetRepository) {
+		for i, batch := range batches {
+			if err := repo.ApplyTransfers("a1", batch, uint64(i+1)); err != nil {
+				t.Fatalf("ApplyTransfers: %v", err)
+			}
+		}
+
+		check := func(repo AssetRepository) {
+			t.Helper()
+			if block, err := repo.IndexedBlock("a1"); err != nil || block != uint64(len(batches)) {
+				t.Errorf("IndexedBlock = %d, %v, want %d", block, err, len(batches))
+			}
+
+			// Every page follows on from the last, whatever the filters
+			for _, address := range []string{"", alice, strings.ToLower(bob), "0xdead"} {
+				for _, direction := range []string{"", DirectionIn, DirectionOut} {
+					for _, since := range []uint64{0, 20, 40} {
+						q := TransferQuery{Address: address, Direction: direction, SinceBlock: since, Limit: 3}
+						want := newestFirst(batches, q)
+						var got []Transfer
+						for pages := 0; ; pages++ {
+							page, err := repo.QueryTransfers("a1", q)
+							if err != nil {
+								t.Fatalf("QueryTransfers(%+v): %v", q, err)
+							}
+							if len(page.Transfers) > q.Limit || pages > len(want) {
+								t.Fatalf("QueryTransfers(%+v) returned %d transfers on page %d", q, len(page.Transfers), pages)
+							}
+							got = append(got, page.Transfers...)
+							if page.NextCursor == "" {
+								break
+							}
+							q.Cursor = page.NextCursor
+						}
+						if !reflect.DeepEqual(got, want) && len(got)+len(want) > 0 {
+							t.Errorf("transfers for %s %s since %d = %d, want %d", address, direction, since, len(got), len(want))
+						}
+					}
+				}
+			}
+			if _, err := repo.QueryTransfers("a1", TransferQuery{Cursor: "bogus"}); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("QueryTransfers with a bad cursor = %v, want ErrInvalidCursor", err)
+			}
+			if page, err := repo.QueryTransfers("a2", TransferQuery{}); err != nil || len(page.Transfers) != 0 {
+				t.Errorf("QueryTransfers of an unindexed asset = %+v, %v, want none", page, err)
+			}
+
+			for _, address := range []string{alice, bob, carol, "0xdead"} {
+				want := &Account{}
+				for _, batch := range batches {
+					for _, t := range batch {
+						want.observe(t, address)
+					}
+				}
+				account, err := repo.Account("a1", address)
+				if err != nil {
+					t.Fatalf("Account: %v", err)
+				}
+				balance := account.Balance
+				account.Balance = nil
+				if !reflect.DeepEqual(account, want) {
+					t.Errorf("activity of %s = %+v, want %+v", address, account, want)
+				}
+				if holders := holderBalance(t, repo, address); balance.Cmp(holders) != 0 {
+					t.Errorf("balance of %s = %s, holders say %s", address, balance, holders)
+				}
+			}
+		}
+		check(repo)
+		repo = reopen()
+		check(repo)
+
+		if b, ok := repo.(*BoltAssetRepository); ok {
+			dropBuckets(t, b, accountTransfersBucket, accountsBucket)
+			check(reopen())
+		}
+	})
+}
+
+// holderBalance returns the balance of address among the holders of a1
+func holderBalance(t *testing.T, repo AssetRepository, address string) *big.Int {
+	t.Helper()
+
+	page, err := repo.QueryHolders("a1", HolderQuery{Limit: MaxPageSize})
+	if err != nil {
+		t.Fatalf("QueryHolders: %v", err)
+	}
+	for _, h := range page.Holders {
+		if h.Address == address {
+			return h.Balance
+		}
+	}
+	return new(big.Int)
+}
+
+func TestHolders(t *testing.T) {
+	testStores(t, func(t *testing.T, repo AssetRepository, reopen func() AssetRepository) {
+		repo.ApplyTransfers("a1", []Transfer{
+			{From: ZeroAddress, To: alice, Value: big.NewInt(100), Block: 1},
+			{From: alice, To: bob, Value: big.NewInt(30), Block: 2},
+			{From: alice, To: carol, Value: big.NewInt(30), Block: 2, LogIndex: 1},
+			{From: bob, To: ZeroAddress, Value: big.NewInt(30), Block: 3},
+		}, 3)
+
+		repo = reopen()
+		page, err := repo.QueryHolders("a1", HolderQuery{Limit: 1})
+		if err != nil || len(page.Holders) != 1 || page.Holders[0].Address != alice || page.Count != 2 || page.Total.Int64() != 70 {
+			t.Fatalf("QueryHolders = %+v, %v, want alice of 2 holders of 70", page, err)
+		}
+		page, err = repo.QueryHolders("a1", HolderQuery{Limit: 1, Cursor: page.NextCursor})
+		if err != nil || len(page.Holders) != 1 || page.Holders[0].Address != carol || page.NextCursor != "" {
+			t.Errorf("second QueryHolders page = %+v, %v, want carol alone", page, err)
+		}
+	})
+}
+
+func TestTransferReplay(t *testing.T) {
+	testStores(t, func(t *testing.T, repo AssetRepository, reopen func() AssetRepository) {
+		mint := Transfer{From: ZeroAddress, To: alice, Value: big.NewInt(5), Block: 1}
+		send := Transfer{From: alice, To: bob, Value: big.NewInt(2), Block: 2}
+		apply := func(repo AssetRepository, transfers ...Transfer) {
+			t.Helper()
+			if err := repo.ApplyTransfers("a1", transfers, 2); err != nil {
+				t.Fatalf("ApplyTransfers: %v", err)
+			}
+		}
+
+		// A batch replayed, within itself, as-is and after a restart
+		apply(repo, mint, mint)
+		apply(repo, mint, send)
+		repo = reopen()
+		apply(repo, mint, send)
+
+		if got := holderBalance(t, repo, alice); got.Int64() != 3 {
+			t.Errorf("balance of alice = %s, want 3", got)
+		}
+		if got := holderBalance(t, repo, bob); got.Int64() != 2 {
+			t.Errorf("balance of bob = %s, want 2", got)
+		}
+		page, err := repo.QueryTransfers("a1", TransferQuery{Limit: MaxPageSize})
+		if err != nil || len(page.Transfers) != 2 {
+			t.Errorf("QueryTransfers = %+v, %v, want 2 transfers", page, err)
+		}
+		account, err := repo.Account("a1", alice)
+		if err != nil || account.Transfers != 2 || account.Balance.Int64() != 3 {
+			t.Errorf("Account(alice) = %+v, %v, want 2 transfers and a balance of 3", account, err)
+		}
+	})
+}
+
+func TestDrafts(t *testing.T) {
+	testStores(t, func(t *testing.T, repo AssetRepository, reopen func() AssetRepository) {
+		base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+		for i, owner := range []string{alice, bob, alice} {
+			draft := Draft{ID: fmt.Sprintf("d%d", i+1), Owner: owner, Name: "Draft", IconData: "data:image/png;base64,AA==", CreatedAt: base, UpdatedAt: base.Add(time.Duration(i) * time.Hour)}
+			if err := repo.SaveDraft(draft); err != nil {
+				t.Fatalf("SaveDraft: %v", err)
+			}
+		}
+		if _, err := repo.UpdateDraft("d1", func(draft *Draft) error {
+			draft.Step, draft.UpdatedAt = 2, base.Add(5*time.Hour)
+			return nil
+		}); err != nil {
+			t.Fatalf("UpdateDraft: %v", err)
+		}
+		if _, err := repo.UpdateDraft("missing", func(*Draft) error { return nil }); !errors.Is(err, ErrNotFound) {
+			t.Errorf("UpdateDraft of a missing draft = %v, want ErrNotFound", err)
+		}
+
+		repo = reopen()
+		drafts, err := repo.ListDrafts(alice)
+		if err != nil || len(drafts) != 2 || drafts[0].ID != "d1" || drafts[0].Step != 2 || drafts[1].ID != "d3" {
+			t.Errorf("ListDrafts after reopen = %+v, %v, want d1 at step 2 then d3", drafts, err)
+		}
+		if len(drafts) > 0 && drafts[0].IconData != "" {
+			t.Errorf("ListDrafts returned the icon %q, want it left out", drafts[0].IconData)
+		}
+		if draft, err := repo.GetDraft("d1"); err != nil || draft.IconData == "" {
+			t.Errorf("GetDraft = %+v, %v, want d1 with its icon", draft, err)
+		}
+		if b, ok := repo.(*BoltAssetRepository); ok {
+			dropBuckets(t, b, ownerDraftsBucket)
+			repo = reopen()
+			if drafts, err := repo.ListDrafts(alice); err != nil || len(drafts) != 2 {
+				t.Errorf("ListDrafts after rebuilding the owner index = %+v, %v, want 2 drafts", drafts, err)
+			}
+		}
+
+		// A draft changing hands moves in the index
+		if _, err := repo.UpdateDraft("d3", func(draft *Draft) error {
+			draft.Owner = carol
+			return nil
+		}); err != nil {
+			t.Fatalf("UpdateDraft: %v", err)
+		}
+		if drafts, _ := repo.ListDrafts(carol); len(drafts) != 1 || drafts[0].ID != "d3" {
+			t.Errorf("ListDrafts of the new owner = %+v, want d3", drafts)
+		}
+		if err := repo.DeleteDraft("d1"); err != nil {
+			t.Fatalf("DeleteDraft: %v", err)
+		}
+		if _, err := repo.GetDraft("d1"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetDraft of a deleted draft = %v, want ErrNotFound", err)
+		}
+		if err := repo.DeleteDraft("d1"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("second DeleteDraft = %v, want ErrNotFound", err)
+		}
+		if drafts, _ := repo.ListDrafts(bob); len(drafts) != 1 || drafts[0].ID != "d2" {
+			t.Errorf("ListDrafts of another owner = %+v, want d2", drafts)
+		}
+	})
+}
+
+func TestWhitepapers(t *testing.T) {
+	testStores(t, func(t *testing.T, repo AssetRepository, reopen func() AssetRepository) {
+		at := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+		for _, content := range []string{"one", "two", "two", "one"} {
+			if _, err := repo.AddWhitepaper("asset:a1", WhitepaperVersion{Content: content, ContentHash: "hash-" + content, CreatedAt: at}, 10); err != nil {
+				t.Fatalf("AddWhitepaper: %v", err)
+			}
+		}
+
+		repo = reopen()
+		// Repeating the latest version adds nothing, an older one does
+		versions, err := repo.Whitepapers("asset:a1")
+		var contents []string
+		for _, v := range versions {
+			contents = append(contents, fmt.Sprintf("%d:%s", v.Version, v.Content))
+		}
+		if err != nil || !slices.Equal(contents, []string{"1:one", "2:two", "3:one"}) {
+			t.Errorf("Whitepapers = %v, %v, want one, two, one", contents, err)
+		}
+		if latest, err := repo.Whitepaper("asset:a1", 0); err != nil || latest.Version != 3 {
+			t.Errorf("latest Whitepaper = %+v, %v, want version 3", latest, err)
+		}
+		if _, err := repo.Whitepaper("asset:a1", 4); !errors.Is(err, ErrNotFound) {
+			t.Errorf("missing Whitepaper version = %v, want ErrNotFound", err)
+		}
+
+		// Only the latest versions are kept
+		for i := 0; i < 5; i++ {
+			content := fmt.Sprint(i)
+			if _, err := repo.AddWhitepaper("draft:d1", WhitepaperVersion{Content: content, ContentHash: "hash-" + content, CreatedAt: at}, 2); err != nil {
+				t.Fatalf("AddWhitepaper: %v", err)
+			}
+		}
+		repo = reopen()
+		contents = nil
+		versions, err = repo.Whitepapers("draft:d1")
+		for _, v := range versions {
+			contents = append(contents, fmt.Sprintf("%d:%s", v.Version, v.Content))
+		}
+		if err != nil || !slices.Equal(contents, []string{"4:3", "5:4"}) {
+			t.Errorf("capped Whitepapers = %v, %v, want versions 4 and 5", contents, err)
+		}
+		if v, err := repo.Whitepaper("draft:d1", 4); err != nil || v.Content != "3" {
+			t.Errorf("Whitepaper version 4 = %+v, %v, want 3", v, err)
+		}
+		if _, err := repo.Whitepaper("draft:d1", 3); !errors.Is(err, ErrNotFound) {
+			t.Errorf("dropped Whitepaper version = %v, want ErrNotFound", err)
+		}
+		if latest, err := repo.Whitepaper("draft:d1", 0); err != nil || latest.Version != 5 {
+			t.Errorf("latest capped Whitepaper = %+v, %v, want version 5", latest, err)
+		}
+
+		if err := repo.DeleteWhitepapers("asset:a1"); err != nil {
+			t.Fatalf("DeleteWhitepapers: %v", err)
+		}
+		if _, err := repo.Whitepaper("asset:a1", 0); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Whitepaper after delete = %v, want ErrNotFound", err)
+		}
+	})
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"cmp"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Transfer directions relative to an account
+const (
+	DirectionIn  = "in"
+	DirectionOut = "out"
+)
+
+// TransferQuery selects a page of the recorded transfers of an asset,
+// newest first
+type TransferQuery struct {
+	Address    string // only transfers from or to this account, case-insensitive
+	Direction  string // with Address, DirectionIn or DirectionOut to keep one side
+	SinceBlock uint64 // only transfers in later blocks
+	Limit      int    // DefaultPageSize when zero, capped at MaxPageSize
+	Cursor     string // NextCursor of the previous page
+}
+
+// TransferPage is one page of transfers
+type TransferPage struct {
+	Transfers  []Transfer
+	NextCursor string // empty on the last page
+}
+
+// sortTransfer is the cursor sort order of transfer pages
+const sortTransfer = "transfer"
+
+// ValidDirection reports whether direction is a supported transfer direction
+func ValidDirection(direction string) bool {
+	switch direction {
+	case "", DirectionIn, DirectionOut:
+		return true
+	}
+	return false
+}
+
+// matchesTransfer reports whether t passes the filters of q
+func matchesTransfer(t Transfer, q TransferQuery) bool {
+	if t.Block <= q.SinceBlock {
+		return false
+	}
+	if q.Address == "" {
+		return true
+	}
+	in, out := strings.EqualFold(t.To, q.Address), strings.EqualFold(t.From, q.Address)
+	switch q.Direction {
+	case DirectionIn:
+		return in
+	case DirectionOut:
+		return out
+	}
+	return in || out
+}
+
+// sortTransfers orders transfers newest first, by block then log index
+func sortTransfers(transfers []Transfer) {
+	sort.Slice(transfers, func(i, j int) bool {
+		return compareTransfers(transfers[i].Block, transfers[i].LogIndex, transfers[j].Block, transfers[j].LogIndex) < 0
+	})
+}
+
+// compareTransfers orders two (block, log index) positions, negative when a
+// comes first
+func compareTransfers(aBlock, aIndex, bBlock, bIndex uint64) int {
+	if c := cmp.Compare(bBlock, aBlock); c != 0 {
+		return c
+	}
+	return cmp.Compare(bIndex, aIndex)
+}
+
+// queryTransfers filters and pages through transfers sorted by sortTransfers
+func queryTransfers(transfers []Transfer, q TransferQuery) (*TransferPage, error) {
+	pager, err := newTransferPager(q)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range transfers {
+		if pager.add(t) {
+			break
+		}
+	}
+	return pager.page, nil
+}
+
+// transferPager collects a page of transfers from a walk over them in
+// sortTransfers order
+type transferPager struct {
+	q     TransferQuery
+	limit int
+	// after is the position of the cursor, nil on the first page
+	after *transferPosition
+	page  *TransferPage
+}
+
+// transferPosition is the block and log index of a transfer
+type transferPosition struct {
+	block, logIndex uint64
+}
+
+// newTransferPager creates a transferPager for q, failing with
+// ErrInvalidCursor on a bad cursor
+func newTransferPager(q TransferQuery) (*transferPager, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	p := &transferPager{q: q, limit: min(limit, MaxPageSize), page: &TransferPage{Transfers: []Transfer{}}}
+
+	if q.Cursor != "" {
+		after, err := decodeCursor(q.Cursor, sortTransfer)
+		if err != nil {
+			return nil, err
+		}
+		var pos transferPosition
+		if pos.block, err = strconv.ParseUint(after.Key, 10, 64); err != nil {
+			return nil, ErrInvalidCursor
+		}
+		if pos.logIndex, err = strconv.ParseUint(after.ID, 10, 64); err != nil {
+			return nil, ErrInvalidCursor
+		}
+		p.after = &pos
+	}
+	return p, nil
+}
+
+// add offers the next transfer of the walk and reports whether the page is
+// complete, after which the walk can stop
+func (p *transferPager) add(t Transfer) bool {
+	if p.after != nil && compareTransfers(t.Block, t.LogIndex, p.after.block, p.after.logIndex) <= 0 {
+		return false
+	}
+	if !matchesTransfer(t, p.q) {
+		return false
+	}
+	if len(p.page.Transfers) == p.limit {
+		last := p.page.Transfers[p.limit-1]
+		p.page.NextCursor = encodeCursor(cursor{
+			Sort: sortTransfer,
+			Key:  strconv.FormatUint(last.Block, 10),
+			ID:   strconv.FormatUint(last.LogIndex, 10),
+		})
+		return true
+	}
+	p.page.Transfers = append(p.page.Transfers, t)
+	return false
+}
+
+// Account is the indexed activity of one address in an asset
+type Account struct {
+	Balance   *big.Int  `json:"-"` // zero when not held
+	Transfers int       // sent or received
+	First     *Transfer // oldest transfer, nil without any
+	Last      *Transfer // newest transfer, nil without any
+}
+
+// observe counts t in the activity of a, when it involves address
+func (a *Account) observe(t Transfer, address string) {
+	if !strings.EqualFold(t.From, address) && !strings.EqualFold(t.To, address) {
+		return
+	}
+	a.Transfers++
+	if a.First == nil || compareTransfers(t.Block, t.LogIndex, a.First.Block, a.First.LogIndex) > 0 {
+		first := t
+		a.First = &first
+	}
+	if a.Last == nil || compareTransfers(t.Block, t.LogIndex, a.Last.Block, a.Last.LogIndex) < 0 {
+		last := t
+		a.Last = &last
+	}
+}
+
+// observeTransfers counts each of transfers once in the activity of its
+// sender and of its recipient, as returned by account
+func observeTransfers(transfers []Transfer, account func(address string) (*Account, error)) error {
+	for _, t := range transfers {
+		addresses := []string{t.From}
+		if !strings.EqualFold(t.To, t.From) {
+			addresses = append(addresses, t.To)
+		}
+		for _, address := range addresses {
+			a, err := account(address)
+			if err != nil {
+				return err
+			}
+			a.observe(t, address)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "time"
+
+// WhitepaperVersion is one saved revision of a whitepaper
+type WhitepaperVersion struct {
+	Version     int       `json:"version"` // from 1
+	Content     string    `json:"content"` // markdown
+	ContentHash string    `json:"contentHash"`
+	Author      string    `json:"author"` // wallet address
+	CreatedAt   time.Time `json:"createdAt"`
+}
@@ -1,128 +1,96 @@
 package services
 
 import (
-	"log"
+	"context"
 
 	"github.com/yourusername/bitcoin-ai-platform/pkg/ai"
 )
 
 // AIService provides AI-related functionality
 type AIService struct {
-	openaiClient *ai.OpenAIClient
-	mockMode     bool
+	provider ai.Provider
+	symbols  *SymbolRegistry
 }
 
-// NewAIService creates a new AIService
-func NewAIService() *AIService {
-	client, err := ai.NewOpenAIClient()
-	if err != nil {
-		log.Printf("Warning: Failed to initialize OpenAI client: %v. Using mock mode.", err)
-		return &AIService{
-			openaiClient: nil,
-			mockMode:     true,
-		}
-	}
-
+// NewAIService creates a new AIService generating content with provider,
+// checking suggested symbols against symbols
+func NewAIService(provider ai.Provider, symbols *SymbolRegistry) *AIService {
 	return &AIService{
-		openaiClient: client,
-		mockMode:     false,
+		provider: provider,
+		symbols:  symbols,
 	}
 }
 
 // WhitepaperRequest represents a request to generate a whitepaper
 type WhitepaperRequest struct {
-	Name        string `json:"name"`
-	Symbol      string `json:"symbol"`
-	Description string `json:"description"`
-	UseCase     string `json:"useCase"`
-	TokenType   string `json:"tokenType"`
-	TotalSupply string `json:"totalSupply"`
+	Name        string `json:"name" form:"name" binding:"required"`
+	Symbol      string `json:"symbol" form:"symbol" binding:"required"`
+	Description string `json:"description" form:"description"`
+	UseCase     string `json:"useCase" form:"useCase" binding:"required"`
+	TokenType   string `json:"tokenType" form:"tokenType"`
+	TotalSupply string `json:"totalSupply" form:"totalSupply"`
 }
 
 // TokenSuggestionRequest represents a request to generate token suggestions
 type TokenSuggestionRequest struct {
-	UseCase string `json:"useCase"`
+	UseCase string `json:"useCase" binding:"required"`
 }
 
 // GenerateWhitepaper generates a whitepaper for a token
-func (s *AIService) GenerateWhitepaper(req WhitepaperRequest) (string, error) {
-	if s.mockMode {
-		return getMockWhitepaper(req.Name, req.Symbol, req.UseCase), nil
-	}
+func (s *AIService) GenerateWhitepaper(ctx context.Context, req WhitepaperRequest) (string, error) {
+	return s.provider.GenerateWhitepaper(ctx, req.params())
+}
 
-	return s.openaiClient.GenerateWhitepaper(
-		req.Name,
-		req.Symbol,
-		req.Description,
-		req.UseCase,
-		req.TokenType,
-		req.TotalSupply,
-	)
+// StreamWhitepaper generates a whitepaper for a token, calling onChunk with
+// each markdown fragment. Generation stops when ctx is cancelled.
+func (s *AIService) StreamWhitepaper(ctx context.Context, req WhitepaperRequest, onChunk func(string) error) (ai.Usage, error) {
+	return s.provider.StreamWhitepaper(ctx, req.params(), onChunk)
 }
 
-// GenerateTokenSuggestions generates token suggestions based on the use case
-func (s *AIService) GenerateTokenSuggestions(req TokenSuggestionRequest) (map[string]interface{}, error) {
-	if s.mockMode {
-		return getMockTokenSuggestion(req.UseCase), nil
+// params converts the request into provider parameters
+func (req WhitepaperRequest) params() ai.WhitepaperParams {
+	return ai.WhitepaperParams{
+		Name:        req.Name,
+		Symbol:      req.Symbol,
+		Description: req.Description,
+		UseCase:     req.UseCase,
+		TokenType:   req.TokenType,
+		TotalSupply: req.TotalSupply,
 	}
-
-	return s.openaiClient.GenerateTokenSuggestions(req.UseCase)
 }
 
-// getMockWhitepaper returns a mock whitepaper
-func getMockWhitepaper(name, symbol, useCase string) string {
-	return `# ` + name + ` Whitepaper
-
-## Abstract
-` + name + ` (` + symbol + `) is a digital asset based on the Bitcoin ecosystem, designed to ` + useCase + `.
-
-## 1. Introduction
-This whitepaper outlines the vision, technology, and roadmap for ` + name + `.
-
-## 2. Technical Architecture
-` + name + ` is built on the exSat protocol, inheriting Bitcoin's security and decentralization features.
-
-## 3. Tokenomics
-- Total Supply: 1,000,000 ` + symbol + `
-- Distribution:
-  * Team: 15%
-  * Community: 30% 
-  * Liquidity: 25%
-  * Ecosystem Development: 30%
-
-## 4. Use Cases
-` + useCase + `
-
-## 5. Roadmap
-- Phase 1: Token Issuance and Initial Distribution
-- Phase 2: Ecosystem Building and Partnership Expansion
-- Phase 3: Feature Extension and Use Case Implementation
-
-## 6. Team
-Our team consists of blockchain experts, security engineers, and industry advisors dedicated to building secure and efficient blockchain applications.
-
-## 7. Conclusion
-` + name + ` will provide innovative solutions for ` + useCase + `, and with the advantages of the exSat platform, we are confident in creating value within the Bitcoin ecosystem.
-`
+// TokenSuggestion is a generated suggestion and whether its symbol is free
+type TokenSuggestion struct {
+	ai.TokenSuggestion
+	Available bool   `json:"available"`
+	Reason    string `json:"reason"` // see SymbolAvailability
 }
 
-// getMockTokenSuggestion returns a mock token suggestion
-func getMockTokenSuggestion(useCase string) map[string]interface{} {
-	shortUseCase := useCase
-	if len(useCase) > 5 {
-		shortUseCase = useCase[:5]
+// GenerateTokenSuggestions generates ranked token suggestions based on the
+// use case, telling which suggested symbols a new asset may use
+func (s *AIService) GenerateTokenSuggestions(ctx context.Context, req TokenSuggestionRequest) ([]TokenSuggestion, error) {
+	generated, err := s.provider.GenerateTokenSuggestions(ctx, req.UseCase)
+	if err != nil {
+		return nil, err
 	}
 
-	shortSymbol := useCase
-	if len(useCase) > 3 {
-		shortSymbol = useCase[:3]
+	suggestions := make([]TokenSuggestion, 0, len(generated))
+	for _, g := range generated {
+		availability, err := s.symbols.Check(g.Symbol)
+		if err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, TokenSuggestion{TokenSuggestion: g, Available: availability.Available, Reason: availability.Reason})
 	}
+	return suggestions, nil
+}
 
-	return map[string]interface{}{
-		"name":            shortUseCase + "Token",
-		"symbol":          shortSymbol,
-		"description":     "A token focused on " + useCase + ", built on the Bitcoin ecosystem",
-		"useCase":         useCase,
-		"marketPotential": "This token has significant potential in the growing DeFi and cross-chain application space.",
-	}
+// Provider returns the name of the AI provider in use
+func (s *AIService) Provider() string {
+	return s.provider.Name()
+}
+
+// MockMode returns true if the service is running with the offline mock provider
+func (s *AIService) MockMode() bool {
+	return s.provider.Name() == ai.ProviderMock
 }
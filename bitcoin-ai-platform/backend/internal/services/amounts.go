@@ -0,0 +1,65 @@
+package services
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/yourusername/bitcoin-ai-platform/pkg/evm"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
+)
+
+// MaxDecimals is the highest precision an asset can be created with
+const MaxDecimals = 18
+
+// parseSupply validates the total supply and decimals of a creation
+// request, returning the supply in base units
+func parseSupply(req AssetCreationRequest, invalid *ValidationError) (uint8, *big.Int) {
+	decimals := DefaultTokenDecimals
+	if req.Decimals != nil {
+		decimals = *req.Decimals
+		if decimals < 0 || decimals > MaxDecimals {
+			invalid.Add("decimals", fmt.Sprintf("must be between 0 and %d", MaxDecimals))
+			return 0, nil
+		}
+	}
+
+	if strings.TrimSpace(req.TotalSupply) == "" {
+		invalid.Add("totalSupply", "is required")
+		return 0, nil
+	}
+	supply, err := evm.ParseUnits(req.TotalSupply, uint8(decimals))
+	switch {
+	case err != nil && strings.Contains(req.TotalSupply, "."):
+		invalid.Add("totalSupply", fmt.Sprintf("must be a number of tokens with at most %d decimal places", decimals))
+		return 0, nil
+	case err != nil:
+		invalid.Add("totalSupply", "must be a number of tokens, such as 1,000,000 or 2.5")
+		return 0, nil
+	case supply.Sign() == 0:
+		invalid.Add("totalSupply", "must be positive")
+		return 0, nil
+	}
+	if _, err := evm.EncodeUint256(supply); err != nil {
+		invalid.Add("totalSupply", "exceeds the largest uint256 amount of base units")
+		return 0, nil
+	}
+	return uint8(decimals), supply
+}
+
+// formatSupplies normalizes the supplies of asset to plain base units and
+// fills in their formatted forms. Supplies that cannot be parsed, such as
+// free-form values from exSat, are passed through unchanged.
+func formatSupplies(asset *client.Asset) {
+	asset.TotalSupply, asset.TotalSupplyFormatted = formatAmount(asset.TotalSupply, asset.Decimals)
+	asset.CirculatingSupply, asset.CirculatingSupplyFormatted = formatAmount(asset.CirculatingSupply, asset.Decimals)
+}
+
+// formatAmount returns an amount of base units and its formatted form
+func formatAmount(raw string, decimals uint8) (string, string) {
+	n, ok := new(big.Int).SetString(strings.ReplaceAll(strings.TrimSpace(raw), ",", ""), 10)
+	if !ok || n.Sign() < 0 {
+		return raw, raw
+	}
+	return n.String(), evm.FormatUnits(n, decimals)
+}
@@ -1,90 +1,947 @@
 package services
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"log"
-	"os"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/yourusername/bitcoin-ai-platform/internal/repository"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/address"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/evm"
 	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
 )
 
-// AssetService provides methods for managing assets
-type AssetService struct {
-	exSatClient *client.ExSatClient
+// ErrAssetNotFound is returned when an asset does not exist
+var ErrAssetNotFound = errors.New("asset not found")
+
+// AssetService manages the fan token assets created through FansMint
+type AssetService interface {
+	// CreateAsset validates the request, creates the asset and records it
+	CreateAsset(ctx context.Context, req AssetCreationRequest) (*client.Asset, error)
+	// PrepareAsset creates the asset and returns the unsigned transaction
+	// deploying its token from the owner's wallet
+	PrepareAsset(ctx context.Context, req AssetCreationRequest) (*PreparedAsset, error)
+	// SubmitDeployment records the deployment of an asset's token signed by
+	// its owner, who must be the caller
+	SubmitDeployment(ctx context.Context, id, caller string, sub DeploymentSubmission) (*client.Asset, error)
+	// GetAsset retrieves an asset by ID
+	GetAsset(ctx context.Context, id string) (*client.Asset, error)
+	// ListAssets retrieves a filtered, sorted page of assets
+	ListAssets(ctx context.Context, q repository.AssetQuery) (*repository.AssetPage, error)
+	// GetHolders retrieves a page of the indexed holders of an asset
+	GetHolders(ctx context.Context, id string, q repository.HolderQuery) (*HolderList, error)
+	// GetTransfers retrieves a page of the indexed transfers of an asset
+	GetTransfers(ctx context.Context, id string, q repository.TransferQuery) (*TransferList, error)
+	// ChangeStatus pauses, resumes or retires an asset on behalf of its
+	// owner, who must be the caller
+	ChangeStatus(ctx context.Context, id, caller, status, reason string) (*client.Asset, error)
+	// GetHistory retrieves the status changes of an asset
+	GetHistory(ctx context.Context, id string) (*StatusHistory, error)
+	// GetIcon returns the requested icon variant of an asset
+	GetIcon(id, variant string) (*Icon, error)
+	// SymbolAvailability reports whether a new asset may use symbol
+	SymbolAvailability(symbol string) (*SymbolAvailability, error)
+	// MockMode returns true when running without the exSat API
+	MockMode() bool
+}
+
+// assetService is the AssetService backed by exSat and the asset store
+type assetService struct {
+	exSat    client.ExSatAPI
+	assets   repository.AssetRepository
+	icons    *IconService
+	deployer *TokenDeployer // nil when on-chain deployment is disabled
+	symbols  *SymbolRegistry
+	network  address.Network
+	explorer string // block explorer base URL
+	mockMode bool
 }
 
 // AssetCreationRequest represents the data needed to create a new asset
 type AssetCreationRequest struct {
 	Name         string `json:"name"`
 	Symbol       string `json:"symbol"`
-	TotalSupply  string `json:"totalSupply"`
+	TotalSupply  string `json:"totalSupply"` // in whole tokens, such as "1,000,000" or "2.5"
+	Decimals     *int   `json:"decimals"`    // DefaultTokenDecimals when omitted
 	Description  string `json:"description"`
-	OwnerAddress string `json:"ownerAddress"`
+	OwnerAddress string `json:"ownerAddress"`       // EVM or Bitcoin, optional when signed in
 	IconData     string `json:"iconData,omitempty"` // base64 encoded image data
+
+	// Wallet is the signed-in wallet creating the asset, which owns it
+	Wallet string `json:"-"`
+
+	// IdempotencyKey, taken from the Idempotency-Key header, makes retried
+	// creations return the original asset instead of minting twice
+	IdempotencyKey string `json:"-"`
+}
+
+// PreparedAsset is a created asset with the transaction deploying its token
+type PreparedAsset struct {
+	Asset *client.Asset
+	// Transaction is nil once the deployment has been submitted
+	Transaction *UnsignedTransaction
+}
+
+// DeploymentSubmission is an owner-signed deployment, given either as the
+// raw signed transaction to broadcast or as the hash of one the wallet
+// already broadcast
+type DeploymentSubmission struct {
+	RawTransaction string `json:"rawTransaction"`
+	TxHash         string `json:"txHash"`
+}
+
+// HolderList is one page of the holders of an asset
+type HolderList struct {
+	AssetID      string       `json:"assetId"`
+	IndexedBlock uint64       `json:"indexedBlock"` // last block applied, 0 until indexed
+	HolderCount  int          `json:"holderCount"`
+	Holders      []HolderView `json:"holders"`
+	NextCursor   string       `json:"nextCursor"`
+}
+
+// HolderView is the balance of one holder
+type HolderView struct {
+	Address          string  `json:"address"`
+	Balance          string  `json:"balance"` // base units
+	BalanceFormatted string  `json:"balanceFormatted"`
+	Share            float64 `json:"share"` // fraction of the indexed supply held
+}
+
+// TransferList is one page of the transfers of an asset, newest first
+type TransferList struct {
+	AssetID      string         `json:"assetId"`
+	IndexedBlock uint64         `json:"indexedBlock"` // last block applied, 0 until indexed
+	Transfers    []TransferView `json:"transactions"`
+	NextCursor   string         `json:"nextCursor"`
+}
+
+// TransferView is one token transfer
+type TransferView struct {
+	BlockNumber     uint64 `json:"blockNumber"`
+	Timestamp       string `json:"timestamp,omitempty"` // RFC 3339, of the block
+	From            string `json:"from"`
+	To              string `json:"to"`
+	Amount          string `json:"amount"` // base units
+	AmountFormatted string `json:"amountFormatted"`
+	TxHash          string `json:"txHash"`
+	LogIndex        uint64 `json:"logIndex"`
+	// Direction is in or out relative to the queried address, if any
+	Direction   string `json:"direction,omitempty"`
+	ExplorerURL string `json:"explorerUrl"`
+}
+
+// StatusHistory is the audit trail of the lifecycle of an asset
+type StatusHistory struct {
+	AssetID string                    `json:"assetId"`
+	Status  string                    `json:"status"`
+	Changes []repository.StatusChange `json:"history"` // oldest first
+}
+
+// MaxReasonLength bounds the reason given for a status change
+const MaxReasonLength = 500
+
+// ownerTransitions lists, for each status an owner may move an asset to,
+// the statuses it may do so from
+var ownerTransitions = map[string][]string{
+	StatusPaused:  {StatusActive},
+	StatusActive:  {StatusPaused},
+	StatusRetired: {StatusPendingReview, StatusActive, StatusPaused, StatusFailed},
+}
+
+// NewAssetService creates a new AssetService.
+// In mock mode exSat is never called and an empty store is seeded with examples.
+// Symbols and names must be free in symbols, and Bitcoin owner addresses
+// must belong to network. Transfers link to transactions on explorer.
+// When deployer is set, token contracts are deployed through it: for every
+// new asset when it holds a server key, or on request from owners' wallets.
+func NewAssetService(exSat client.ExSatAPI, assets repository.AssetRepository, icons *IconService, deployer *TokenDeployer, symbols *SymbolRegistry, network address.Network, explorer string, mockMode bool) AssetService {
+	s := &assetService{
+		exSat:    exSat,
+		assets:   assets,
+		icons:    icons,
+		deployer: deployer,
+		symbols:  symbols,
+		network:  network,
+		explorer: strings.TrimRight(explorer, "/"),
+		mockMode: mockMode,
+	}
+
+	if s.MockMode() {
+		s.seedMockAssets()
+	}
+
+	return s
+}
+
+// CreateAsset creates a new asset on exSat and records it in the asset
+// store. When the deployer holds a server key its token is deployed too.
+func (s *assetService) CreateAsset(ctx context.Context, req AssetCreationRequest) (*client.Asset, error) {
+	serverSigned := s.deployer != nil && s.deployer.ServerSigned()
+	asset, err := s.create(ctx, req, serverSigned)
+	if err != nil {
+		return nil, err
+	}
+
+	// A retried creation keeps the deployment and status already recorded
+	stored, _ := s.assets.Get(asset.ID)
+	if stored != nil {
+		keepLifecycle(asset, stored)
+	} else {
+		if serverSigned {
+			s.deploy(ctx, asset)
+		}
+		asset.Status = deploymentStatus(asset.Deployment)
+	}
+
+	// The asset already exists upstream at this point, so a store failure
+	// must not be reported as a failed creation
+	if err := s.assets.Save(*asset); err != nil {
+		log.Printf("Warning: Failed to store asset %s: %v", asset.ID, err)
+	} else if stored == nil {
+		s.recordCreation(asset)
+	}
+
+	return asset, nil
+}
+
+// PrepareAsset creates a new asset like CreateAsset and returns the unsigned
+// factory transaction deploying its token, for the owner to sign
+func (s *assetService) PrepareAsset(ctx context.Context, req AssetCreationRequest) (*PreparedAsset, error) {
+	if s.deployer == nil {
+		return nil, ErrDeploymentDisabled
+	}
+
+	asset, err := s.create(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// A retried preparation keeps the deployment and status already recorded
+	stored, _ := s.assets.Get(asset.ID)
+	if stored != nil && stored.Deployment != nil {
+		keepLifecycle(asset, stored)
+	} else {
+		asset.Deployment = &client.Deployment{
+			Status:    client.DeploymentAwaitingSignature,
+			Factory:   s.deployer.factory.Hex(),
+			UpdatedAt: time.Now().UTC().Format(time.RFC3339),
+		}
+		asset.Status = deploymentStatus(asset.Deployment)
+	}
+	if err := s.assets.Save(*asset); err != nil {
+		return nil, fmt.Errorf("error writing asset store: %w", err)
+	}
+	if stored == nil {
+		s.recordCreation(asset)
+	}
+
+	prepared := &PreparedAsset{Asset: asset}
+	switch asset.Deployment.Status {
+	case client.DeploymentAwaitingSignature, client.DeploymentFailed:
+		// A retired asset is never deployed
+		if !canTransition(asset.Status, StatusDeploying) {
+			break
+		}
+		if prepared.Transaction, err = s.deployer.Prepare(ctx, *asset); err != nil {
+			return nil, err
+		}
+	}
+	return prepared, nil
 }
 
-// NewAssetService creates a new instance of AssetService
-func NewAssetService() *AssetService {
-	baseURL := os.Getenv("EXSAT_API_URL")
-	if baseURL == "" {
-		baseURL = "https://api.exsat.network" // Default URL
-		log.Println("EXSAT_API_URL not set, using default:", baseURL)
+// SubmitDeployment records the owner-signed deployment of the token of an
+// asset, broadcasting it first when given as a raw transaction
+func (s *assetService) SubmitDeployment(ctx context.Context, id, caller string, sub DeploymentSubmission) (*client.Asset, error) {
+	if s.deployer == nil {
+		return nil, ErrDeploymentDisabled
+	}
+
+	raw, txHash := strings.TrimSpace(sub.RawTransaction), strings.TrimSpace(sub.TxHash)
+	var invalid ValidationError
+	if id == "" {
+		invalid.Add("assetId", "is required")
+	}
+	if (raw == "") == (txHash == "") {
+		invalid.Add("rawTransaction", "exactly one of rawTransaction or txHash is required")
+	}
+	var rawBytes []byte
+	if raw != "" {
+		var err error
+		if rawBytes, err = hex.DecodeString(strings.TrimPrefix(raw, "0x")); err != nil || len(rawBytes) == 0 {
+			invalid.Add("rawTransaction", "must be 0x-prefixed hex")
+		}
+	}
+	if txHash != "" {
+		if _, err := evm.ParseHash(txHash); err != nil {
+			invalid.Add("txHash", "must be a 0x-prefixed 64 digit hex hash")
+		}
+	}
+	if err := invalid.Err(); err != nil {
+		return nil, err
+	}
+
+	asset, err := s.GetAsset(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(caller, asset.CreatorAddress) {
+		return nil, ErrNotAssetOwner
 	}
 
-	apiKey := os.Getenv("EXSAT_API_KEY")
-	if apiKey == "" {
-		log.Println("Warning: EXSAT_API_KEY not set. API calls may fail.")
+	if asset.Deployment != nil {
+		switch asset.Deployment.Status {
+		case client.DeploymentAwaitingSignature, client.DeploymentFailed:
+		case client.DeploymentPending:
+			// Resubmitting the pending transaction is harmless
+			if rawBytes != nil {
+				txHash = evm.Keccak256(rawBytes).Hex()
+			}
+			if strings.EqualFold(txHash, asset.Deployment.TxHash) {
+				return asset, nil
+			}
+			return nil, fmt.Errorf("%w: transaction %s is already pending", ErrDeploymentConflict, asset.Deployment.TxHash)
+		default:
+			return nil, fmt.Errorf("%w: deployment is %s", ErrDeploymentConflict, asset.Deployment.Status)
+		}
+	}
+	if !canTransition(asset.Status, StatusDeploying) {
+		return nil, fmt.Errorf("%w: asset is %s, cannot become %s", ErrInvalidTransition, asset.Status, StatusDeploying)
 	}
 
-	return &AssetService{
-		exSatClient: client.NewExSatClient(baseURL, apiKey),
+	var deployment *client.Deployment
+	if rawBytes != nil {
+		deployment, err = s.deployer.SubmitRaw(ctx, *asset, rawBytes)
+	} else {
+		deployment, err = s.deployer.SubmitHash(ctx, *asset, txHash)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.assets.Update(asset.ID, func(stored *client.Asset) (*repository.StatusChange, error) {
+		stored.Deployment = deployment
+		return transition(stored, StatusDeploying, caller, "")
+	})
+	if errors.Is(err, ErrInvalidTransition) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error writing asset store: %w", err)
+	}
+	formatSupplies(updated)
+	return updated, nil
 }
 
-// CreateAsset creates a new asset on exSat
-func (s *AssetService) CreateAsset(req AssetCreationRequest) (*client.Asset, error) {
+// create validates the request and creates the asset upstream, storing its
+// icon. onChain requires what deploying its token needs.
+func (s *assetService) create(ctx context.Context, req AssetCreationRequest, onChain bool) (*client.Asset, error) {
+	var invalid ValidationError
 	if req.Name == "" {
-		return nil, errors.New("asset name is required")
+		invalid.Add("name", "is required")
 	}
 	if req.Symbol == "" {
-		return nil, errors.New("asset symbol is required")
+		invalid.Add("symbol", "is required")
+	}
+	decimals, supply := parseSupply(req, &invalid)
+	owner, bitcoin := s.resolveOwner(req, &invalid)
+	// Deployment needs an EVM owner
+	if onChain && owner != "" && owner == bitcoin {
+		invalid.Add("ownerAddress", "must be an EVM address to deploy the token")
+	}
+	if err := invalid.Err(); err != nil {
+		return nil, err
+	}
+	req.Symbol = strings.TrimSpace(req.Symbol)
+	req.OwnerAddress = owner
+	req.TotalSupply = supply.String()
+
+	// A retry of a creation that already made its asset gets that asset
+	// back rather than a second one
+	holder := reservationHolder(req)
+	if holder != "" {
+		created, err := s.createdBy(holder)
+		if err != nil || created != nil {
+			return created, err
+		}
+	}
+
+	// Hold the symbol and name until the asset is stored
+	release, err := s.symbols.Reserve(req.Symbol, req.Name, holder)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reject a bad icon before anything is created upstream
+	var icon *ProcessedIcon
+	if req.IconData != "" {
+		icon, err = s.icons.Process(req.IconData)
+		if err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	var asset *client.Asset
+	if s.MockMode() {
+		asset = newMockAsset(req, decimals)
+	} else {
+		params := client.AssetCreateParams{
+			Name:           req.Name,
+			Symbol:         req.Symbol,
+			TotalSupply:    req.TotalSupply,
+			Decimals:       decimals,
+			Description:    req.Description,
+			OwnerAddress:   req.OwnerAddress,
+			IconData:       req.IconData,
+			IdempotencyKey: req.IdempotencyKey,
+		}
+
+		created, err := s.exSat.CreateAsset(ctx, params)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		asset = created
+		if asset.CreatorAddress == "" {
+			asset.CreatorAddress = req.OwnerAddress
+		}
 	}
-	if req.TotalSupply == "" {
-		return nil, errors.New("total supply is required")
+	// Retries past the reservation may then still use its symbol and name
+	if holder != "" {
+		if err := s.assets.RecordCreation(holder, asset.ID); err != nil {
+			log.Printf("Warning: Failed to record creation of asset %s: %v", asset.ID, err)
+		}
 	}
-	if req.OwnerAddress == "" {
-		return nil, errors.New("owner address is required")
+	asset.TotalSupply = req.TotalSupply
+	asset.Decimals = decimals
+	asset.OwnerBitcoinAddress = bitcoin
+	formatSupplies(asset)
+
+	if icon != nil {
+		if err := s.icons.Save(asset.ID, icon); err != nil {
+			log.Printf("Warning: Failed to store icon for asset %s: %v", asset.ID, err)
+		} else {
+			asset.IconUrl = fmt.Sprintf("/api/v1/assets/%s/icon", asset.ID)
+		}
 	}
+	return asset, nil
+}
 
-	// In a real implementation, we might validate the address format here
+// createdBy returns the stored asset made by the creation holder, or nil
+// when it has not made one
+func (s *assetService) createdBy(holder string) (*client.Asset, error) {
+	id, err := s.assets.Creation(holder)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading asset store: %w", err)
+	}
+	asset, err := s.assets.Get(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading asset store: %w", err)
+	}
+	formatSupplies(asset)
+	return asset, nil
+}
 
-	params := client.AssetCreateParams{
-		Name:         req.Name,
-		Symbol:       req.Symbol,
-		TotalSupply:  req.TotalSupply,
-		Description:  req.Description,
-		OwnerAddress: req.OwnerAddress,
-		IconData:     req.IconData,
+// resolveOwner returns the normalized owner of a new asset and the
+// Bitcoin address it was given, if any. The signed-in wallet owns the
+// asset: an EVM ownerAddress must be that wallet, while a Bitcoin one is
+// recorded as the owner's address on the Bitcoin side of exSat.
+func (s *assetService) resolveOwner(req AssetCreationRequest, invalid *ValidationError) (owner, bitcoin string) {
+	given := strings.TrimSpace(req.OwnerAddress)
+	if given == "" {
+		if req.Wallet == "" {
+			invalid.Add("ownerAddress", "is required")
+		}
+		return req.Wallet, ""
 	}
 
-	return s.exSatClient.CreateAsset(params)
+	a, err := address.Parse(given, s.network)
+	if err != nil {
+		invalid.Add("ownerAddress", err.Error())
+		return "", ""
+	}
+	if a.IsBitcoin() {
+		if req.Wallet == "" {
+			return a.Normalized, a.Normalized
+		}
+		return req.Wallet, a.Normalized
+	}
+	if req.Wallet != "" && a.Normalized != req.Wallet {
+		invalid.Add("ownerAddress", "must be the signed-in wallet "+req.Wallet)
+	}
+	return a.Normalized, ""
+}
+
+// deploy submits the server-signed deployment of the token contract of
+// asset. A submission failure is recorded as a failed deployment since the
+// asset itself exists.
+func (s *assetService) deploy(ctx context.Context, asset *client.Asset) {
+	deployment, err := s.deployer.Deploy(ctx, *asset)
+	if err != nil {
+		log.Printf("Warning: Failed to deploy token of asset %s: %v", asset.ID, err)
+		now := time.Now().UTC().Format(time.RFC3339)
+		deployment = &client.Deployment{
+			Status:      client.DeploymentFailed,
+			Factory:     s.deployer.factory.Hex(),
+			Error:       err.Error(),
+			SubmittedAt: now,
+			UpdatedAt:   now,
+		}
+	}
+	asset.Deployment = deployment
 }
 
-// GetAsset retrieves an asset by ID
-func (s *AssetService) GetAsset(id string) (*client.Asset, error) {
+// keepLifecycle carries the deployment, status and whitepaper recorded for
+// an asset over to a retry of its creation
+func keepLifecycle(asset, stored *client.Asset) {
+	asset.Whitepaper = stored.Whitepaper
+	if stored.Deployment != nil {
+		asset.Deployment = stored.Deployment
+		asset.ContractAddress = stored.ContractAddress
+	}
+	asset.Status = stored.Status
+	if !ValidStatus(asset.Status) {
+		asset.Status = deploymentStatus(asset.Deployment)
+	}
+}
+
+// recordCreation starts the status history of a new asset
+func (s *assetService) recordCreation(asset *client.Asset) {
+	change := repository.StatusChange{To: asset.Status, Actor: asset.CreatorAddress, At: time.Now().UTC()}
+	if asset.Deployment != nil && asset.Deployment.Status == client.DeploymentFailed {
+		change.Reason = asset.Deployment.Error
+	}
+	if err := s.assets.RecordStatus(asset.ID, change); err != nil {
+		log.Printf("Warning: Failed to record status of asset %s: %v", asset.ID, err)
+	}
+}
+
+// GetAsset retrieves an asset by ID.
+// When live, the exSat copy is refreshed into the store; the stored copy is
+// served if exSat cannot be reached.
+func (s *assetService) GetAsset(ctx context.Context, id string) (*client.Asset, error) {
 	if id == "" {
-		return nil, errors.New("asset ID is required")
+		return nil, &ValidationError{Fields: []FieldError{{Field: "id", Message: "is required"}}}
+	}
+
+	stored, err := s.assets.Get(id)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("error reading asset store: %w", err)
+	}
+	if stored != nil {
+		formatSupplies(stored)
+	}
+
+	if s.MockMode() {
+		if stored == nil {
+			return nil, ErrAssetNotFound
+		}
+		return stored, nil
+	}
+
+	remote, err := s.exSat.GetAsset(ctx, id)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			return nil, ErrAssetNotFound
+		}
+		// Only fall back when exSat failed, not when the caller gave up
+		if stored != nil && ctx.Err() == nil {
+			log.Printf("Warning: Failed to refresh asset %s from exSat, serving stored copy: %v", id, err)
+			return stored, nil
+		}
+		return nil, err
+	}
+
+	asset := reconcile(s.assets, *remote)
+	return &asset, nil
+}
+
+// ListAssets retrieves a filtered, sorted page of assets from the store.
+// When live, AssetSync keeps the store up to date with the exSat listing.
+func (s *assetService) ListAssets(ctx context.Context, q repository.AssetQuery) (*repository.AssetPage, error) {
+	var invalid ValidationError
+	if !repository.ValidSort(q.Sort) {
+		invalid.Add("sort", "must be one of createdAt, holders or supply")
+	}
+	if q.Limit < 0 || q.Limit > repository.MaxPageSize {
+		invalid.Add("limit", fmt.Sprintf("must be between 1 and %d", repository.MaxPageSize))
+	}
+	if err := invalid.Err(); err != nil {
+		return nil, err
+	}
+
+	page, err := s.assets.Query(q)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return nil, &ValidationError{Fields: []FieldError{{Field: "cursor", Message: "is not a cursor returned for this sort order"}}}
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range page.Assets {
+		formatSupplies(&page.Assets[i])
+	}
+	return page, nil
+}
+
+// GetHolders retrieves a page of the holders of an asset, largest balance
+// first, as indexed from its Transfer logs
+func (s *assetService) GetHolders(ctx context.Context, id string, q repository.HolderQuery) (*HolderList, error) {
+	if q.Limit < 0 || q.Limit > repository.MaxPageSize {
+		return nil, &ValidationError{Fields: []FieldError{{Field: "limit", Message: fmt.Sprintf("must be between 1 and %d", repository.MaxPageSize)}}}
+	}
+
+	asset, err := s.GetAsset(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	indexed, err := s.assets.IndexedBlock(asset.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading asset store: %w", err)
+	}
+	page, err := s.assets.QueryHolders(asset.ID, q)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return nil, &ValidationError{Fields: []FieldError{{Field: "cursor", Message: "is not a cursor returned for this listing"}}}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading asset store: %w", err)
 	}
 
-	return s.exSatClient.GetAsset(id)
+	list := &HolderList{
+		AssetID:      asset.ID,
+		IndexedBlock: indexed,
+		HolderCount:  page.Count,
+		Holders:      make([]HolderView, 0, len(page.Holders)),
+		NextCursor:   page.NextCursor,
+	}
+	total := new(big.Float).SetInt(page.Total)
+	for _, h := range page.Holders {
+		share, _ := new(big.Float).Quo(new(big.Float).SetInt(h.Balance), total).Float64()
+		list.Holders = append(list.Holders, HolderView{
+			Address:          h.Address,
+			Balance:          h.Balance.String(),
+			BalanceFormatted: evm.FormatUnits(h.Balance, asset.Decimals),
+			Share:            share,
+		})
+	}
+	return list, nil
 }
 
-// GetAssets retrieves all assets
-func (s *AssetService) GetAssets() ([]client.Asset, error) {
-	return s.exSatClient.GetAssets()
+// GetTransfers retrieves a page of the transfers of an asset, newest first,
+// as indexed from its Transfer logs
+func (s *assetService) GetTransfers(ctx context.Context, id string, q repository.TransferQuery) (*TransferList, error) {
+	var invalid ValidationError
+	if q.Limit < 0 || q.Limit > repository.MaxPageSize {
+		invalid.Add("limit", fmt.Sprintf("must be between 1 and %d", repository.MaxPageSize))
+	}
+	if q.Address != "" {
+		account, err := evm.ParseAddress(q.Address)
+		if err != nil {
+			invalid.Add("address", "must be a 0x-prefixed 40 digit hex address")
+		} else {
+			q.Address = account.Hex()
+		}
+	}
+	switch {
+	case !repository.ValidDirection(q.Direction):
+		invalid.Add("direction", "must be in or out")
+	case q.Direction != "" && q.Address == "":
+		invalid.Add("direction", "requires an address")
+	}
+	if err := invalid.Err(); err != nil {
+		return nil, err
+	}
+
+	asset, err := s.GetAsset(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	indexed, err := s.assets.IndexedBlock(asset.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading asset store: %w", err)
+	}
+	page, err := s.assets.QueryTransfers(asset.ID, q)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return nil, &ValidationError{Fields: []FieldError{{Field: "cursor", Message: "is not a cursor returned for this listing"}}}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading asset store: %w", err)
+	}
+
+	list := &TransferList{
+		AssetID:      asset.ID,
+		IndexedBlock: indexed,
+		Transfers:    make([]TransferView, 0, len(page.Transfers)),
+		NextCursor:   page.NextCursor,
+	}
+	for _, t := range page.Transfers {
+		view := newTransferView(t, asset.Decimals, s.explorer)
+		switch {
+		case q.Direction != "":
+			view.Direction = q.Direction
+		case q.Address != "":
+			// Unfiltered, a transfer to oneself counts as incoming
+			view.Direction = repository.DirectionOut
+			if strings.EqualFold(t.To, q.Address) {
+				view.Direction = repository.DirectionIn
+			}
+		}
+		list.Transfers = append(list.Transfers, view)
+	}
+	return list, nil
+}
+
+// newTransferView presents t, linking it on explorer
+func newTransferView(t repository.Transfer, decimals uint8, explorer string) TransferView {
+	view := TransferView{
+		BlockNumber:     t.Block,
+		From:            t.From,
+		To:              t.To,
+		Amount:          t.Value.String(),
+		AmountFormatted: evm.FormatUnits(t.Value, decimals),
+		TxHash:          t.TxHash,
+		LogIndex:        t.LogIndex,
+		ExplorerURL:     explorer + "/tx/" + t.TxHash,
+	}
+	if !t.Timestamp.IsZero() {
+		view.Timestamp = t.Timestamp.UTC().Format(time.RFC3339)
+	}
+	return view
+}
+
+// ChangeStatus moves an asset to status on behalf of its owner: paused to
+// pause it, active to resume it once paused, or retired to end it for good
+func (s *assetService) ChangeStatus(ctx context.Context, id, caller, status, reason string) (*client.Asset, error) {
+	var invalid ValidationError
+	from, ok := ownerTransitions[status]
+	if !ok {
+		invalid.Add("status", "must be paused, active or retired")
+	}
+	reason = strings.TrimSpace(reason)
+	if len(reason) > MaxReasonLength {
+		invalid.Add("reason", fmt.Sprintf("must be at most %d characters", MaxReasonLength))
+	}
+	if err := invalid.Err(); err != nil {
+		return nil, err
+	}
+
+	asset, err := s.GetAsset(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(caller, asset.CreatorAddress) {
+		return nil, ErrNotAssetOwner
+	}
+
+	updated, err := s.assets.Update(asset.ID, func(stored *client.Asset) (*repository.StatusChange, error) {
+		if !slices.Contains(from, stored.Status) {
+			return nil, fmt.Errorf("%w: asset is %s, cannot become %s", ErrInvalidTransition, stored.Status, status)
+		}
+		return transition(stored, status, caller, reason)
+	})
+	if errors.Is(err, ErrInvalidTransition) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error writing asset store: %w", err)
+	}
+	formatSupplies(updated)
+	return updated, nil
+}
+
+// GetHistory retrieves the status changes of an asset, oldest first
+func (s *assetService) GetHistory(ctx context.Context, id string) (*StatusHistory, error) {
+	asset, err := s.GetAsset(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := s.assets.History(asset.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading asset store: %w", err)
+	}
+	return &StatusHistory{AssetID: asset.ID, Status: asset.Status, Changes: changes}, nil
+}
+
+// GetIcon returns the requested icon variant of an asset
+func (s *assetService) GetIcon(id, variant string) (*Icon, error) {
+	return s.icons.Get(id, variant)
+}
+
+// SymbolAvailability reports whether a new asset may use symbol
+func (s *assetService) SymbolAvailability(symbol string) (*SymbolAvailability, error) {
+	return s.symbols.Check(symbol)
+}
+
+// reconcile merges an exSat asset into its stored copy, if any, and saves
+// the result when it differs. The merge runs inside the store update, on
+// the current stored copy, so that it cannot undo a concurrent status
+// change, deployment or holder update.
+func reconcile(assets repository.AssetRepository, remote client.Asset) client.Asset {
+	// Once indexed, holder figures come from the chain rather than exSat
+	indexed, err := assets.IndexedBlock(remote.ID)
+	chainHolders := err == nil && indexed > 0
+
+	merged := remote
+	_, err = assets.Update(remote.ID, func(stored *client.Asset) (*repository.StatusChange, error) {
+		merged = mergeRemote(remote, stored, chainHolders)
+		current := *stored
+		formatSupplies(&current)
+		if reflect.DeepEqual(current, merged) {
+			return nil, errUnchanged
+		}
+		*stored = merged
+		return nil, nil
+	})
+	switch {
+	case err == nil, errors.Is(err, errUnchanged):
+	case errors.Is(err, repository.ErrNotFound):
+		merged = mergeRemote(remote, nil, false)
+		if err := assets.Save(merged); err != nil {
+			log.Printf("Warning: Failed to store asset %s: %v", remote.ID, err)
+		}
+	default:
+		log.Printf("Warning: Failed to store asset %s: %v", remote.ID, err)
+	}
+	return merged
+}
+
+// mergeRemote returns an exSat asset merged with its stored copy, if any,
+// keeping the fields only FansMint knows about
+func mergeRemote(remote client.Asset, stored *client.Asset, chainHolders bool) client.Asset {
+	if stored != nil {
+		if remote.IconUrl == "" {
+			remote.IconUrl = stored.IconUrl
+		}
+		if remote.CreatorAddress == "" {
+			remote.CreatorAddress = stored.CreatorAddress
+		}
+		if remote.CreatedAt == "" {
+			remote.CreatedAt = stored.CreatedAt
+		}
+		if remote.Deployment == nil {
+			remote.Deployment = stored.Deployment
+		}
+		if remote.ContractAddress == "" {
+			remote.ContractAddress = stored.ContractAddress
+		}
+		if remote.OwnerBitcoinAddress == "" {
+			remote.OwnerBitcoinAddress = stored.OwnerBitcoinAddress
+		}
+		remote.Whitepaper = stored.Whitepaper
+		// FansMint owns the lifecycle of the assets it has stored
+		if ValidStatus(stored.Status) {
+			remote.Status = stored.Status
+		}
+		// Without decimals the exSat supply cannot be read, keep ours
+		if remote.Decimals == 0 && stored.Decimals != 0 {
+			remote.Decimals = stored.Decimals
+			remote.TotalSupply = stored.TotalSupply
+		}
+		if chainHolders {
+			remote.Holders = stored.Holders
+			remote.CirculatingSupply = stored.CirculatingSupply
+		}
+	}
+
+	// Assets created elsewhere join the lifecycle as active
+	if !ValidStatus(remote.Status) {
+		remote.Status = StatusActive
+	}
+
+	formatSupplies(&remote)
+	return remote
 }
 
 // MockMode returns true if we're running in mock mode (without real API)
-func (s *AssetService) MockMode() bool {
-	return os.Getenv("EXSAT_API_KEY") == ""
+func (s *assetService) MockMode() bool {
+	return s.mockMode
+}
+
+// newMockAsset builds a locally created asset for mock mode
+func newMockAsset(req AssetCreationRequest, decimals uint8) *client.Asset {
+	return &client.Asset{
+		ID:                newAssetID(),
+		Name:              req.Name,
+		Symbol:            req.Symbol,
+		TotalSupply:       req.TotalSupply,
+		CirculatingSupply: "0",
+		Decimals:          decimals,
+		Description:       req.Description,
+		CreatorAddress:    req.OwnerAddress,
+		CreatedAt:         time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// newAssetID generates a random asset ID of the form ast_<hex>
+func newAssetID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("ast_%d", time.Now().UnixNano())
+	}
+	return "ast_" + hex.EncodeToString(b)
+}
+
+// seedMockAssets stores the example assets into an empty store so the
+// Dashboard has something to show in mock mode
+func (s *assetService) seedMockAssets() {
+	existing, err := s.assets.List()
+	if err != nil || len(existing) > 0 {
+		return
+	}
+
+	for _, asset := range getMockAssets() {
+		formatSupplies(&asset)
+		if err := s.assets.Save(asset); err != nil {
+			log.Printf("Warning: Failed to seed mock asset %s: %v", asset.ID, err)
+			continue
+		}
+		created, _ := time.Parse(time.RFC3339, asset.CreatedAt)
+		if err := s.assets.RecordStatus(asset.ID, repository.StatusChange{To: asset.Status, Actor: ActorSystem, At: created}); err != nil {
+			log.Printf("Warning: Failed to record status of mock asset %s: %v", asset.ID, err)
+		}
+	}
+}
+
+// getMockAssets returns mock assets for development purposes
+func getMockAssets() []client.Asset {
+	return []client.Asset{
+		{
+			ID:                "1",
+			Name:              "ExampleToken",
+			Symbol:            "EXT",
+			TotalSupply:       "1000000000000000000000000",
+			CirculatingSupply: "750000000000000000000000",
+			Decimals:          18,
+			Description:       "Example token description",
+			ContractAddress:   "0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
+			CreatedAt:         "2023-05-19T10:00:00Z",
+			Status:            StatusActive,
+		},
+		{
+			ID:                "2",
+			Name:              "BitcoinUtility",
+			Symbol:            "BTU",
+			TotalSupply:       "2100000",
+			CirculatingSupply: "1050000",
+			ContractAddress:   "0x5aeda56215b167893e80b4fe645ba6d5bab767de",
+			CreatedAt:         "2023-05-15T14:30:00Z",
+			Status:            StatusActive,
+		},
+	}
 }
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yourusername/bitcoin-ai-platform/internal/repository"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
+)
+
+// maxSyncPages bounds the exSat listing pages read in one pass, so a
+// misbehaving cursor cannot keep a pass running forever
+const maxSyncPages = 1000
+
+// AssetSync copies the exSat asset listing into the asset store, so that
+// asset listings are served from the store without calling exSat.
+//
+// Each page is merged as it is read and only assets that changed are
+// written back.
+type AssetSync struct {
+	exSat  client.ExSatAPI
+	assets repository.AssetRepository
+}
+
+// NewAssetSync creates an AssetSync
+func NewAssetSync(exSat client.ExSatAPI, assets repository.AssetRepository) *AssetSync {
+	return &AssetSync{exSat: exSat, assets: assets}
+}
+
+// Run syncs every interval until ctx is done
+func (s *AssetSync) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.SyncOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Warning: exSat asset sync failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SyncOnce merges every page of the exSat listing into the store
+func (s *AssetSync) SyncOnce(ctx context.Context) error {
+	seen := make(map[string]bool)
+
+	params := client.ListAssetsParams{Limit: client.DefaultPageSize}
+	for range maxSyncPages {
+		page, err := s.exSat.ListAssets(ctx, params)
+		if err != nil {
+			return err
+		}
+		for _, asset := range page.Assets {
+			reconcile(s.assets, asset)
+		}
+
+		if page.NextCursor == "" {
+			return nil
+		}
+		if seen[page.NextCursor] {
+			return fmt.Errorf("exSat listing cursor %q repeated", page.NextCursor)
+		}
+		seen[page.NextCursor] = true
+		params.Cursor = page.NextCursor
+	}
+	return fmt.Errorf("exSat listing did not end after %d pages", maxSyncPages)
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/bitcoin-ai-platform/pkg/evm"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/siwe"
+)
+
+// Default lifetimes of sign-in nonces and sessions
+const (
+	DefaultNonceTTL   = 10 * time.Minute
+	DefaultSessionTTL = 24 * time.Hour
+)
+
+// A nonce is its random part, its expiry in Unix seconds and the MAC of
+// both, hex encoded, so issuing one stores nothing
+const (
+	nonceRandom = 8
+	nonceBody   = nonceRandom + 8
+	nonceLength = nonceBody + 16
+)
+
+// nonceContext separates nonce MACs from session token signatures
+const nonceContext = "fansmint-siwe-nonce:"
+
+// ErrUnauthorized is returned when a request lacks a valid session
+var ErrUnauthorized = errors.New("authentication required")
+
+// ErrSignInFailed is returned when a signed sign-in message is rejected
+var ErrSignInFailed = errors.New("sign-in failed")
+
+// ErrNotAssetOwner is returned when the signed-in wallet does not own an asset
+var ErrNotAssetOwner = errors.New("the signed-in wallet does not own this asset")
+
+// jwtHeader is the encoded header of every session token
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// AuthService signs wallets in with Sign-In with Ethereum (EIP-4361) and
+// issues HS256 JWT session tokens bound to the wallet address
+type AuthService struct {
+	domain  string
+	chainID int64
+	secret  []byte
+
+	// NonceTTL is how long a nonce may wait for its signed message
+	NonceTTL time.Duration
+	// SessionTTL is the lifetime of a session token
+	SessionTTL time.Duration
+
+	// spent holds the nonces used until they expire, so only sign-in
+	// attempts with a genuine nonce take memory
+	mu        sync.Mutex
+	spent     map[string]time.Time // nonce -> expiry
+	nextPrune time.Time
+}
+
+// Challenge is a nonce to embed in a sign-in message
+type Challenge struct {
+	Nonce     string    `json:"nonce"`
+	Domain    string    `json:"domain"`
+	ChainID   int64     `json:"chainId"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Session is a signed-in wallet
+type Session struct {
+	Token     string    `json:"token,omitempty"`
+	Address   string    `json:"address"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// sessionClaims are the JWT claims of a session token
+type sessionClaims struct {
+	Subject   string `json:"sub"` // checksummed wallet address
+	Issuer    string `json:"iss"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// NewAuthService creates an AuthService accepting messages for domain on
+// chain chainID, signing sessions with secret
+func NewAuthService(domain string, chainID int64, secret []byte) *AuthService {
+	return &AuthService{
+		domain:     domain,
+		chainID:    chainID,
+		secret:     secret,
+		NonceTTL:   DefaultNonceTTL,
+		SessionTTL: DefaultSessionTTL,
+		spent:      make(map[string]time.Time),
+	}
+}
+
+// Nonce issues a single-use nonce for a sign-in message. Nonces are signed
+// rather than stored, so anonymous callers cannot exhaust memory with them.
+func (s *AuthService) Nonce() (*Challenge, error) {
+	body := make([]byte, nonceBody)
+	if _, err := rand.Read(body[:nonceRandom]); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %w", err)
+	}
+	expires := time.Now().Add(s.NonceTTL).Unix()
+	binary.BigEndian.PutUint64(body[nonceRandom:], uint64(expires))
+
+	nonce := hex.EncodeToString(append(body, s.nonceMAC(body)...))
+	return &Challenge{Nonce: nonce, Domain: s.domain, ChainID: s.chainID, ExpiresAt: time.Unix(expires, 0).UTC()}, nil
+}
+
+// SignIn verifies a signed EIP-4361 message and opens a session for its
+// address. The nonce of the message is consumed whatever the outcome.
+func (s *AuthService) SignIn(message, signature string) (*Session, error) {
+	var invalid ValidationError
+	if message == "" {
+		invalid.Add("message", "is required")
+	}
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil || len(sig) != evm.SignatureLength {
+		invalid.Add("signature", fmt.Sprintf("must be a 0x-prefixed %d byte hex signature", evm.SignatureLength))
+	}
+	if err := invalid.Err(); err != nil {
+		return nil, err
+	}
+
+	msg, err := siwe.Parse(message)
+	if err != nil {
+		return nil, &ValidationError{Fields: []FieldError{{Field: "message", Message: err.Error()}}}
+	}
+
+	// Spend the nonce first so a message can never be replayed
+	if !s.consumeNonce(msg.Nonce) {
+		return nil, fmt.Errorf("%w: unknown or expired nonce", ErrSignInFailed)
+	}
+
+	now := time.Now()
+	switch {
+	case msg.Domain != s.domain:
+		return nil, fmt.Errorf("%w: message is for domain %s", ErrSignInFailed, msg.Domain)
+	case msg.ChainID != s.chainID:
+		return nil, fmt.Errorf("%w: message is for chain %d", ErrSignInFailed, msg.ChainID)
+	case msg.Version != siwe.Version:
+		return nil, fmt.Errorf("%w: unsupported message version %s", ErrSignInFailed, msg.Version)
+	}
+	if err := msg.Valid(now); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSignInFailed, err)
+	}
+	if _, err := siwe.Verify(message, sig); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSignInFailed, err)
+	}
+
+	expires := now.Add(s.SessionTTL)
+	if msg.ExpirationTime != nil && msg.ExpirationTime.Before(expires) {
+		expires = *msg.ExpirationTime
+	}
+	token, err := s.issue(sessionClaims{
+		Subject:   msg.Address.Hex(),
+		Issuer:    s.domain,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expires.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Session{Token: token, Address: msg.Address.Hex(), ExpiresAt: time.Unix(expires.Unix(), 0).UTC()}, nil
+}
+
+// Authenticate returns the session of a token issued by SignIn
+func (s *AuthService) Authenticate(token string) (*Session, error) {
+	header, rest, ok := strings.Cut(token, ".")
+	payload, signature, ok2 := strings.Cut(rest, ".")
+	if !ok || !ok2 || header != jwtHeader {
+		return nil, fmt.Errorf("%w: malformed session token", ErrUnauthorized)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, s.sign(header+"."+payload)) {
+		return nil, fmt.Errorf("%w: invalid session token signature", ErrUnauthorized)
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed session token", ErrUnauthorized)
+	}
+	var claims sessionClaims
+	if err := json.Unmarshal(data, &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed session token", ErrUnauthorized)
+	}
+	if claims.Issuer != s.domain {
+		return nil, fmt.Errorf("%w: session token issued for another domain", ErrUnauthorized)
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, fmt.Errorf("%w: session expired", ErrUnauthorized)
+	}
+	address, err := evm.ParseAddress(claims.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed session token", ErrUnauthorized)
+	}
+	return &Session{Address: address.Hex(), ExpiresAt: time.Unix(claims.ExpiresAt, 0).UTC()}, nil
+}
+
+// issue encodes and signs a session token
+func (s *AuthService) issue(claims sessionClaims) (string, error) {
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(data)
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(s.sign(unsigned)), nil
+}
+
+// sign returns the HS256 signature of a token's header and payload
+func (s *AuthService) sign(unsigned string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(unsigned))
+	return mac.Sum(nil)
+}
+
+// nonceMAC returns the truncated MAC of the body of a nonce
+func (s *AuthService) nonceMAC(body []byte) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(nonceContext))
+	mac.Write(body)
+	return mac.Sum(nil)[:nonceLength-nonceBody]
+}
+
+// consumeNonce spends nonce, reporting whether it was issued by Nonce, has
+// not expired and was not spent before
+func (s *AuthService) consumeNonce(nonce string) bool {
+	raw, err := hex.DecodeString(nonce)
+	if err != nil || len(raw) != nonceLength || !hmac.Equal(raw[nonceBody:], s.nonceMAC(raw[:nonceBody])) {
+		return false
+	}
+	now := time.Now()
+	expires := time.Unix(int64(binary.BigEndian.Uint64(raw[nonceRandom:nonceBody])), 0)
+	if !now.Before(expires) {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Sweeping once per NonceTTL keeps spent nonces at most two TTLs
+	if !now.Before(s.nextPrune) {
+		for spent, until := range s.spent {
+			if !now.Before(until) {
+				delete(s.spent, spent)
+			}
+		}
+		s.nextPrune = now.Add(s.NonceTTL)
+	}
+
+	key := strings.ToLower(nonce)
+	if _, ok := s.spent[key]; ok {
+		return false
+	}
+	s.spent[key] = expires
+	return true
+}
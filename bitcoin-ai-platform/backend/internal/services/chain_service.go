@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/yourusername/bitcoin-ai-platform/internal/repository"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/evm"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
+)
+
+// ChainService reads ERC-20 state from exSat EVM. Reads are pinned to the
+// latest block and cached until the chain moves on.
+type ChainService struct {
+	chain  *evm.Client
+	assets repository.AssetRepository
+	tokens []evm.Address
+	cache  blockCache
+}
+
+// TokenView is an ERC-20 token as read from the chain
+type TokenView struct {
+	Address              string `json:"address"`
+	Name                 string `json:"name"`
+	Symbol               string `json:"symbol"`
+	Decimals             uint8  `json:"decimals"`
+	TotalSupply          string `json:"totalSupply"` // base units
+	TotalSupplyFormatted string `json:"totalSupplyFormatted"`
+	BlockNumber          uint64 `json:"blockNumber"`
+	AssetID              string `json:"assetId,omitempty"` // set for tokens issued through FansMint
+}
+
+// TokenBalance is the balance of one token held by a wallet
+type TokenBalance struct {
+	Token            string `json:"token"`
+	Name             string `json:"name"`
+	Symbol           string `json:"symbol"`
+	Decimals         uint8  `json:"decimals"`
+	Balance          string `json:"balance"` // base units
+	BalanceFormatted string `json:"balanceFormatted"`
+	AssetID          string `json:"assetId,omitempty"`
+	// IndexedBlock is the block an indexed balance is as of, 0 for one
+	// read from the chain at the block of the response
+	IndexedBlock uint64 `json:"indexedBlock,omitempty"`
+}
+
+// WalletBalances lists the token balances of a wallet at a block
+type WalletBalances struct {
+	Wallet      string         `json:"wallet"`
+	BlockNumber uint64         `json:"blockNumber"`
+	Balances    []TokenBalance `json:"balances"`
+}
+
+// NewChainService creates a ChainService. tokens are the ERC-20 contracts
+// always reported in wallet balances; FansMint tokens are added when their
+// indexed balance is positive.
+func NewChainService(chain *evm.Client, assets repository.AssetRepository, tokens []string) *ChainService {
+	s := &ChainService{chain: chain, assets: assets}
+	for _, token := range tokens {
+		address, err := evm.ParseAddress(token)
+		if err != nil {
+			log.Printf("Warning: Ignoring configured token %q: %v", token, err)
+			continue
+		}
+		s.tokens = append(s.tokens, address)
+	}
+	return s
+}
+
+// GetToken reads the ERC-20 metadata and supply of the token at address
+func (s *ChainService) GetToken(ctx context.Context, address string) (*TokenView, error) {
+	token, err := parseAddressField("address", address)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := s.chain.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info, err := s.tokenInfo(ctx, token, block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenView{
+		Address:              info.Address.Hex(),
+		Name:                 info.Name,
+		Symbol:               info.Symbol,
+		Decimals:             info.Decimals,
+		TotalSupply:          info.TotalSupply.String(),
+		TotalSupplyFormatted: evm.FormatUnits(info.TotalSupply, info.Decimals),
+		BlockNumber:          block,
+		AssetID:              s.issuedAssets()[info.Address],
+	}, nil
+}
+
+// GetBalances reads the balances of wallet for the configured tokens, then
+// adds the FansMint tokens it holds from the indexed balances. Only the
+// configured tokens are read from the chain, however many assets were
+// issued. Tokens that cannot be read are skipped.
+func (s *ChainService) GetBalances(ctx context.Context, wallet string) (*WalletBalances, error) {
+	owner, err := parseAddressField("wallet", wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := s.chain.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	assets, err := s.assets.List()
+	if err != nil {
+		return nil, fmt.Errorf("error reading asset store: %w", err)
+	}
+	issued := make(map[evm.Address]client.Asset)
+	for _, asset := range assets {
+		if address, err := evm.ParseAddress(asset.ContractAddress); err == nil {
+			issued[address] = asset
+		}
+	}
+
+	result := &WalletBalances{Wallet: owner.Hex(), BlockNumber: block, Balances: []TokenBalance{}}
+	for _, token := range s.tokens {
+		balance, err := s.tokenBalance(ctx, token, owner, block)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			log.Printf("Warning: Failed to read balance of %s in token %s: %v", owner.Hex(), token.Hex(), err)
+			continue
+		}
+		balance.AssetID = issued[token].ID
+		result.Balances = append(result.Balances, *balance)
+	}
+
+	// FansMint tokens are only listed when held, to keep the list short
+	var held []TokenBalance
+	for token, asset := range issued {
+		if slices.Contains(s.tokens, token) {
+			continue
+		}
+		account, err := s.assets.Account(asset.ID, owner.Hex())
+		if err != nil {
+			return nil, fmt.Errorf("error reading asset store: %w", err)
+		}
+		if account.Balance.Sign() <= 0 {
+			continue
+		}
+		indexed, err := s.assets.IndexedBlock(asset.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error reading asset store: %w", err)
+		}
+		held = append(held, TokenBalance{
+			Token:            token.Hex(),
+			Name:             asset.Name,
+			Symbol:           asset.Symbol,
+			Decimals:         asset.Decimals,
+			Balance:          account.Balance.String(),
+			BalanceFormatted: evm.FormatUnits(account.Balance, asset.Decimals),
+			AssetID:          asset.ID,
+			IndexedBlock:     indexed,
+		})
+	}
+	slices.SortFunc(held, func(a, b TokenBalance) int {
+		return strings.Compare(strings.ToLower(a.Token), strings.ToLower(b.Token))
+	})
+	result.Balances = append(result.Balances, held...)
+	return result, nil
+}
+
+// tokenBalance reads the metadata of token and the balance of owner at block
+func (s *ChainService) tokenBalance(ctx context.Context, token, owner evm.Address, block uint64) (*TokenBalance, error) {
+	info, err := s.tokenInfo(ctx, token, block)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := s.balanceOf(ctx, token, owner, block)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenBalance{
+		Token:            token.Hex(),
+		Name:             info.Name,
+		Symbol:           info.Symbol,
+		Decimals:         info.Decimals,
+		Balance:          balance.String(),
+		BalanceFormatted: evm.FormatUnits(balance, info.Decimals),
+	}, nil
+}
+
+// tokenInfo reads token metadata at block through the cache
+func (s *ChainService) tokenInfo(ctx context.Context, token evm.Address, block uint64) (*evm.TokenInfo, error) {
+	key := "token:" + token.Hex()
+	if cached, ok := s.cache.get(block, key); ok {
+		if err, ok := cached.(error); ok {
+			return nil, err
+		}
+		return cached.(*evm.TokenInfo), nil
+	}
+	info, err := s.chain.TokenInfo(ctx, token, block)
+	if err != nil {
+		// Not being a token is as final as the state of the block itself
+		if errors.Is(err, evm.ErrNotContract) || errors.Is(err, evm.ErrABI) {
+			s.cache.put(block, key, err)
+		}
+		return nil, err
+	}
+	s.cache.put(block, key, info)
+	return info, nil
+}
+
+// balanceOf reads a token balance at block through the cache
+func (s *ChainService) balanceOf(ctx context.Context, token, owner evm.Address, block uint64) (*big.Int, error) {
+	key := "balance:" + token.Hex() + ":" + owner.Hex()
+	if cached, ok := s.cache.get(block, key); ok {
+		return cached.(*big.Int), nil
+	}
+	balance, err := s.chain.BalanceOf(ctx, token, owner, block)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.put(block, key, balance)
+	return balance, nil
+}
+
+// issuedAssets maps the contract of every deployed FansMint asset to its ID
+func (s *ChainService) issuedAssets() map[evm.Address]string {
+	issued := make(map[evm.Address]string)
+	assets, err := s.assets.List()
+	if err != nil {
+		log.Printf("Warning: Failed to list assets: %v", err)
+		return issued
+	}
+	for _, asset := range assets {
+		if address, err := evm.ParseAddress(asset.ContractAddress); err == nil {
+			issued[address] = asset.ID
+		}
+	}
+	return issued
+}
+
+// parseAddressField parses an EVM address, reporting failures against field
+func parseAddressField(field, value string) (evm.Address, error) {
+	address, err := evm.ParseAddress(strings.TrimSpace(value))
+	if err != nil {
+		return address, &ValidationError{Fields: []FieldError{{Field: field, Message: "must be a 0x-prefixed 40 digit hex address"}}}
+	}
+	return address, nil
+}
+
+// blockCache holds values read at a single block. Entries of older blocks
+// are dropped as soon as a newer block is seen.
+type blockCache struct {
+	mu      sync.Mutex
+	block   uint64
+	entries map[string]interface{}
+}
+
+// get returns the value cached for key at block
+func (c *blockCache) get(block uint64, key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if block != c.block {
+		return nil, false
+	}
+	value, ok := c.entries[key]
+	return value, ok
+}
+
+// put caches value for key at block, ignoring reads of an older block
+func (c *blockCache) put(block uint64, key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if block < c.block {
+		return
+	}
+	if block > c.block || c.entries == nil {
+		c.block = block
+		c.entries = make(map[string]interface{})
+	}
+	c.entries[key] = value
+}
@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/yourusername/bitcoin-ai-platform/internal/repository"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/ai"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/imaging"
+)
+
+// ErrDraftNotFound is returned when a draft does not exist or belongs to
+// another wallet
+var ErrDraftNotFound = errors.New("draft not found")
+
+// ErrDraftPublished is returned when changing a draft already published
+var ErrDraftPublished = errors.New("draft is already published")
+
+// ErrTooManyDrafts is returned when a wallet starts a draft while holding
+// MaxDraftsPerOwner unpublished ones
+var ErrTooManyDrafts = errors.New("too many drafts")
+
+// MaxDraftsPerOwner bounds the unpublished drafts of a wallet
+const MaxDraftsPerOwner = 20
+
+// maxDraftIconLength bounds the base64 icon of a draft, leaving room for a
+// data URI prefix. The icon itself is only checked when publishing.
+var maxDraftIconLength = base64.StdEncoding.EncodedLen(imaging.DefaultLimits.MaxBytes) + 64
+
+// DraftService keeps the asset creations in progress of each wallet and
+// publishes them as assets
+type DraftService struct {
+	drafts      repository.AssetRepository
+	assets      AssetService
+	whitepapers *WhitepaperService
+}
+
+// NewDraftService creates a new DraftService storing drafts in drafts and
+// publishing them through assets. Each whitepaper saved in a draft is kept
+// as a version in whitepapers.
+func NewDraftService(drafts repository.AssetRepository, assets AssetService, whitepapers *WhitepaperService) *DraftService {
+	return &DraftService{
+		drafts:      drafts,
+		assets:      assets,
+		whitepapers: whitepapers,
+	}
+}
+
+// DraftUpdate sets the fields of a draft it gives, leaving the others as
+// they are. Fields follow AssetCreationRequest, plus what the creation
+// wizard collects along the way.
+type DraftUpdate struct {
+	Step         *int                `json:"step"`
+	UseCase      *string             `json:"useCase"`
+	Suggestion   *ai.TokenSuggestion `json:"suggestion"`
+	Name         *string             `json:"name"`
+	Symbol       *string             `json:"symbol"`
+	TotalSupply  *string             `json:"totalSupply"`
+	Decimals     *int                `json:"decimals"`
+	Description  *string             `json:"description"`
+	OwnerAddress *string             `json:"ownerAddress"`
+	IconData     *string             `json:"iconData"`
+	Whitepaper   *string             `json:"whitepaper"`
+}
+
+// validate checks the bounds of the fields given. Whether they make a
+// valid asset is only checked when publishing.
+func (u DraftUpdate) validate() error {
+	var invalid ValidationError
+	if u.Step != nil && *u.Step < 0 {
+		invalid.Add("step", "must not be negative")
+	}
+	if u.IconData != nil && len(*u.IconData) > maxDraftIconLength {
+		invalid.Add("iconData", fmt.Sprintf("must not exceed %d bytes once decoded", imaging.DefaultLimits.MaxBytes))
+	}
+	if u.Whitepaper != nil && len(*u.Whitepaper) > MaxWhitepaperLength {
+		invalid.Add("whitepaper", fmt.Sprintf("must not exceed %d bytes", MaxWhitepaperLength))
+	}
+	return invalid.Err()
+}
+
+// apply copies the fields given onto draft
+func (u DraftUpdate) apply(draft *repository.Draft) {
+	set := func(field *string, value *string) {
+		if value != nil {
+			*field = *value
+		}
+	}
+	if u.Step != nil {
+		draft.Step = *u.Step
+	}
+	if u.Suggestion != nil {
+		draft.Suggestion = u.Suggestion
+	}
+	if u.Decimals != nil {
+		draft.Decimals = u.Decimals
+	}
+	set(&draft.UseCase, u.UseCase)
+	set(&draft.Name, u.Name)
+	set(&draft.Symbol, u.Symbol)
+	set(&draft.TotalSupply, u.TotalSupply)
+	set(&draft.Description, u.Description)
+	set(&draft.OwnerAddress, u.OwnerAddress)
+	set(&draft.IconData, u.IconData)
+	set(&draft.Whitepaper, u.Whitepaper)
+}
+
+// CreateDraft starts a draft owned by the signed-in wallet owner
+func (s *DraftService) CreateDraft(ctx context.Context, owner string, update DraftUpdate) (*repository.Draft, error) {
+	if err := update.validate(); err != nil {
+		return nil, err
+	}
+
+	drafts, err := s.drafts.ListDrafts(owner)
+	if err != nil {
+		return nil, fmt.Errorf("error reading draft store: %w", err)
+	}
+	unpublished := 0
+	for _, d := range drafts {
+		if d.AssetID == "" {
+			unpublished++
+		}
+	}
+	if unpublished >= MaxDraftsPerOwner {
+		return nil, fmt.Errorf("%w: publish or delete one of your %d drafts first", ErrTooManyDrafts, unpublished)
+	}
+
+	now := time.Now().UTC()
+	draft := repository.Draft{ID: newDraftID(), Owner: owner, CreatedAt: now, UpdatedAt: now}
+	update.apply(&draft)
+	if err := s.drafts.SaveDraft(draft); err != nil {
+		return nil, fmt.Errorf("error writing draft store: %w", err)
+	}
+	s.recordWhitepaper(&draft)
+	return &draft, nil
+}
+
+// UpdateDraft changes the fields given of a draft of owner
+func (s *DraftService) UpdateDraft(ctx context.Context, id, owner string, update DraftUpdate) (*repository.Draft, error) {
+	if err := update.validate(); err != nil {
+		return nil, err
+	}
+
+	draft, err := s.drafts.UpdateDraft(id, func(draft *repository.Draft) error {
+		if draft.Owner != owner {
+			return ErrDraftNotFound
+		}
+		if draft.AssetID != "" {
+			return fmt.Errorf("%w as asset %s", ErrDraftPublished, draft.AssetID)
+		}
+		update.apply(draft)
+		draft.UpdatedAt = time.Now().UTC()
+		return nil
+	})
+	if err != nil {
+		return nil, draftStoreError(err)
+	}
+	if update.Whitepaper != nil {
+		s.recordWhitepaper(draft)
+	}
+	return draft, nil
+}
+
+// recordWhitepaper keeps the whitepaper of a draft as a version unless it
+// is empty or unchanged. The draft itself holds the text, so a failure is
+// only logged.
+func (s *DraftService) recordWhitepaper(draft *repository.Draft) {
+	if strings.TrimSpace(draft.Whitepaper) == "" {
+		return
+	}
+	if _, err := s.whitepapers.record(draftDocument(draft.ID), draft.Owner, draft.Whitepaper); err != nil {
+		log.Printf("Warning: Failed to record whitepaper of draft %s: %v", draft.ID, err)
+	}
+}
+
+// GetWhitepaper retrieves a version of the whitepaper of a draft of owner,
+// the latest for 0
+func (s *DraftService) GetWhitepaper(ctx context.Context, id, owner string, version int) (*Whitepaper, error) {
+	if _, err := s.GetDraft(ctx, id, owner); err != nil {
+		return nil, err
+	}
+	return s.whitepapers.get(draftDocument(id), version, nil)
+}
+
+// DiffWhitepaper compares two versions of the whitepaper of a draft of
+// owner, like WhitepaperService.DiffAssetWhitepaper
+func (s *DraftService) DiffWhitepaper(ctx context.Context, id, owner string, from, to int) (*WhitepaperDiff, error) {
+	if _, err := s.GetDraft(ctx, id, owner); err != nil {
+		return nil, err
+	}
+	return s.whitepapers.diff(draftDocument(id), from, to)
+}
+
+// GetDraft retrieves a draft of owner
+func (s *DraftService) GetDraft(ctx context.Context, id, owner string) (*repository.Draft, error) {
+	draft, err := s.drafts.GetDraft(id)
+	if err != nil {
+		return nil, draftStoreError(err)
+	}
+	if draft.Owner != owner {
+		return nil, ErrDraftNotFound
+	}
+	return draft, nil
+}
+
+// ListDrafts retrieves the drafts of owner, most recently updated first
+func (s *DraftService) ListDrafts(ctx context.Context, owner string) ([]repository.Draft, error) {
+	drafts, err := s.drafts.ListDrafts(owner)
+	if err != nil {
+		return nil, fmt.Errorf("error reading draft store: %w", err)
+	}
+	return drafts, nil
+}
+
+// DeleteDraft discards a draft of owner
+func (s *DraftService) DeleteDraft(ctx context.Context, id, owner string) error {
+	if _, err := s.GetDraft(ctx, id, owner); err != nil {
+		return err
+	}
+	if err := s.drafts.DeleteDraft(id); err != nil {
+		return draftStoreError(err)
+	}
+	if err := s.drafts.DeleteWhitepapers(draftDocument(id)); err != nil {
+		log.Printf("Warning: Failed to delete whitepaper of draft %s: %v", id, err)
+	}
+	return nil
+}
+
+// PublishDraft creates the asset described by a draft of owner, validated
+// like any new asset, publishes the whitepaper of the draft for it and
+// marks the draft as published. A retried publication reuses
+// idempotencyKey, or one derived from the draft.
+func (s *DraftService) PublishDraft(ctx context.Context, id, owner, idempotencyKey string) (*client.Asset, error) {
+	draft, err := s.GetDraft(ctx, id, owner)
+	if err != nil {
+		return nil, err
+	}
+	if draft.AssetID != "" {
+		return nil, fmt.Errorf("%w as asset %s", ErrDraftPublished, draft.AssetID)
+	}
+	if idempotencyKey == "" {
+		idempotencyKey = "draft-" + draft.ID
+	}
+
+	asset, err := s.assets.CreateAsset(ctx, AssetCreationRequest{
+		Name:           draft.Name,
+		Symbol:         draft.Symbol,
+		TotalSupply:    draft.TotalSupply,
+		Decimals:       draft.Decimals,
+		Description:    draft.Description,
+		OwnerAddress:   draft.OwnerAddress,
+		IconData:       draft.IconData,
+		Wallet:         owner,
+		IdempotencyKey: idempotencyKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The asset exists at this point, so store failures must not be
+	// reported as a failed publication
+	if strings.TrimSpace(draft.Whitepaper) != "" {
+		published, err := s.whitepapers.PublishAssetWhitepaper(ctx, asset.ID, owner, draft.Whitepaper)
+		if err != nil {
+			log.Printf("Warning: Failed to publish whitepaper of asset %s: %v", asset.ID, err)
+		} else {
+			asset = published
+		}
+	}
+	_, err = s.drafts.UpdateDraft(id, func(draft *repository.Draft) error {
+		draft.AssetID = asset.ID
+		draft.UpdatedAt = time.Now().UTC()
+		return nil
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to mark draft %s as published: %v", id, err)
+	}
+	return asset, nil
+}
+
+// draftStoreError maps a missing draft to ErrDraftNotFound and wraps other
+// store failures
+func draftStoreError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, repository.ErrNotFound):
+		return ErrDraftNotFound
+	case errors.Is(err, ErrDraftNotFound), errors.Is(err, ErrDraftPublished):
+		return err
+	}
+	return fmt.Errorf("error writing draft store: %w", err)
+}
+
+// newDraftID generates a random draft ID of the form dft_<hex>
+func newDraftID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("dft_%d", time.Now().UnixNano())
+	}
+	return "dft_" + hex.EncodeToString(b)
+}
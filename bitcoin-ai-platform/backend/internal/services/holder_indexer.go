@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/bitcoin-ai-platform/internal/repository"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/evm"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
+)
+
+// Defaults of the HolderIndexer settings
+const (
+	DefaultConfirmations = 3
+	DefaultLogBlockRange = 5000
+)
+
+// maxRangesPerSync bounds the eth_getLogs calls made for one asset in a
+// single pass, so a new asset catching up cannot starve the others
+const maxRangesPerSync = 20
+
+// HolderIndexer follows the ERC-20 Transfer logs of every FansMint asset
+// with a contract and keeps the transfers and holder balances in the asset
+// store, along with the holder count and circulating supply of each asset.
+//
+// Only blocks Confirmations deep are indexed, so reorgs above them are
+// never observed and applied transfers never need to be undone.
+type HolderIndexer struct {
+	chain  *evm.Client
+	assets repository.AssetRepository
+
+	// Confirmations is how far behind the chain head indexing stays
+	Confirmations uint64
+	// BlockRange is the widest block span requested in one eth_getLogs call
+	BlockRange uint64
+	// StartBlock is where indexing of a new asset starts
+	StartBlock uint64
+
+	mu     sync.Mutex
+	tokens map[evm.Address]*evm.TokenInfo
+}
+
+// NewHolderIndexer creates a HolderIndexer with the default settings
+func NewHolderIndexer(chain *evm.Client, assets repository.AssetRepository) *HolderIndexer {
+	return &HolderIndexer{
+		chain:         chain,
+		assets:        assets,
+		Confirmations: DefaultConfirmations,
+		BlockRange:    DefaultLogBlockRange,
+		tokens:        make(map[evm.Address]*evm.TokenInfo),
+	}
+}
+
+// Run syncs every interval until ctx is done
+func (x *HolderIndexer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := x.SyncOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Warning: Holder indexing failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SyncOnce indexes the confirmed blocks not yet applied for every asset.
+// A failing asset does not hold back the others; every failure is reported.
+func (x *HolderIndexer) SyncOnce(ctx context.Context) error {
+	head, err := x.chain.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if head < x.Confirmations {
+		return nil
+	}
+	safe := head - x.Confirmations
+
+	assets, err := x.assets.List()
+	if err != nil {
+		return fmt.Errorf("error listing assets: %w", err)
+	}
+
+	var errs []error
+	for _, asset := range assets {
+		token, err := evm.ParseAddress(asset.ContractAddress)
+		if err != nil {
+			continue // not deployed yet
+		}
+		if err := x.syncAsset(ctx, asset, token, safe); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			errs = append(errs, fmt.Errorf("asset %s: %w", asset.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// syncAsset applies the Transfer logs of token up to block safe
+func (x *HolderIndexer) syncAsset(ctx context.Context, asset client.Asset, token evm.Address, safe uint64) error {
+	assetID := asset.ID
+	info, err := x.tokenInfo(ctx, token, safe)
+	if errors.Is(err, evm.ErrNotContract) || errors.Is(err, evm.ErrABI) {
+		// Recorded but not (yet) deployed there; check again next pass
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	indexed, err := x.assets.IndexedBlock(assetID)
+	if err != nil {
+		return err
+	}
+	// Tokens deployed by FansMint have no logs before their deployment
+	from := x.StartBlock
+	if asset.Deployment != nil && asset.Deployment.BlockNumber > from {
+		from = asset.Deployment.BlockNumber
+	}
+	if indexed > 0 {
+		from = max(indexed+1, from)
+	}
+	applied := indexed > 0
+	supplyChanged := false
+
+	rangeSize := max(x.BlockRange, 1)
+	for i := 0; i < maxRangesPerSync && from <= safe; i++ {
+		to := min(from+rangeSize-1, safe)
+		logs, err := x.chain.GetLogs(ctx, evm.FilterQuery{
+			FromBlock: from,
+			ToBlock:   to,
+			Addresses: []evm.Address{token},
+			Topics:    [][]evm.Hash{{evm.TransferTopic}},
+		})
+		if err != nil {
+			return err
+		}
+
+		transfers := make([]repository.Transfer, 0, len(logs))
+		times := make(map[uint64]time.Time)
+		for _, entry := range logs {
+			if entry.Removed {
+				continue
+			}
+			t, err := evm.DecodeTransfer(entry)
+			if err != nil {
+				log.Printf("Warning: Skipping undecodable log %s:%d of %s: %v", entry.TxHash.Hex(), entry.LogIndex, token.Hex(), err)
+				continue
+			}
+			if t.From.IsZero() || t.To.IsZero() {
+				supplyChanged = true
+			}
+			timestamp, ok := times[t.BlockNumber]
+			if !ok {
+				if timestamp, err = x.chain.BlockTime(ctx, t.BlockNumber); err != nil {
+					return err
+				}
+				times[t.BlockNumber] = timestamp
+			}
+			transfers = append(transfers, repository.Transfer{
+				From:      t.From.Hex(),
+				To:        t.To.Hex(),
+				Value:     t.Value,
+				Block:     t.BlockNumber,
+				Timestamp: timestamp,
+				TxHash:    t.TxHash.Hex(),
+				LogIndex:  t.LogIndex,
+			})
+		}
+
+		if err := x.assets.ApplyTransfers(assetID, transfers, to); err != nil {
+			return fmt.Errorf("error storing transfers: %w", err)
+		}
+		applied = true
+		from = to + 1
+	}
+
+	// Until a first range is indexed the exSat figures are all there is
+	if !applied {
+		return nil
+	}
+	if supplyChanged {
+		x.mu.Lock()
+		delete(x.tokens, token)
+		x.mu.Unlock()
+		if info, err = x.tokenInfo(ctx, token, from-1); err != nil {
+			return err
+		}
+	}
+	return x.updateStats(assetID, info)
+}
+
+// errUnchanged aborts an asset update that would change nothing
+var errUnchanged = errors.New("unchanged")
+
+// updateStats recomputes the holder count and circulating supply of an
+// asset from its indexed balances and takes its total supply and decimals
+// from the deployed token. Tokens still held by the creator are not
+// circulating.
+func (x *HolderIndexer) updateStats(assetID string, info *evm.TokenInfo) error {
+	holders, err := x.assets.Holders(assetID)
+	if err != nil {
+		return err
+	}
+
+	_, err = x.assets.Update(assetID, func(asset *client.Asset) (*repository.StatusChange, error) {
+		circulating := new(big.Int)
+		for _, h := range holders {
+			if !strings.EqualFold(h.Address, asset.CreatorAddress) {
+				circulating.Add(circulating, h.Balance)
+			}
+		}
+
+		holderCount := int64(len(holders))
+		circulatingSupply := circulating.String()
+		totalSupply := info.TotalSupply.String()
+		if asset.Holders == holderCount && asset.CirculatingSupply == circulatingSupply &&
+			asset.TotalSupply == totalSupply && asset.Decimals == info.Decimals {
+			return nil, errUnchanged
+		}
+		asset.Holders = holderCount
+		asset.CirculatingSupply = circulatingSupply
+		asset.TotalSupply = totalSupply
+		asset.Decimals = info.Decimals
+		formatSupplies(asset)
+		return nil, nil
+	})
+	if errors.Is(err, errUnchanged) {
+		return nil
+	}
+	return err
+}
+
+// tokenInfo reads the decimals and total supply of token once. Decimals
+// never change and the supply only with a mint or burn, after which
+// syncAsset reads it again.
+func (x *HolderIndexer) tokenInfo(ctx context.Context, token evm.Address, block uint64) (*evm.TokenInfo, error) {
+	x.mu.Lock()
+	info, ok := x.tokens[token]
+	x.mu.Unlock()
+	if ok {
+		return info, nil
+	}
+
+	info, err := x.chain.TokenInfo(ctx, token, block)
+	if err != nil {
+		return nil, err
+	}
+
+	x.mu.Lock()
+	x.tokens[token] = info
+	x.mu.Unlock()
+	return info, nil
+}
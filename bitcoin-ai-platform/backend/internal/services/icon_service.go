@@ -0,0 +1,126 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/bitcoin-ai-platform/internal/storage"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/imaging"
+)
+
+// Icon variants served by the icon route
+const (
+	IconOriginal  = "original"
+	IconThumbnail = "thumb"
+)
+
+// iconContentTypes lists the formats an icon may be stored in
+var iconContentTypes = []string{
+	imaging.ContentTypePNG,
+	imaging.ContentTypeJPEG,
+	imaging.ContentTypeWebP,
+	imaging.ContentTypeSVG,
+}
+
+// ErrIconNotFound is returned when an asset has no stored icon
+var ErrIconNotFound = errors.New("icon not found")
+
+// IconService validates, stores and serves asset icons
+type IconService struct {
+	store  storage.BlobStore
+	limits imaging.Limits
+}
+
+// Icon is a stored icon ready to be served
+type Icon struct {
+	Data        []byte
+	ContentType string
+	ETag        string
+}
+
+// ProcessedIcon is a validated icon upload with its thumbnail
+type ProcessedIcon struct {
+	Original  *imaging.Image
+	Thumbnail *imaging.Image
+}
+
+// NewIconService creates an IconService keeping icons in store
+func NewIconService(store storage.BlobStore) *IconService {
+	return &IconService{
+		store:  store,
+		limits: imaging.DefaultLimits,
+	}
+}
+
+// Process decodes and validates base64 icon data and renders its thumbnail.
+// Errors wrap imaging.ErrInvalidImage.
+func (s *IconService) Process(iconData string) (*ProcessedIcon, error) {
+	data, err := imaging.DecodeBase64(iconData, s.limits)
+	if err != nil {
+		return nil, err
+	}
+
+	original, thumbnail, err := imaging.Process(data, s.limits)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProcessedIcon{Original: original, Thumbnail: thumbnail}, nil
+}
+
+// Save stores both variants of a processed icon for an asset
+func (s *IconService) Save(assetID string, icon *ProcessedIcon) error {
+	variants := map[string]*imaging.Image{
+		IconOriginal:  icon.Original,
+		IconThumbnail: icon.Thumbnail,
+	}
+	for variant, img := range variants {
+		// Remove a previous icon stored with another format
+		for _, contentType := range iconContentTypes {
+			if contentType != img.ContentType {
+				if err := s.store.Delete(iconKey(assetID, variant, contentType)); err != nil {
+					return fmt.Errorf("error replacing %s icon: %w", variant, err)
+				}
+			}
+		}
+
+		// The content type is encoded in the key so it can be recovered on read
+		if err := s.store.Put(iconKey(assetID, variant, img.ContentType), img.Data); err != nil {
+			return fmt.Errorf("error storing %s icon: %w", variant, err)
+		}
+	}
+	return nil
+}
+
+// Get returns the requested icon variant of an asset
+func (s *IconService) Get(assetID, variant string) (*Icon, error) {
+	if variant != IconOriginal && variant != IconThumbnail {
+		return nil, fmt.Errorf("unknown icon variant %q", variant)
+	}
+
+	for _, contentType := range iconContentTypes {
+		data, err := s.store.Get(iconKey(assetID, variant, contentType))
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading icon: %w", err)
+		}
+
+		sum := sha256.Sum256(data)
+		return &Icon{
+			Data:        data,
+			ContentType: contentType,
+			ETag:        `"` + hex.EncodeToString(sum[:16]) + `"`,
+		}, nil
+	}
+
+	return nil, ErrIconNotFound
+}
+
+// iconKey returns the blob key of an icon variant
+func iconKey(assetID, variant, contentType string) string {
+	return "icons/" + assetID + "/" + variant + imaging.Extension(contentType)
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/yourusername/bitcoin-ai-platform/internal/repository"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
+)
+
+// Lifecycle statuses of an asset. Drafts are kept apart until published,
+// so an asset starts out pending_review, deploying or active.
+const (
+	StatusPendingReview = "pending_review" // created, its token awaiting the owner's signature
+	StatusDeploying     = "deploying"      // token deployment submitted
+	StatusActive        = "active"
+	StatusPaused        = "paused"
+	StatusRetired       = "retired" // final
+	StatusFailed        = "failed"  // token deployment failed, may be retried
+)
+
+// ActorSystem is the actor of the status changes FansMint makes itself
+const ActorSystem = "system"
+
+// ErrInvalidTransition is returned for a status change the lifecycle of
+// an asset does not allow
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// transitions lists the statuses each status may move to
+var transitions = map[string][]string{
+	StatusPendingReview: {StatusDeploying, StatusActive, StatusFailed, StatusRetired},
+	StatusDeploying:     {StatusActive, StatusFailed},
+	StatusActive:        {StatusPaused, StatusRetired},
+	StatusPaused:        {StatusActive, StatusRetired},
+	StatusFailed:        {StatusDeploying, StatusRetired},
+	StatusRetired:       {},
+}
+
+// ValidStatus reports whether status is a lifecycle status
+func ValidStatus(status string) bool {
+	_, ok := transitions[status]
+	return ok
+}
+
+// canTransition reports whether an asset may move from one status to another
+func canTransition(from, to string) bool {
+	return slices.Contains(transitions[from], to)
+}
+
+// transition moves asset to status, returning the change to record
+func transition(asset *client.Asset, to, actor, reason string) (*repository.StatusChange, error) {
+	if !canTransition(asset.Status, to) {
+		return nil, fmt.Errorf("%w: asset is %s, cannot become %s", ErrInvalidTransition, asset.Status, to)
+	}
+	change := &repository.StatusChange{
+		From:   asset.Status,
+		To:     to,
+		Actor:  actor,
+		Reason: reason,
+		At:     time.Now().UTC(),
+	}
+	asset.Status = to
+	return change, nil
+}
+
+// deploymentStatus is the lifecycle status of an asset whose token is at
+// the given deployment stage, active when it is not deployed through
+// FansMint
+func deploymentStatus(deployment *client.Deployment) string {
+	if deployment == nil {
+		return StatusActive
+	}
+	switch deployment.Status {
+	case client.DeploymentAwaitingSignature:
+		return StatusPendingReview
+	case client.DeploymentPending:
+		return StatusDeploying
+	case client.DeploymentFailed:
+		return StatusFailed
+	}
+	return StatusActive
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/bitcoin-ai-platform/internal/repository"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/address"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/evm"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
+)
+
+// PortfolioService summarizes the FansMint tokens of a wallet from the
+// indexed chain data, without any RPC call
+type PortfolioService struct {
+	assets   repository.AssetRepository
+	network  address.Network
+	explorer string // block explorer base URL
+}
+
+// Portfolio is what a wallet holds, created and did across FansMint tokens
+type Portfolio struct {
+	Address string       `json:"address"`
+	Kind    address.Kind `json:"kind"`
+	// Holdings are the tokens with a positive indexed balance
+	Holdings []Holding `json:"holdings"`
+	// Created are the assets the address owns, as EVM creator or as named
+	// Bitcoin owner
+	Created       []client.Asset `json:"created"`
+	TransferCount int            `json:"transferCount"`
+	// FirstActivity and LastActivity are the oldest and newest transfers
+	// sending or receiving any FansMint token, nil without any
+	FirstActivity *Activity `json:"firstActivity"`
+	LastActivity  *Activity `json:"lastActivity"`
+}
+
+// Holding is the balance of one FansMint token held by a wallet
+type Holding struct {
+	AssetID          string `json:"assetId"`
+	Name             string `json:"name"`
+	Symbol           string `json:"symbol"`
+	Token            string `json:"token"`
+	Decimals         uint8  `json:"decimals"`
+	Balance          string `json:"balance"` // base units
+	BalanceFormatted string `json:"balanceFormatted"`
+	IconURL          string `json:"iconUrl,omitempty"`
+	IndexedBlock     uint64 `json:"indexedBlock"` // last block applied
+}
+
+// Activity is a transfer of the asset AssetID
+type Activity struct {
+	AssetID string `json:"assetId"`
+	TransferView
+}
+
+// NewPortfolioService creates a PortfolioService accepting Bitcoin
+// addresses of network and linking transfers on explorer
+func NewPortfolioService(assets repository.AssetRepository, network address.Network, explorer string) *PortfolioService {
+	return &PortfolioService{
+		assets:   assets,
+		network:  network,
+		explorer: strings.TrimRight(explorer, "/"),
+	}
+}
+
+// GetPortfolio summarizes the FansMint tokens of wallet, an EVM or Bitcoin
+// address. Tokens only live on exSat EVM, so a Bitcoin address just has
+// the assets it was named owner of.
+func (s *PortfolioService) GetPortfolio(ctx context.Context, wallet string) (*Portfolio, error) {
+	owner, err := address.Parse(wallet, s.network)
+	if err != nil {
+		return nil, &ValidationError{Fields: []FieldError{{Field: "address", Message: err.Error()}}}
+	}
+
+	assets, err := s.assets.List()
+	if err != nil {
+		return nil, fmt.Errorf("error reading asset store: %w", err)
+	}
+
+	portfolio := &Portfolio{
+		Address:  owner.Normalized,
+		Kind:     owner.Kind,
+		Holdings: []Holding{},
+		Created:  []client.Asset{},
+	}
+	for _, asset := range assets {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if owner.IsBitcoin() {
+			if asset.OwnerBitcoinAddress == owner.Normalized {
+				formatSupplies(&asset)
+				portfolio.Created = append(portfolio.Created, asset)
+			}
+			continue
+		}
+
+		if strings.EqualFold(asset.CreatorAddress, owner.Normalized) {
+			formatSupplies(&asset)
+			portfolio.Created = append(portfolio.Created, asset)
+		}
+		token, err := evm.ParseAddress(asset.ContractAddress)
+		if err != nil {
+			continue // not deployed yet
+		}
+		if err := s.addAccount(portfolio, asset, token, owner.Normalized); err != nil {
+			return nil, err
+		}
+	}
+	return portfolio, nil
+}
+
+// addAccount adds the indexed balance and activity of wallet in asset
+func (s *PortfolioService) addAccount(portfolio *Portfolio, asset client.Asset, token evm.Address, wallet string) error {
+	account, err := s.assets.Account(asset.ID, wallet)
+	if err != nil {
+		return fmt.Errorf("error reading asset store: %w", err)
+	}
+
+	if account.Balance.Sign() > 0 {
+		indexed, err := s.assets.IndexedBlock(asset.ID)
+		if err != nil {
+			return fmt.Errorf("error reading asset store: %w", err)
+		}
+		portfolio.Holdings = append(portfolio.Holdings, Holding{
+			AssetID:          asset.ID,
+			Name:             asset.Name,
+			Symbol:           asset.Symbol,
+			Token:            token.Hex(),
+			Decimals:         asset.Decimals,
+			Balance:          account.Balance.String(),
+			BalanceFormatted: evm.FormatUnits(account.Balance, asset.Decimals),
+			IconURL:          asset.IconUrl,
+			IndexedBlock:     indexed,
+		})
+	}
+
+	if account.Transfers == 0 {
+		return nil
+	}
+	portfolio.TransferCount += account.Transfers
+	first, last := account.First, account.Last
+	if a := portfolio.FirstActivity; a == nil || before(first.Block, first.LogIndex, a.BlockNumber, a.LogIndex) {
+		portfolio.FirstActivity = &Activity{AssetID: asset.ID, TransferView: newTransferView(*first, asset.Decimals, s.explorer)}
+	}
+	if a := portfolio.LastActivity; a == nil || before(a.BlockNumber, a.LogIndex, last.Block, last.LogIndex) {
+		portfolio.LastActivity = &Activity{AssetID: asset.ID, TransferView: newTransferView(*last, asset.Decimals, s.explorer)}
+	}
+	return nil
+}
+
+// before reports whether position a comes before b on chain. Log indexes
+// count across the whole block, so positions of different tokens compare.
+func before(aBlock, aIndex, bBlock, bIndex uint64) bool {
+	return aBlock < bBlock || (aBlock == bBlock && aIndex < bIndex)
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/bitcoin-ai-platform/internal/repository"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/ai"
+)
+
+// DefaultReservationTTL is how long a symbol stays reserved for a creation
+const DefaultReservationTTL = 10 * time.Minute
+
+// Symbol lengths, in letters or digits, shared with the AI suggestions
+const (
+	MinSymbolLength = ai.MinSymbolLength
+	MaxSymbolLength = ai.MaxSymbolLength
+)
+
+// Reasons a symbol is or is not available
+const (
+	SymbolAvailable = "available"
+	SymbolInvalid   = "invalid"
+	SymbolBlocked   = "blocked"
+	SymbolTaken     = "taken"
+	SymbolReserved  = "reserved"
+)
+
+// SymbolAvailability tells whether a new asset may use a symbol
+type SymbolAvailability struct {
+	Symbol    string `json:"symbol"`
+	Available bool   `json:"available"`
+	Reason    string `json:"reason"` // available, invalid, blocked, taken or reserved
+	Message   string `json:"message,omitempty"`
+}
+
+// SymbolRegistry keeps asset symbols and names unique, case-insensitively.
+// A creation reserves its symbol and name before anything is created
+// upstream, so that two concurrent creations cannot both claim them.
+type SymbolRegistry struct {
+	assets  repository.AssetRepository
+	blocked map[string]bool
+
+	// ReservationTTL is how long a reservation holds once made
+	ReservationTTL time.Duration
+
+	mu           sync.Mutex
+	reservations map[string]reservation // registryKey -> reservation
+}
+
+// reservation is a symbol or name claimed by a creation in progress
+type reservation struct {
+	holder  string // creator and idempotency key, empty when not retryable
+	expires time.Time
+}
+
+// NewSymbolRegistry creates a SymbolRegistry checking against the assets
+// in assets and rejecting the blocked symbols
+func NewSymbolRegistry(assets repository.AssetRepository, blocked []string) *SymbolRegistry {
+	r := &SymbolRegistry{
+		assets:         assets,
+		blocked:        make(map[string]bool, len(blocked)),
+		ReservationTTL: DefaultReservationTTL,
+		reservations:   make(map[string]reservation),
+	}
+	for _, symbol := range blocked {
+		r.blocked[repository.NormalizeSymbol(symbol)] = true
+	}
+	return r
+}
+
+// Check reports whether a new asset may use symbol
+func (r *SymbolRegistry) Check(symbol string) (*SymbolAvailability, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	conflicts, err := r.conflicts(symbol, "", "")
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		c := conflicts[0]
+		return &SymbolAvailability{Symbol: strings.TrimSpace(symbol), Reason: c.reason, Message: "symbol " + c.message}, nil
+	}
+	return &SymbolAvailability{Symbol: strings.TrimSpace(symbol), Available: true, Reason: SymbolAvailable}, nil
+}
+
+// Reserve claims symbol and name for a creation by holder, failing with a
+// ValidationError when either is unavailable. Retries by the same holder,
+// identified by its creator and idempotency key, share the reservation.
+// The returned release frees it for a creation that did not happen, unless
+// a retry has renewed it since; a successful one keeps it until it
+// expires, after which its stored asset holds the symbol.
+func (r *SymbolRegistry) Reserve(symbol, name, holder string) (release func(), err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	conflicts, err := r.conflicts(symbol, name, holder)
+	if err != nil {
+		return nil, err
+	}
+	var invalid ValidationError
+	for _, c := range conflicts {
+		invalid.Add(c.field, c.message)
+	}
+	if err := invalid.Err(); err != nil {
+		return nil, err
+	}
+
+	keys := []string{registryKey("symbol", repository.NormalizeSymbol(symbol)), registryKey("name", repository.NormalizeName(name))}
+	expires := time.Now().Add(r.ReservationTTL)
+	for _, key := range keys {
+		r.reservations[key] = reservation{holder: holder, expires: expires}
+	}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for _, key := range keys {
+			if res, ok := r.reservations[key]; ok && res.holder == holder && res.expires.Equal(expires) {
+				delete(r.reservations, key)
+			}
+		}
+	}, nil
+}
+
+// conflict is why a symbol or name cannot be used
+type conflict struct {
+	field   string // symbol or name
+	reason  string // invalid, blocked, taken or reserved
+	message string
+}
+
+// conflicts lists why symbol and, when given, name cannot be used by a
+// creation by holder. Called with r.mu held.
+func (r *SymbolRegistry) conflicts(symbol, name, holder string) ([]conflict, error) {
+	normalized := repository.NormalizeSymbol(symbol)
+	if !ai.ValidSymbol(normalized) {
+		return []conflict{{"symbol", SymbolInvalid, fmt.Sprintf("must be %d to %d letters or digits", MinSymbolLength, MaxSymbolLength)}}, nil
+	}
+	if r.blocked[normalized] {
+		return []conflict{{"symbol", SymbolBlocked, "is reserved and cannot be used"}}, nil
+	}
+
+	r.pruneReservations(time.Now())
+	var conflicts []conflict
+	checkSymbol, checkName := true, name != ""
+	if res, ok := r.reservations[registryKey("symbol", normalized)]; ok {
+		// A retry of a creation in progress may use what it claimed
+		if holder == "" || res.holder != holder {
+			conflicts = append(conflicts, conflict{"symbol", SymbolReserved, "is reserved by an asset being created"})
+		}
+		checkSymbol = false
+	}
+	if res, ok := r.reservations[registryKey("name", repository.NormalizeName(name))]; ok && checkName {
+		if holder == "" || res.holder != holder {
+			conflicts = append(conflicts, conflict{"name", SymbolReserved, "is reserved by an asset being created"})
+		}
+		checkName = false
+	}
+	if !checkSymbol && !checkName {
+		return conflicts, nil
+	}
+
+	// An asset made by an earlier attempt of this creation is its own
+	var created string
+	if holder != "" {
+		var err error
+		if created, err = r.assets.Creation(holder); err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("error reading asset store: %w", err)
+		}
+	}
+	if checkSymbol {
+		taken, err := r.taken(r.assets.AssetsWithSymbol, symbol, created)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			conflicts = append(conflicts, conflict{"symbol", SymbolTaken, "is already used by another asset"})
+		}
+	}
+	if checkName {
+		taken, err := r.taken(r.assets.AssetsWithName, name, created)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			conflicts = append(conflicts, conflict{"name", SymbolTaken, "is already used by another asset"})
+		}
+	}
+	return conflicts, nil
+}
+
+// taken reports whether lookup finds an asset using value other than
+// created, the one made by the creation asking
+func (r *SymbolRegistry) taken(lookup func(string) ([]string, error), value, created string) (bool, error) {
+	ids, err := lookup(value)
+	if err != nil {
+		return false, fmt.Errorf("error reading asset store: %w", err)
+	}
+	for _, id := range ids {
+		if id != created {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// pruneReservations drops expired reservations. Called with r.mu held.
+func (r *SymbolRegistry) pruneReservations(now time.Time) {
+	for key, res := range r.reservations {
+		if !now.Before(res.expires) {
+			delete(r.reservations, key)
+		}
+	}
+}
+
+// reservationHolder identifies the creation of req across retries, or
+// returns "" when it cannot be retried
+func reservationHolder(req AssetCreationRequest) string {
+	if req.IdempotencyKey == "" {
+		return ""
+	}
+	return strings.ToLower(req.OwnerAddress) + "/" + req.IdempotencyKey
+}
+
+func registryKey(kind, value string) string {
+	return kind + ":" + value
+}
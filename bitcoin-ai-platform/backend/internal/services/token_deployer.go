@@ -0,0 +1,472 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/bitcoin-ai-platform/internal/repository"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/evm"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
+)
+
+// DefaultTokenDecimals is the precision of assets created without decimals
+const DefaultTokenDecimals = 18
+
+// DefaultDropTimeout is how long a deployment may stay unmined before it is
+// checked for having been dropped
+const DefaultDropTimeout = 30 * time.Minute
+
+// gasMargin is the percentage added to gas estimates
+const gasMargin = 20
+
+// ErrDeploymentDisabled is returned when no factory is configured, or a
+// server-signed deployment is requested without a deployer key
+var ErrDeploymentDisabled = errors.New("token deployment is not enabled")
+
+// ErrDeploymentConflict is returned when a deployment is submitted for an
+// asset that is not waiting for one
+var ErrDeploymentConflict = errors.New("asset is not awaiting a deployment")
+
+// TokenDeployer deploys the ERC-20 contract of each asset through the
+// FansMint factory and follows the transactions until they are confirmed
+// or failed. Transactions are signed either by a server-held key or, for
+// prepared deployments, by the owner's own wallet.
+type TokenDeployer struct {
+	chain   *evm.Client
+	assets  repository.AssetRepository
+	key     *evm.PrivateKey
+	factory evm.Address
+	chainID *big.Int
+
+	// Confirmations is how deep a receipt must be before it is final
+	Confirmations uint64
+	// MetadataBaseURL, followed by the asset ID, is the token metadata URI
+	MetadataBaseURL string
+	// DropTimeout is how long a deployment stays unmined before the node is
+	// asked whether another transaction took its nonce, failing it if so
+	DropTimeout time.Duration
+
+	// mu serializes server-signed submissions so each takes the next nonce
+	mu           sync.Mutex
+	chainMu      sync.Mutex
+	chainChecked bool
+}
+
+// UnsignedTransaction is an EIP-1559 transaction for a wallet to sign, in
+// the hex quantity form accepted by eth_sendTransaction
+type UnsignedTransaction struct {
+	Type                 string `json:"type"`
+	ChainID              string `json:"chainId"`
+	From                 string `json:"from"`
+	To                   string `json:"to"`
+	Data                 string `json:"data"`
+	Value                string `json:"value"`
+	Nonce                string `json:"nonce"`
+	Gas                  string `json:"gas"`
+	MaxFeePerGas         string `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas"`
+}
+
+// NewTokenDeployer creates a TokenDeployer for the factory on the chain
+// identified by chainID. key signs server-side deployments; without it
+// only wallet-signed deployments are possible.
+func NewTokenDeployer(chain *evm.Client, assets repository.AssetRepository, key *evm.PrivateKey, factory evm.Address, chainID int64) *TokenDeployer {
+	return &TokenDeployer{
+		chain:         chain,
+		assets:        assets,
+		key:           key,
+		factory:       factory,
+		chainID:       big.NewInt(chainID),
+		Confirmations: DefaultConfirmations,
+		DropTimeout:   DefaultDropTimeout,
+	}
+}
+
+// ServerSigned reports whether deployments can be signed by the server
+func (d *TokenDeployer) ServerSigned() bool {
+	return d.key != nil
+}
+
+// Deploy submits the factory call creating the token of asset, signed by
+// the server key, and returns the pending deployment
+func (d *TokenDeployer) Deploy(ctx context.Context, asset client.Asset) (*client.Deployment, error) {
+	if d.key == nil {
+		return nil, ErrDeploymentDisabled
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	tx, err := d.buildTransaction(ctx, asset, d.key.Address())
+	if err != nil {
+		return nil, err
+	}
+	tx.Sign(d.key)
+	hash, err := d.chain.SendTransaction(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.pending(hash, d.key.Address(), tx.Nonce), nil
+}
+
+// Prepare builds the unsigned factory call creating the token of asset,
+// to be signed by its owner
+func (d *TokenDeployer) Prepare(ctx context.Context, asset client.Asset) (*UnsignedTransaction, error) {
+	owner, err := evm.ParseAddress(asset.CreatorAddress)
+	if err != nil {
+		return nil, &ValidationError{Fields: []FieldError{{Field: "ownerAddress", Message: "must be a 0x-prefixed 40 digit hex address"}}}
+	}
+
+	tx, err := d.buildTransaction(ctx, asset, owner)
+	if err != nil {
+		return nil, err
+	}
+	return &UnsignedTransaction{
+		Type:                 hexQuantity(big.NewInt(evm.DynamicFeeTxType)),
+		ChainID:              hexQuantity(tx.ChainID),
+		From:                 owner.Hex(),
+		To:                   tx.To.Hex(),
+		Data:                 "0x" + hex.EncodeToString(tx.Data),
+		Value:                hexQuantity(tx.Value),
+		Nonce:                hexQuantity(new(big.Int).SetUint64(tx.Nonce)),
+		Gas:                  hexQuantity(new(big.Int).SetUint64(tx.Gas)),
+		MaxFeePerGas:         hexQuantity(tx.GasFeeCap),
+		MaxPriorityFeePerGas: hexQuantity(tx.GasTipCap),
+	}, nil
+}
+
+// SubmitRaw checks that raw is the owner-signed factory call prepared for
+// asset, broadcasts it and returns the pending deployment
+func (d *TokenDeployer) SubmitRaw(ctx context.Context, asset client.Asset, raw []byte) (*client.Deployment, error) {
+	tx, err := evm.DecodeTransaction(raw)
+	if err != nil {
+		return nil, invalidSubmission("rawTransaction", err.Error())
+	}
+	if tx.ChainID.Cmp(d.chainID) != 0 {
+		return nil, invalidSubmission("rawTransaction", fmt.Sprintf("is signed for chain %s, want %s", tx.ChainID, d.chainID))
+	}
+	sender, err := tx.Sender()
+	if err != nil {
+		return nil, invalidSubmission("rawTransaction", err.Error())
+	}
+	if err := d.checkCall(asset, sender, tx.To, tx.Data, tx.Value, "rawTransaction"); err != nil {
+		return nil, err
+	}
+
+	hash, err := d.chain.SendRawTransaction(ctx, raw)
+	var rpcErr *evm.RPCError
+	if errors.As(err, &rpcErr) {
+		// The node refusing the transaction is the signer's problem to fix
+		return nil, invalidSubmission("rawTransaction", "rejected by the node: "+rpcErr.Message)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return d.pending(hash, sender, tx.Nonce), nil
+}
+
+// SubmitHash checks that the transaction hash, already broadcast by the
+// owner's wallet, is the factory call prepared for asset and returns the
+// pending deployment
+func (d *TokenDeployer) SubmitHash(ctx context.Context, asset client.Asset, txHash string) (*client.Deployment, error) {
+	hash, err := evm.ParseHash(txHash)
+	if err != nil {
+		return nil, invalidSubmission("txHash", "must be a 0x-prefixed 64 digit hex hash")
+	}
+	tx, err := d.chain.TransactionByHash(ctx, hash)
+	if errors.Is(err, evm.ErrUnknownTransaction) {
+		return nil, invalidSubmission("txHash", "is not known to the node")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := d.checkCall(asset, tx.From, tx.To, tx.Data, tx.Value, "txHash"); err != nil {
+		return nil, err
+	}
+	return d.pending(hash, tx.From, tx.Nonce), nil
+}
+
+// buildTransaction builds the unsigned factory call creating the token of
+// asset, sent from the given account
+func (d *TokenDeployer) buildTransaction(ctx context.Context, asset client.Asset, from evm.Address) (*evm.Transaction, error) {
+	data, err := d.callData(asset)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.checkChain(ctx); err != nil {
+		return nil, err
+	}
+
+	nonce, err := d.chain.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	tipCap, feeCap, err := d.chain.SuggestFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gas, err := d.chain.EstimateGas(ctx, evm.CallMsg{From: &from, To: d.factory, Data: data})
+	if err != nil {
+		return nil, fmt.Errorf("error estimating deployment gas: %w", err)
+	}
+
+	return &evm.Transaction{
+		ChainID:   d.chainID,
+		Nonce:     nonce,
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Gas:       gas + gas*gasMargin/100,
+		To:        &d.factory,
+		Value:     new(big.Int),
+		Data:      data,
+	}, nil
+}
+
+// callData returns the factory call creating the token of asset, minting
+// its whole supply to its creator
+func (d *TokenDeployer) callData(asset client.Asset) ([]byte, error) {
+	owner, err := evm.ParseAddress(asset.CreatorAddress)
+	if err != nil {
+		return nil, fmt.Errorf("asset %s has no EVM owner: %w", asset.ID, err)
+	}
+	supply, ok := new(big.Int).SetString(asset.TotalSupply, 10)
+	if !ok || supply.Sign() <= 0 {
+		return nil, fmt.Errorf("asset %s has no supply in base units: %q", asset.ID, asset.TotalSupply)
+	}
+
+	return evm.EncodeCreateToken(evm.CreateTokenParams{
+		Name:          asset.Name,
+		Symbol:        asset.Symbol,
+		Decimals:      asset.Decimals,
+		InitialSupply: supply,
+		Owner:         owner,
+		MetadataURI:   d.metadataURI(asset.ID),
+	})
+}
+
+// checkCall verifies that a wallet-signed transaction is the call prepared
+// for asset, reporting mismatches against field
+func (d *TokenDeployer) checkCall(asset client.Asset, from evm.Address, to *evm.Address, data []byte, value *big.Int, field string) error {
+	want, err := d.callData(asset)
+	if err != nil {
+		return err
+	}
+	switch {
+	case !strings.EqualFold(from.Hex(), asset.CreatorAddress):
+		return invalidSubmission(field, "must be signed by the asset owner "+asset.CreatorAddress)
+	case to == nil || *to != d.factory:
+		return invalidSubmission(field, "must call the FansMint factory "+d.factory.Hex())
+	case !bytes.Equal(data, want):
+		return invalidSubmission(field, "does not carry the prepared createToken call")
+	case value != nil && value.Sign() != 0:
+		return invalidSubmission(field, "must not transfer value")
+	}
+	return nil
+}
+
+// pending returns the deployment record of a broadcast transaction
+func (d *TokenDeployer) pending(hash evm.Hash, sender evm.Address, nonce uint64) *client.Deployment {
+	now := time.Now().UTC().Format(time.RFC3339)
+	return &client.Deployment{
+		Status:      client.DeploymentPending,
+		Factory:     d.factory.Hex(),
+		Sender:      sender.Hex(),
+		TxHash:      hash.Hex(),
+		Nonce:       &nonce,
+		SubmittedAt: now,
+		UpdatedAt:   now,
+	}
+}
+
+// Run checks pending deployments every interval until ctx is done
+func (d *TokenDeployer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.SyncOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Warning: Deployment tracking failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SyncOnce settles the pending deployments whose transaction is mined and
+// confirmed, recording the contract address of successful ones
+func (d *TokenDeployer) SyncOnce(ctx context.Context) error {
+	head, err := d.chain.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	assets, err := d.assets.List()
+	if err != nil {
+		return fmt.Errorf("error listing assets: %w", err)
+	}
+
+	var errs []error
+	for _, asset := range assets {
+		if asset.Deployment == nil || asset.Deployment.Status != client.DeploymentPending {
+			continue
+		}
+		if err := d.track(ctx, asset, head); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			errs = append(errs, fmt.Errorf("asset %s: %w", asset.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// track settles the pending deployment of asset when its receipt is final
+func (d *TokenDeployer) track(ctx context.Context, asset client.Asset, head uint64) error {
+	deployment := *asset.Deployment
+	hash, err := evm.ParseHash(deployment.TxHash)
+	if err != nil {
+		return d.settle(asset.ID, deployment, "", "invalid transaction hash")
+	}
+
+	receipt, err := d.chain.TransactionReceipt(ctx, hash)
+	if errors.Is(err, evm.ErrNoReceipt) {
+		submitted, _ := time.Parse(time.RFC3339, deployment.SubmittedAt)
+		if d.DropTimeout > 0 && time.Since(submitted) > d.DropTimeout {
+			return d.checkDropped(ctx, asset.ID, deployment, hash, head)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	deployment.BlockNumber = receipt.BlockNumber
+	if receipt.BlockNumber+d.Confirmations > head {
+		return nil
+	}
+	if receipt.Status != evm.ReceiptSuccess {
+		return d.settle(asset.ID, deployment, "", "transaction reverted")
+	}
+
+	factory, err := evm.ParseAddress(deployment.Factory)
+	if err != nil {
+		factory = d.factory
+	}
+	token, err := evm.CreatedToken(receipt, factory)
+	if err != nil {
+		return d.settle(asset.ID, deployment, "", err.Error())
+	}
+	return d.settle(asset.ID, deployment, token.Hex(), "")
+}
+
+// checkDropped fails a long unmined deployment once a confirmed transaction
+// of its sender has taken its nonce, as it can then never be mined. Until
+// that happens it may still be, and stays pending.
+func (d *TokenDeployer) checkDropped(ctx context.Context, assetID string, deployment client.Deployment, hash evm.Hash, head uint64) error {
+	sender, err := evm.ParseAddress(deployment.Sender)
+	if err != nil {
+		return d.settle(assetID, deployment, "", "invalid sender")
+	}
+	if deployment.Nonce == nil {
+		// Recorded without its nonce: the node has to know the transaction
+		tx, err := d.chain.TransactionByHash(ctx, hash)
+		if errors.Is(err, evm.ErrUnknownTransaction) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		deployment.Nonce = &tx.Nonce
+	}
+	if head < d.Confirmations {
+		return nil
+	}
+
+	used, err := d.chain.NonceAt(ctx, sender, head-d.Confirmations)
+	if err != nil {
+		return err
+	}
+	if used <= *deployment.Nonce {
+		return nil
+	}
+	// The transaction may have been mined since its receipt was asked for
+	if _, err := d.chain.TransactionReceipt(ctx, hash); !errors.Is(err, evm.ErrNoReceipt) {
+		return err
+	}
+	return d.settle(assetID, deployment, "", fmt.Sprintf("transaction dropped: nonce %d taken by another transaction", *deployment.Nonce))
+}
+
+// settle records the outcome of a deployment: confirmed when contract is
+// set, failed with reason otherwise. A deploying asset becomes active or
+// failed accordingly.
+func (d *TokenDeployer) settle(assetID string, deployment client.Deployment, contract, reason string) error {
+	deployment.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	if contract != "" {
+		deployment.Status = client.DeploymentConfirmed
+	} else {
+		deployment.Status = client.DeploymentFailed
+		deployment.Error = reason
+		log.Printf("Warning: Deployment of asset %s failed: %s", assetID, reason)
+	}
+
+	_, err := d.assets.Update(assetID, func(asset *client.Asset) (*repository.StatusChange, error) {
+		asset.Deployment = &deployment
+		if contract != "" {
+			asset.ContractAddress = contract
+		}
+		if asset.Status != StatusDeploying {
+			return nil, nil
+		}
+		return transition(asset, deploymentStatus(&deployment), ActorSystem, reason)
+	})
+	return err
+}
+
+// checkChain makes sure, once, that the node serves the configured chain
+// so transactions are never signed for another one
+func (d *TokenDeployer) checkChain(ctx context.Context) error {
+	d.chainMu.Lock()
+	defer d.chainMu.Unlock()
+
+	if d.chainChecked {
+		return nil
+	}
+	id, err := d.chain.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+	if id.Cmp(d.chainID) != 0 {
+		return fmt.Errorf("%w: node serves chain %s, configured for chain %s", evm.ErrRPC, id, d.chainID)
+	}
+	d.chainChecked = true
+	return nil
+}
+
+// invalidSubmission reports a rejected wallet-signed transaction
+func invalidSubmission(field, message string) error {
+	return &ValidationError{Fields: []FieldError{{Field: field, Message: message}}}
+}
+
+// hexQuantity returns the JSON-RPC encoding of n
+func hexQuantity(n *big.Int) string {
+	return "0x" + n.Text(16)
+}
+
+// metadataURI returns the token metadata URI of an asset
+func (d *TokenDeployer) metadataURI(assetID string) string {
+	if d.MetadataBaseURL == "" {
+		return ""
+	}
+	return strings.TrimSuffix(d.MetadataBaseURL, "/") + "/" + assetID
+}
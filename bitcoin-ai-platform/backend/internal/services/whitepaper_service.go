@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yourusername/bitcoin-ai-platform/internal/repository"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/exsat/client"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/textdiff"
+)
+
+// ErrWhitepaperNotFound is returned when an asset or draft has no
+// whitepaper, or not the requested version
+var ErrWhitepaperNotFound = errors.New("whitepaper not found")
+
+// MaxWhitepaperLength bounds a version of a whitepaper, in bytes of markdown
+const MaxWhitepaperLength = 200 << 10
+
+// MaxWhitepaperVersions bounds the versions kept of a whitepaper. Older
+// ones are dropped as new ones are saved, so drafts saved on every edit
+// keep a bounded history.
+const MaxWhitepaperVersions = 20
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// WhitepaperService keeps the versioned markdown whitepapers of assets and
+// drafts. Every version published for an asset is recorded on it along
+// with its content hash.
+type WhitepaperService struct {
+	store  repository.AssetRepository
+	assets AssetService
+}
+
+// NewWhitepaperService creates a new WhitepaperService storing whitepapers
+// in store and checking the owners of assets through assets
+func NewWhitepaperService(store repository.AssetRepository, assets AssetService) *WhitepaperService {
+	return &WhitepaperService{
+		store:  store,
+		assets: assets,
+	}
+}
+
+// Whitepaper is one version of the whitepaper of an asset or draft
+type Whitepaper struct {
+	repository.WhitepaperVersion
+	Versions int `json:"versions"` // number of the latest version
+	// Published tells whether this is the version the asset publishes
+	Published bool `json:"published"`
+}
+
+// WhitepaperDiff is the change between two versions of a whitepaper
+type WhitepaperDiff struct {
+	From       int    `json:"from"`
+	To         int    `json:"to"`
+	Insertions int    `json:"insertions"` // lines
+	Deletions  int    `json:"deletions"`  // lines
+	Diff       string `json:"diff"`       // unified, empty when identical
+}
+
+// assetDocument and draftDocument key the whitepapers of assets and drafts
+func assetDocument(id string) string { return "asset:" + id }
+func draftDocument(id string) string { return "draft:" + id }
+
+// ContentHash returns the hex SHA-256 of a whitepaper, as recorded for
+// its published version
+func ContentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetAssetWhitepaper retrieves a version of the whitepaper of an asset,
+// the latest for 0
+func (s *WhitepaperService) GetAssetWhitepaper(ctx context.Context, id string, version int) (*Whitepaper, error) {
+	asset, err := s.assets.GetAsset(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.get(assetDocument(id), version, asset.Whitepaper)
+}
+
+// PublishAssetWhitepaper saves content as the next version of the
+// whitepaper of an asset on behalf of its owner, who must be the caller,
+// and publishes it. Publishing the content of the latest version again
+// changes nothing.
+func (s *WhitepaperService) PublishAssetWhitepaper(ctx context.Context, id, caller, content string) (*client.Asset, error) {
+	if err := validateWhitepaper(content); err != nil {
+		return nil, err
+	}
+
+	asset, err := s.assets.GetAsset(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(caller, asset.CreatorAddress) {
+		return nil, ErrNotAssetOwner
+	}
+
+	version, err := s.record(assetDocument(id), caller, content)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.store.Update(id, func(asset *client.Asset) (*repository.StatusChange, error) {
+		if asset.Whitepaper == nil || asset.Whitepaper.Version != version.Version {
+			asset.Whitepaper = &client.Whitepaper{
+				Version:     version.Version,
+				ContentHash: version.ContentHash,
+				PublishedAt: time.Now().UTC().Format(time.RFC3339),
+			}
+		}
+		return nil, nil
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrAssetNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error writing asset store: %w", err)
+	}
+	formatSupplies(updated)
+	return updated, nil
+}
+
+// DiffAssetWhitepaper compares two versions of the whitepaper of an asset.
+// By default to is the latest version and from the one before it.
+func (s *WhitepaperService) DiffAssetWhitepaper(ctx context.Context, id string, from, to int) (*WhitepaperDiff, error) {
+	if _, err := s.assets.GetAsset(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.diff(assetDocument(id), from, to)
+}
+
+// validateWhitepaper checks the content of a new version
+func validateWhitepaper(content string) error {
+	var invalid ValidationError
+	if strings.TrimSpace(content) == "" {
+		invalid.Add("content", "is required")
+	}
+	if len(content) > MaxWhitepaperLength {
+		invalid.Add("content", fmt.Sprintf("must not exceed %d bytes", MaxWhitepaperLength))
+	}
+	return invalid.Err()
+}
+
+// record saves content as the next version of the whitepaper of doc,
+// unless it is the content of the latest version, which is returned. The
+// store compares them in the same transaction, so concurrent saves of the
+// same content add one version. Only the latest MaxWhitepaperVersions
+// versions are kept.
+func (s *WhitepaperService) record(doc, author, content string) (*repository.WhitepaperVersion, error) {
+	version, err := s.store.AddWhitepaper(doc, repository.WhitepaperVersion{
+		Content:     content,
+		ContentHash: ContentHash(content),
+		Author:      author,
+		CreatedAt:   time.Now().UTC(),
+	}, MaxWhitepaperVersions)
+	if err != nil {
+		return nil, fmt.Errorf("error writing whitepaper store: %w", err)
+	}
+	return version, nil
+}
+
+// get returns a version of the whitepaper of doc, the latest for 0.
+// published is the version the asset publishes, if any.
+func (s *WhitepaperService) get(doc string, version int, published *client.Whitepaper) (*Whitepaper, error) {
+	if version < 0 {
+		return nil, &ValidationError{Fields: []FieldError{{Field: "version", Message: "must be a positive integer"}}}
+	}
+
+	latest, err := s.version(doc, 0)
+	if err != nil {
+		return nil, err
+	}
+	v := latest
+	if version != 0 && version != latest.Version {
+		if v, err = s.version(doc, version); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Whitepaper{
+		WhitepaperVersion: *v,
+		Versions:          latest.Version,
+		Published:         published != nil && published.Version == v.Version,
+	}, nil
+}
+
+// diff compares two versions of the whitepaper of doc
+func (s *WhitepaperService) diff(doc string, from, to int) (*WhitepaperDiff, error) {
+	var invalid ValidationError
+	if from < 0 {
+		invalid.Add("from", "must be a positive integer")
+	}
+	if to < 0 {
+		invalid.Add("to", "must be a positive integer")
+	}
+	if err := invalid.Err(); err != nil {
+		return nil, err
+	}
+
+	newer, err := s.version(doc, to)
+	if err != nil {
+		return nil, err
+	}
+	if from == 0 {
+		// The first version compares against an empty document
+		from = max(newer.Version-1, 0)
+	}
+	older := &repository.WhitepaperVersion{}
+	if from != 0 {
+		if older, err = s.version(doc, from); err != nil {
+			return nil, err
+		}
+	}
+
+	lines := textdiff.Lines(older.Content, newer.Content)
+	insertions, deletions := textdiff.Stats(lines)
+	return &WhitepaperDiff{
+		From:       from,
+		To:         newer.Version,
+		Insertions: insertions,
+		Deletions:  deletions,
+		Diff:       textdiff.Unified(fmt.Sprintf("version %d", from), fmt.Sprintf("version %d", newer.Version), lines, diffContext),
+	}, nil
+}
+
+// version reads a version of the whitepaper of doc, the latest for 0
+func (s *WhitepaperService) version(doc string, version int) (*repository.WhitepaperVersion, error) {
+	v, err := s.store.Whitepaper(doc, version)
+	if errors.Is(err, repository.ErrNotFound) {
+		if version == 0 {
+			return nil, ErrWhitepaperNotFound
+		}
+		return nil, fmt.Errorf("%w: no version %d", ErrWhitepaperNotFound, version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading whitepaper store: %w", err)
+	}
+	return v, nil
+}
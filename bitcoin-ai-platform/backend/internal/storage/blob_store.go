@@ -0,0 +1,19 @@
+package storage
+
+import (
+	"errors"
+)
+
+// ErrNotFound is returned when a blob does not exist
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores opaque binary objects such as uploaded images.
+// Keys are slash separated paths, e.g. "icons/ast_1/original.png".
+type BlobStore interface {
+	// Put stores data under key, replacing any previous object
+	Put(key string, data []byte) error
+	// Get returns the data stored under key or ErrNotFound
+	Get(key string) ([]byte, error)
+	// Delete removes the object stored under key, if any
+	Delete(key string) error
+}
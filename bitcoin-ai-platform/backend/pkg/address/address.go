@@ -0,0 +1,336 @@
+// Package address recognizes, checks and normalizes the EVM and Bitcoin
+// addresses exSat bridges between.
+package address
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/yourusername/bitcoin-ai-platform/pkg/evm"
+)
+
+// Kind is the type of an address
+type Kind string
+
+// Supported kinds of address
+const (
+	KindEVM    Kind = "evm"
+	KindP2PKH  Kind = "p2pkh"  // legacy, base58 starting with 1, m or n
+	KindP2SH   Kind = "p2sh"   // script hash, base58 starting with 3 or 2
+	KindP2WPKH Kind = "p2wpkh" // native segwit key hash, bech32
+	KindP2WSH  Kind = "p2wsh"  // native segwit script hash, bech32
+	KindP2TR   Kind = "p2tr"   // taproot, bech32m
+)
+
+// Network is a Bitcoin network
+type Network string
+
+// Supported Bitcoin networks. Testnet and signet share their address
+// formats, so an address valid on one is valid on the other.
+const (
+	Mainnet Network = "mainnet"
+	Testnet Network = "testnet"
+	Signet  Network = "signet"
+)
+
+// ErrInvalid is matched by every address error
+var ErrInvalid = errors.New("invalid address")
+
+// ErrChecksum is matched when an address fails its checksum
+var ErrChecksum = errors.New("address checksum mismatch")
+
+// ErrWrongNetwork is matched when a Bitcoin address belongs to another network
+var ErrWrongNetwork = errors.New("address of another network")
+
+// Error describes why an address was rejected, phrased to follow the name
+// of the field holding it
+type Error struct {
+	Reason string
+	kind   error // ErrChecksum, ErrWrongNetwork or nil
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	return e.Reason
+}
+
+// Is matches ErrInvalid and the kind of the error
+func (e *Error) Is(target error) bool {
+	return target == ErrInvalid || (e.kind != nil && target == e.kind)
+}
+
+// Address is a recognized address
+type Address struct {
+	Kind    Kind    `json:"kind"`
+	Network Network `json:"network,omitempty"` // Bitcoin addresses only
+	// Normalized is the canonical form: EIP-55 checksummed for EVM
+	// addresses, lower case for bech32 ones
+	Normalized string `json:"address"`
+}
+
+// IsBitcoin reports whether a is a Bitcoin address
+func (a *Address) IsBitcoin() bool {
+	return a.Kind != KindEVM
+}
+
+// ParseNetwork parses a network name
+func ParseNetwork(s string) (Network, error) {
+	switch n := Network(strings.ToLower(strings.TrimSpace(s))); n {
+	case Mainnet, Testnet, Signet:
+		return n, nil
+	}
+	return "", fmt.Errorf("unknown Bitcoin network %q: want mainnet, testnet or signet", s)
+}
+
+// Parse recognizes an EVM address, or a Bitcoin address of network
+func Parse(s string, network Network) (*Address, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		return ParseEVM(s)
+	}
+	return ParseBitcoin(s, network)
+}
+
+// ParseEVM checks a 0x-prefixed EVM address. Mixed-case addresses must
+// carry a valid EIP-55 checksum; all lower or upper case ones have none.
+func ParseEVM(s string) (*Address, error) {
+	a, err := evm.ParseAddress(s)
+	if err != nil {
+		return nil, &Error{Reason: "must be a 0x-prefixed 40 digit hex EVM address"}
+	}
+	digits := s[2:]
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) && "0x"+digits != a.Hex() {
+		return nil, &Error{Reason: "has an invalid EIP-55 checksum, expected " + a.Hex(), kind: ErrChecksum}
+	}
+	return &Address{Kind: KindEVM, Normalized: a.Hex()}, nil
+}
+
+// ParseBitcoin checks a base58check or bech32 Bitcoin address of network
+func ParseBitcoin(s string, network Network) (*Address, error) {
+	if s == "" {
+		return nil, &Error{Reason: "must be an EVM or Bitcoin address"}
+	}
+	if i := strings.LastIndexByte(s, '1'); i > 0 && isBech32HRP(strings.ToLower(s[:i])) {
+		return parseSegwit(s, network)
+	}
+	return parseBase58(s, network)
+}
+
+// bitcoinParams are the address prefixes of a network
+type bitcoinParams struct {
+	pubKeyHash byte
+	scriptHash byte
+	hrp        string
+}
+
+var networkParams = map[Network]bitcoinParams{
+	Mainnet: {pubKeyHash: 0x00, scriptHash: 0x05, hrp: "bc"},
+	Testnet: {pubKeyHash: 0x6f, scriptHash: 0xc4, hrp: "tb"},
+	Signet:  {pubKeyHash: 0x6f, scriptHash: 0xc4, hrp: "tb"},
+}
+
+// networkName names the network of address prefixes, for error messages
+func networkName(p bitcoinParams) string {
+	if p.hrp == "bc" {
+		return string(Mainnet)
+	}
+	return "testnet or signet"
+}
+
+func isBech32HRP(hrp string) bool {
+	return hrp == "bc" || hrp == "tb"
+}
+
+// parseBase58 checks a P2PKH or P2SH address
+func parseBase58(s string, network Network) (*Address, error) {
+	payload, err := decodeBase58Check(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) != 21 {
+		return nil, &Error{Reason: "must be an EVM or Bitcoin address"}
+	}
+
+	want := networkParams[network]
+	switch payload[0] {
+	case want.pubKeyHash:
+		return &Address{Kind: KindP2PKH, Network: network, Normalized: s}, nil
+	case want.scriptHash:
+		return &Address{Kind: KindP2SH, Network: network, Normalized: s}, nil
+	}
+	for _, other := range []bitcoinParams{networkParams[Mainnet], networkParams[Testnet]} {
+		if payload[0] == other.pubKeyHash || payload[0] == other.scriptHash {
+			return nil, wrongNetwork(other, network)
+		}
+	}
+	return nil, &Error{Reason: "must be an EVM or Bitcoin address"}
+}
+
+// parseSegwit checks a bech32 (witness v0) or bech32m (v1) address
+func parseSegwit(s string, network Network) (*Address, error) {
+	hrp, data, variant, err := decodeBech32(s)
+	if err != nil {
+		return nil, err
+	}
+	if want := networkParams[network]; hrp != want.hrp {
+		return nil, wrongNetwork(bitcoinParams{hrp: hrp}, network)
+	}
+	if len(data) == 0 {
+		return nil, &Error{Reason: "is a segwit address without a witness program"}
+	}
+
+	version := data[0]
+	program, ok := convertBits(data[1:], 5, 8, false)
+	if !ok || len(program) < 2 || len(program) > 40 {
+		return nil, &Error{Reason: "has an invalid witness program"}
+	}
+
+	var kind Kind
+	switch {
+	case version == 0 && variant != bech32:
+		return nil, &Error{Reason: "is a witness v0 address that must use bech32, not bech32m", kind: ErrChecksum}
+	case version != 0 && variant != bech32m:
+		return nil, &Error{Reason: fmt.Sprintf("is a witness v%d address that must use bech32m", version), kind: ErrChecksum}
+	case version == 0 && len(program) == 20:
+		kind = KindP2WPKH
+	case version == 0 && len(program) == 32:
+		kind = KindP2WSH
+	case version == 1 && len(program) == 32:
+		kind = KindP2TR
+	default:
+		return nil, &Error{Reason: fmt.Sprintf("is an unsupported witness v%d program of %d bytes", version, len(program))}
+	}
+	return &Address{Kind: kind, Network: network, Normalized: strings.ToLower(s)}, nil
+}
+
+func wrongNetwork(p bitcoinParams, network Network) error {
+	return &Error{Reason: fmt.Sprintf("is a Bitcoin %s address, expected %s", networkName(p), network), kind: ErrWrongNetwork}
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// decodeBase58Check decodes s and verifies its double SHA-256 checksum
+func decodeBase58Check(s string) ([]byte, error) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for _, c := range s {
+		digit := strings.IndexRune(base58Alphabet, c)
+		if digit < 0 {
+			return nil, &Error{Reason: "must be an EVM or Bitcoin address"}
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(digit)))
+	}
+
+	// Each leading '1' encodes a leading zero byte
+	zeros := len(s) - len(strings.TrimLeft(s, "1"))
+	decoded := append(make([]byte, zeros), n.Bytes()...)
+	if len(decoded) < 5 {
+		return nil, &Error{Reason: "must be an EVM or Bitcoin address"}
+	}
+
+	payload, checksum := decoded[:len(decoded)-4], decoded[len(decoded)-4:]
+	first := sha256.Sum256(payload)
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(second[:4], checksum) {
+		return nil, &Error{Reason: "has an invalid base58 checksum", kind: ErrChecksum}
+	}
+	return payload, nil
+}
+
+// bech32 variants, identified by their checksum constant (BIP-173, BIP-350)
+const (
+	bech32  = 1
+	bech32m = 0x2bc830a3
+)
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// decodeBech32 decodes s into its human-readable part and 5-bit data,
+// without the checksum, and reports the checksum variant
+func decodeBech32(s string) (hrp string, data []byte, variant int, err error) {
+	if len(s) > 90 {
+		return "", nil, 0, &Error{Reason: "is longer than 90 characters"}
+	}
+	if s != strings.ToLower(s) && s != strings.ToUpper(s) {
+		return "", nil, 0, &Error{Reason: "mixes upper and lower case"}
+	}
+	s = strings.ToLower(s)
+
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 1 || sep+7 > len(s) {
+		return "", nil, 0, &Error{Reason: "must be an EVM or Bitcoin address"}
+	}
+	hrp = s[:sep]
+	for _, c := range s[sep+1:] {
+		digit := strings.IndexRune(bech32Charset, c)
+		if digit < 0 {
+			return "", nil, 0, &Error{Reason: fmt.Sprintf("has the invalid bech32 character %q", c)}
+		}
+		data = append(data, byte(digit))
+	}
+
+	switch variant = bech32Polymod(append(hrpExpand(hrp), data...)); variant {
+	case bech32, bech32m:
+	default:
+		return "", nil, 0, &Error{Reason: "has an invalid bech32 checksum", kind: ErrChecksum}
+	}
+	return hrp, data[:len(data)-6], variant, nil
+}
+
+func bech32Polymod(values []byte) int {
+	generator := [5]int{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := 1
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ int(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func hrpExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// convertBits regroups data from groups of from bits into groups of to bits
+func convertBits(data []byte, from, to uint, pad bool) ([]byte, bool) {
+	acc, bits := 0, uint(0)
+	maxv := 1<<to - 1
+	var out []byte
+	for _, v := range data {
+		if int(v)>>from != 0 {
+			return nil, false
+		}
+		acc = acc<<from | int(v)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(to-bits)&maxv))
+		}
+	} else if bits >= from || acc<<(to-bits)&maxv != 0 {
+		return nil, false
+	}
+	return out, true
+}
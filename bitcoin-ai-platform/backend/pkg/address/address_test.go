@@ -0,0 +1,99 @@
+package address
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	valid := []struct {
+		in      string
+		network Network
+		kind    Kind
+		want    string
+	}{
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", Testnet, KindEVM, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		{"0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359", Testnet, KindEVM, "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"},
+		{"0xDBF03B407C01E7CD3CBEA99509D93F8DDDC8C6FB", Testnet, KindEVM, "0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB"},
+		{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", Mainnet, KindP2PKH, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"},
+		{"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", Mainnet, KindP2SH, "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy"},
+		{"mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", Testnet, KindP2PKH, "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn"},
+		{"2MzQwSSnBHWHqSAqtTVQ6v47XtaisrJa1Vc", Signet, KindP2SH, "2MzQwSSnBHWHqSAqtTVQ6v47XtaisrJa1Vc"},
+		{"BC1QAR0SRRR7XFKVY5L643LYDNW9RE59GTZZWF5MDQ", Mainnet, KindP2WPKH, "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"},
+		{"bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", Mainnet, KindP2WPKH, "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"},
+		{"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", Testnet, KindP2WSH, "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7"},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", Mainnet, KindP2TR, "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0"},
+		{"tb1pqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesf3hn0c", Signet, KindP2TR, "tb1pqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesf3hn0c"},
+	}
+	for _, tc := range valid {
+		a, err := Parse(tc.in, tc.network)
+		if err != nil {
+			t.Errorf("Parse(%s, %s): %v", tc.in, tc.network, err)
+			continue
+		}
+		if a.Kind != tc.kind || a.Normalized != tc.want {
+			t.Errorf("Parse(%s) = %+v, want %s %s", tc.in, a, tc.kind, tc.want)
+		}
+	}
+
+	invalid := []struct {
+		in      string
+		network Network
+		kind    error
+	}{
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", Mainnet, ErrChecksum},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", Mainnet, ErrInvalid},
+		{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb", Mainnet, ErrChecksum},
+		{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", Testnet, ErrWrongNetwork},
+		{"mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", Mainnet, ErrWrongNetwork},
+		{"bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", Signet, ErrWrongNetwork},
+		{"bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdx", Mainnet, ErrChecksum},
+		{"bc1qar0srrr7xfkvy5l643lydnw9re59gtzzWF5MDQ", Mainnet, ErrInvalid},
+		// Witness v1 encoded with the bech32 checksum (BIP-350)
+		{"bc1pw508d6qejxtdg4c5r7qrxna3ggswkp9wqzgj4ql6dqnv", Mainnet, ErrInvalid},
+		{"not an address", Mainnet, ErrInvalid},
+		{"", Mainnet, ErrInvalid},
+	}
+	for _, tc := range invalid {
+		if _, err := Parse(tc.in, tc.network); !errors.Is(err, tc.kind) {
+			t.Errorf("Parse(%q, %s) = %v, want %v", tc.in, tc.network, err, tc.kind)
+		}
+	}
+}
+
+// TestParseSegwit covers the BIP-173 and BIP-350 rules binding witness
+// versions to checksum variants and program lengths
+func TestParseSegwit(t *testing.T) {
+	tests := []struct {
+		in      string
+		network Network
+		kind    error
+		reason  string
+	}{
+		// Witness v0 must use bech32, later versions bech32m
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh", Mainnet, ErrChecksum, "must use bech32, not bech32m"},
+		{"tb1q0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq24jc47", Testnet, ErrChecksum, "must use bech32, not bech32m"},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd", Mainnet, ErrChecksum, "v1 address that must use bech32m"},
+		{"BC1S0XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ54WELL", Mainnet, ErrChecksum, "v16 address that must use bech32m"},
+		{"bc1zqypqxpq9qcrsszg2pvxq6rs0zq9v69tu", Mainnet, ErrChecksum, "v2 address that must use bech32m"},
+		// Witness versions stop at 16
+		{"BC130XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ7ZWS8R", Mainnet, ErrInvalid, "unsupported witness v17"},
+		// Programs are 2 to 40 bytes
+		{"bc1pw5dgrnzv", Mainnet, ErrInvalid, "invalid witness program"},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7v8n0nx0muaewav253zgeav", Mainnet, ErrInvalid, "invalid witness program"},
+		// v0 programs are 20 or 32 bytes
+		{"BC1QR508D6QEJXTDG4Y5R3ZARVARYV98GJ9P", Mainnet, ErrInvalid, "unsupported witness v0 program of 16 bytes"},
+		// Valid, but not a kind FansMint records
+		{"BC1SW50QGDZ25J", Mainnet, ErrInvalid, "unsupported witness v16 program of 2 bytes"},
+		{"bc1zw508d6qejxtdg4y5r3zarvaryvaxxpcs", Mainnet, ErrInvalid, "unsupported witness v2 program of 16 bytes"},
+		{"bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kt5nd6y", Mainnet, ErrInvalid, "unsupported witness v1 program of 40 bytes"},
+	}
+	for _, tc := range tests {
+		_, err := Parse(tc.in, tc.network)
+		if !errors.Is(err, tc.kind) || !strings.Contains(fmt.Sprint(err), tc.reason) {
+			t.Errorf("Parse(%q, %s) = %v, want %v that %s", tc.in, tc.network, err, tc.kind, tc.reason)
+		}
+	}
+}
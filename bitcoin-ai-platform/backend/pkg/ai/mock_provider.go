@@ -0,0 +1,148 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MockProvider is a deterministic offline provider used for development
+// and when no language model is configured
+type MockProvider struct{}
+
+// NewMockProvider creates a new mock provider
+func NewMockProvider() *MockProvider {
+	return &MockProvider{}
+}
+
+// Name returns the provider identifier
+func (p *MockProvider) Name() string {
+	return ProviderMock
+}
+
+// GenerateWhitepaper returns a templated whitepaper for the token
+func (p *MockProvider) GenerateWhitepaper(ctx context.Context, params WhitepaperParams) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	name, symbol, useCase := params.Name, params.Symbol, params.UseCase
+	totalSupply := params.TotalSupply
+	if totalSupply == "" {
+		totalSupply = "1,000,000"
+	}
+
+	return `# ` + name + ` Whitepaper
+
+## Abstract
+` + name + ` (` + symbol + `) is a digital asset based on the Bitcoin ecosystem, designed to ` + useCase + `.
+
+## 1. Introduction
+This whitepaper outlines the vision, technology, and roadmap for ` + name + `.
+
+## 2. Technical Architecture
+` + name + ` is built on the exSat protocol, inheriting Bitcoin's security and decentralization features.
+
+## 3. Tokenomics
+- Total Supply: ` + totalSupply + ` ` + symbol + `
+- Distribution:
+  * Team: 15%
+  * Community: 30% 
+  * Liquidity: 25%
+  * Ecosystem Development: 30%
+
+## 4. Use Cases
+` + useCase + `
+
+## 5. Roadmap
+- Phase 1: Token Issuance and Initial Distribution
+- Phase 2: Ecosystem Building and Partnership Expansion
+- Phase 3: Feature Extension and Use Case Implementation
+
+## 6. Team
+Our team consists of blockchain experts, security engineers, and industry advisors dedicated to building secure and efficient blockchain applications.
+
+## 7. Conclusion
+` + name + ` will provide innovative solutions for ` + useCase + `, and with the advantages of the exSat platform, we are confident in creating value within the Bitcoin ecosystem.
+`, nil
+}
+
+// mockStreamDelay is the pause between streamed chunks, so the UI behaves as with a real model
+const mockStreamDelay = 30 * time.Millisecond
+
+// StreamWhitepaper streams the templated whitepaper line by line.
+// Usage is estimated from whitespace separated words.
+func (p *MockProvider) StreamWhitepaper(ctx context.Context, params WhitepaperParams, onChunk func(string) error) (Usage, error) {
+	content, err := p.GenerateWhitepaper(ctx, params)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	usage := Usage{PromptTokens: len(strings.Fields(params.Description + " " + params.UseCase))}
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if line == "" {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return usage, ctx.Err()
+		case <-time.After(mockStreamDelay):
+		}
+
+		if err := onChunk(line); err != nil {
+			return usage, err
+		}
+		usage.CompletionTokens += len(strings.Fields(line))
+	}
+	usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+
+	return usage, nil
+}
+
+// GenerateTokenSuggestions returns suggestions derived from the letters of the use case
+func (p *MockProvider) GenerateTokenSuggestions(ctx context.Context, useCase string) ([]TokenSuggestion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Build a stem from the letters of the use case so short or symbol-only
+	// inputs still produce valid names and symbols
+	var letters []rune
+	for _, r := range useCase {
+		if unicode.IsLetter(r) && r < unicode.MaxASCII {
+			letters = append(letters, unicode.ToUpper(r))
+		}
+	}
+	stem := "FAN"
+	if len(letters) >= 3 {
+		stem = string(letters)
+	}
+	if len(stem) > 5 {
+		stem = stem[:5]
+	}
+	word := stem[:1] + strings.ToLower(stem[1:])
+
+	variants := []struct {
+		suffix       string
+		symbolSuffix string
+	}{
+		{"Token", ""},
+		{"Fan", "F"},
+		{"Club", "C"},
+	}
+
+	suggestions := make([]TokenSuggestion, 0, len(variants))
+	for i, v := range variants {
+		suggestions = append(suggestions, TokenSuggestion{
+			Rank:            i + 1,
+			Name:            word + v.suffix,
+			Symbol:          stem[:3] + v.symbolSuffix,
+			Description:     "A token focused on " + useCase + ", built on the Bitcoin ecosystem",
+			UseCase:         useCase,
+			MarketPotential: "This token has significant potential in the growing DeFi and cross-chain application space.",
+		})
+	}
+	return suggestions, nil
+}
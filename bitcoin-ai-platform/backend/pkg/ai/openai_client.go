@@ -4,31 +4,77 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
+	"io"
+	"time"
 
 	"github.com/sashabaranov/go-openai"
 )
 
-// OpenAIClient handles interactions with the OpenAI API
+// OpenAIClient handles interactions with the OpenAI API or any server
+// implementing the OpenAI chat completions API (llama.cpp, Ollama, vLLM...)
 type OpenAIClient struct {
-	client *openai.Client
+	client        *openai.Client
+	model         string
+	name          string
+	timeout       time.Duration
+	streamTimeout time.Duration
 }
 
+// Default generation deadlines of OpenAIClient
+const (
+	DefaultTimeout       = 60 * time.Second
+	DefaultStreamTimeout = 3 * time.Minute
+)
+
 // NewOpenAIClient creates a new OpenAI client
-func NewOpenAIClient() (*OpenAIClient, error) {
-	apiKey := os.Getenv("OPENAI_API_KEY")
+func NewOpenAIClient(apiKey string) (*OpenAIClient, error) {
 	if apiKey == "" {
-		return nil, errors.New("OPENAI_API_KEY environment variable is required")
+		return nil, errors.New("an OpenAI API key is required")
+	}
+
+	return NewOpenAIClientWithConfig(apiKey, "", ""), nil
+}
+
+// NewOpenAIClientWithConfig creates a client for the given base URL and model.
+// An empty baseURL targets api.openai.com and an empty model uses GPT-4 Turbo.
+func NewOpenAIClientWithConfig(apiKey, baseURL, model string) *OpenAIClient {
+	config := openai.DefaultConfig(apiKey)
+	name := ProviderOpenAI
+	if baseURL != "" {
+		config.BaseURL = baseURL
+		name = ProviderOpenAICompatible
+	}
+	if model == "" {
+		model = openai.GPT4Turbo
 	}
 
-	client := openai.NewClient(apiKey)
 	return &OpenAIClient{
-		client: client,
-	}, nil
+		client:        openai.NewClientWithConfig(config),
+		model:         model,
+		name:          name,
+		timeout:       DefaultTimeout,
+		streamTimeout: DefaultStreamTimeout,
+	}
 }
 
-// GenerateWhitepaper generates a whitepaper for a token
-func (c *OpenAIClient) GenerateWhitepaper(tokenName, tokenSymbol, description, useCase, tokenType, totalSupply string) (string, error) {
+// withTimeouts overrides the generation deadlines that are positive
+func (c *OpenAIClient) withTimeouts(timeout, streamTimeout time.Duration) *OpenAIClient {
+	if timeout > 0 {
+		c.timeout = timeout
+	}
+	if streamTimeout > 0 {
+		c.streamTimeout = streamTimeout
+	}
+	return c
+}
+
+// Name returns the provider identifier
+func (c *OpenAIClient) Name() string {
+	return c.name
+}
+
+// whitepaperRequest builds the chat completion request used to generate a whitepaper
+func (c *OpenAIClient) whitepaperRequest(params WhitepaperParams) openai.ChatCompletionRequest {
 	prompt := fmt.Sprintf(`
 Generate a concise whitepaper for a Bitcoin ecosystem token with the following details:
 - Name: %s
@@ -46,83 +92,133 @@ The whitepaper should include:
 5. A roadmap and conclusion
 
 Format the whitepaper in Markdown format with headers and bullet points.
-`, tokenName, tokenSymbol, description, useCase, tokenType, totalSupply)
-
-	resp, err := c.client.CreateChatCompletion(
-		context.Background(),
-		openai.ChatCompletionRequest{
-			Model: openai.GPT4Turbo,
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role:    openai.ChatMessageRoleSystem,
-					Content: "You are a blockchain whitepaper expert who specializes in creating professional whitepapers for cryptocurrency projects.",
-				},
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: prompt,
-				},
+`, params.Name, params.Symbol, params.Description, params.UseCase, params.TokenType, params.TotalSupply)
+
+	return openai.ChatCompletionRequest{
+		Model: c.model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleSystem,
+				Content: "You are a blockchain whitepaper expert who specializes in creating professional whitepapers for cryptocurrency projects.",
+			},
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: prompt,
 			},
-			MaxTokens: 2000,
 		},
-	)
+		MaxTokens: 2000,
+	}
+}
 
+// GenerateWhitepaper generates a whitepaper for a token
+func (c *OpenAIClient) GenerateWhitepaper(ctx context.Context, params WhitepaperParams) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	resp, err := c.client.CreateChatCompletion(ctx, c.whitepaperRequest(params))
 	if err != nil {
-		return "", fmt.Errorf("error calling OpenAI API: %w", err)
+		return "", fmt.Errorf("%w: error calling %s API: %w", ErrProvider, c.name, err)
 	}
 
 	if len(resp.Choices) == 0 {
-		return "", errors.New("no response from OpenAI API")
+		return "", fmt.Errorf("%w: no response from %s API", ErrProvider, c.name)
 	}
 
 	return resp.Choices[0].Message.Content, nil
 }
 
-// GenerateTokenSuggestions generates token suggestions based on the use case
-func (c *OpenAIClient) GenerateTokenSuggestions(useCase string) (map[string]interface{}, error) {
+// StreamWhitepaper generates a whitepaper for a token, passing each markdown
+// delta to onChunk as it arrives. The stream stops when ctx is cancelled.
+func (c *OpenAIClient) StreamWhitepaper(ctx context.Context, params WhitepaperParams, onChunk func(string) error) (Usage, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.streamTimeout)
+	defer cancel()
+
+	req := c.whitepaperRequest(params)
+	req.Stream = true
+	req.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+
+	stream, err := c.client.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		return Usage{}, fmt.Errorf("%w: error calling %s API: %w", ErrProvider, c.name, err)
+	}
+	defer stream.Close()
+
+	var usage Usage
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return usage, nil
+		}
+		if err != nil {
+			return usage, fmt.Errorf("%w: error reading %s stream: %w", ErrProvider, c.name, err)
+		}
+
+		if resp.Usage != nil {
+			usage = Usage{
+				PromptTokens:     resp.Usage.PromptTokens,
+				CompletionTokens: resp.Usage.CompletionTokens,
+				TotalTokens:      resp.Usage.TotalTokens,
+			}
+		}
+		if len(resp.Choices) == 0 || resp.Choices[0].Delta.Content == "" {
+			continue
+		}
+		if err := onChunk(resp.Choices[0].Delta.Content); err != nil {
+			return usage, err
+		}
+	}
+}
+
+// GenerateTokenSuggestions generates ranked token suggestions based on the use case
+func (c *OpenAIClient) GenerateTokenSuggestions(ctx context.Context, useCase string) ([]TokenSuggestion, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
 	prompt := fmt.Sprintf(`
-Based on the following use case, suggest a name, symbol, and description for a Bitcoin ecosystem token:
+Based on the following use case, suggest %d names, symbols, and descriptions for a Bitcoin ecosystem token:
 Use Case: %s
 
-Generate a JSON object with:
+Respond with a JSON object of the form:
+{"suggestions": [{"name": "", "symbol": "", "description": "", "useCase": "", "marketPotential": ""}]}
+
+Each suggestion must have:
 1. A creative and relevant name
-2. A 3-4 letter symbol 
+2. An uppercase symbol of %d to %d letters or digits, ideally 3 or 4 letters
 3. A concise description
-4. Analysis of market potential
-`, useCase)
+4. The use case it addresses
+5. Analysis of market potential
+
+Order the suggestions from best to worst.
+`, DefaultSuggestionCount, useCase, MinSymbolLength, MaxSymbolLength)
 
 	resp, err := c.client.CreateChatCompletion(
-		context.Background(),
+		ctx,
 		openai.ChatCompletionRequest{
-			Model: openai.GPT4Turbo,
+			Model: c.model,
 			Messages: []openai.ChatCompletionMessage{
 				{
 					Role:    openai.ChatMessageRoleSystem,
-					Content: "You are a cryptocurrency naming expert who creates relevant, catchy, and marketable token names and descriptions.",
+					Content: "You are a cryptocurrency naming expert who creates relevant, catchy, and marketable token names and descriptions. You always answer with valid JSON.",
 				},
 				{
 					Role:    openai.ChatMessageRoleUser,
 					Content: prompt,
 				},
 			},
-			MaxTokens: 500,
+			ResponseFormat: &openai.ChatCompletionResponseFormat{
+				Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+			},
+			MaxTokens: 1000,
 		},
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("error calling OpenAI API: %w", err)
+		return nil, fmt.Errorf("%w: error calling %s API: %w", ErrProvider, c.name, err)
 	}
 
 	if len(resp.Choices) == 0 {
-		return nil, errors.New("no response from OpenAI API")
+		return nil, fmt.Errorf("%w: no response from %s API", ErrProvider, c.name)
 	}
 
-	// In a real implementation, we would parse the JSON response
-	// For now, we'll just return a mock suggestion
-	return map[string]interface{}{
-		"name":            fmt.Sprintf("%sToken", useCase[:5]),
-		"symbol":          useCase[:3],
-		"description":     fmt.Sprintf("A token focused on %s, built on the Bitcoin ecosystem", useCase),
-		"useCase":         useCase,
-		"marketPotential": "This token has significant potential in the growing DeFi and cross-chain application space.",
-	}, nil
+	return ParseTokenSuggestions(resp.Choices[0].Message.Content, useCase)
 }
@@ -0,0 +1,82 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Provider names accepted in ProviderConfig.Name
+const (
+	ProviderOpenAI           = "openai"
+	ProviderOpenAICompatible = "openai-compatible"
+	ProviderMock             = "mock"
+)
+
+// ErrProvider is returned when the provider API cannot be reached or fails
+var ErrProvider = errors.New("AI provider request failed")
+
+// Provider is a language model backend able to produce token creation content.
+// New generation tasks are added here and implemented by every provider.
+type Provider interface {
+	// Name returns the provider identifier, e.g. "openai" or "mock"
+	Name() string
+	// GenerateWhitepaper generates a markdown whitepaper for a token
+	GenerateWhitepaper(ctx context.Context, params WhitepaperParams) (string, error)
+	// StreamWhitepaper generates a whitepaper, calling onChunk with each
+	// markdown fragment until done, onChunk fails or ctx is cancelled
+	StreamWhitepaper(ctx context.Context, params WhitepaperParams, onChunk func(string) error) (Usage, error)
+	// GenerateTokenSuggestions generates ranked token suggestions for a use case
+	GenerateTokenSuggestions(ctx context.Context, useCase string) ([]TokenSuggestion, error)
+}
+
+// WhitepaperParams holds the token details a whitepaper is generated from
+type WhitepaperParams struct {
+	Name        string
+	Symbol      string
+	Description string
+	UseCase     string
+	TokenType   string
+	TotalSupply string
+}
+
+// Usage reports the tokens consumed by a generation
+type Usage struct {
+	PromptTokens     int `json:"promptTokens"`
+	CompletionTokens int `json:"completionTokens"`
+	TotalTokens      int `json:"totalTokens"`
+}
+
+// ProviderConfig selects and configures a Provider
+type ProviderConfig struct {
+	Name    string // openai, openai-compatible or mock
+	APIKey  string
+	BaseURL string // required for openai-compatible, e.g. http://localhost:11434/v1
+	Model   string // optional, defaults to the provider's default model
+
+	// Timeout bounds a blocking generation and StreamTimeout a streamed one.
+	// Zero keeps the provider defaults.
+	Timeout       time.Duration
+	StreamTimeout time.Duration
+}
+
+// NewProvider creates the provider described by cfg
+func NewProvider(cfg ProviderConfig) (Provider, error) {
+	switch cfg.Name {
+	case ProviderOpenAI:
+		if cfg.APIKey == "" {
+			return nil, errors.New("an API key is required for the openai provider")
+		}
+		return NewOpenAIClientWithConfig(cfg.APIKey, "", cfg.Model).withTimeouts(cfg.Timeout, cfg.StreamTimeout), nil
+	case ProviderOpenAICompatible:
+		if cfg.BaseURL == "" {
+			return nil, errors.New("a base URL is required for the openai-compatible provider")
+		}
+		return NewOpenAIClientWithConfig(cfg.APIKey, cfg.BaseURL, cfg.Model).withTimeouts(cfg.Timeout, cfg.StreamTimeout), nil
+	case ProviderMock:
+		return NewMockProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown AI provider %q", cfg.Name)
+	}
+}
@@ -0,0 +1,129 @@
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultSuggestionCount is the number of token suggestions requested from the model
+const DefaultSuggestionCount = 3
+
+// Symbol lengths, in letters or digits
+const (
+	MinSymbolLength = 2
+	MaxSymbolLength = 11
+)
+
+// ValidSymbol reports whether symbol is MinSymbolLength to MaxSymbolLength
+// uppercase letters or digits
+func ValidSymbol(symbol string) bool {
+	if len(symbol) < MinSymbolLength || len(symbol) > MaxSymbolLength {
+		return false
+	}
+	for _, c := range symbol {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// ErrInvalidResponse is returned when the model reply does not match the expected schema
+var ErrInvalidResponse = errors.New("invalid response from model")
+
+// TokenSuggestion represents a single AI generated token proposal
+type TokenSuggestion struct {
+	Rank            int    `json:"rank"`
+	Name            string `json:"name"`
+	Symbol          string `json:"symbol"`
+	Description     string `json:"description"`
+	UseCase         string `json:"useCase"`
+	MarketPotential string `json:"marketPotential"`
+}
+
+// SchemaError describes a part of the model reply that violates the suggestion schema
+type SchemaError struct {
+	Index  int    // position of the offending suggestion, -1 for the envelope
+	Field  string // JSON field name
+	Reason string
+}
+
+// Error implements the error interface
+func (e *SchemaError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("%v: %s %s", ErrInvalidResponse, e.Field, e.Reason)
+	}
+	return fmt.Sprintf("%v: suggestions[%d].%s %s", ErrInvalidResponse, e.Index, e.Field, e.Reason)
+}
+
+// Unwrap allows errors.Is(err, ErrInvalidResponse)
+func (e *SchemaError) Unwrap() error {
+	return ErrInvalidResponse
+}
+
+// tokenSuggestionsEnvelope is the JSON object the model is instructed to return
+type tokenSuggestionsEnvelope struct {
+	Suggestions []TokenSuggestion `json:"suggestions"`
+}
+
+// ParseTokenSuggestions decodes and validates a model reply into ranked suggestions.
+// The order of the suggestions in the reply is taken as their ranking. Invalid
+// suggestions are dropped; the reply is only rejected when none is valid.
+func ParseTokenSuggestions(content, useCase string) ([]TokenSuggestion, error) {
+	var envelope tokenSuggestionsEnvelope
+	if err := json.Unmarshal([]byte(content), &envelope); err != nil {
+		return nil, &SchemaError{Index: -1, Field: "suggestions", Reason: fmt.Sprintf("is not valid JSON: %v", err)}
+	}
+	if len(envelope.Suggestions) == 0 {
+		return nil, &SchemaError{Index: -1, Field: "suggestions", Reason: "is missing or empty"}
+	}
+
+	suggestions := make([]TokenSuggestion, 0, len(envelope.Suggestions))
+	var firstErr error
+	for i, s := range envelope.Suggestions {
+		s.Name = strings.TrimSpace(s.Name)
+		s.Symbol = strings.ToUpper(strings.TrimSpace(s.Symbol))
+		s.Description = strings.TrimSpace(s.Description)
+		s.MarketPotential = strings.TrimSpace(s.MarketPotential)
+		if strings.TrimSpace(s.UseCase) == "" {
+			s.UseCase = useCase
+		}
+
+		if err := validateSuggestion(i, s); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		s.Rank = len(suggestions) + 1
+		suggestions = append(suggestions, s)
+	}
+
+	if len(suggestions) == 0 {
+		return nil, firstErr
+	}
+	return suggestions, nil
+}
+
+// validateSuggestion checks that a single suggestion has every required field
+func validateSuggestion(index int, s TokenSuggestion) error {
+	if s.Name == "" {
+		return &SchemaError{Index: index, Field: "name", Reason: "is required"}
+	}
+	if s.Symbol == "" {
+		return &SchemaError{Index: index, Field: "symbol", Reason: "is required"}
+	}
+	if !ValidSymbol(s.Symbol) {
+		return &SchemaError{Index: index, Field: "symbol", Reason: fmt.Sprintf("must be %d to %d letters or digits", MinSymbolLength, MaxSymbolLength)}
+	}
+	if s.Description == "" {
+		return &SchemaError{Index: index, Field: "description", Reason: "is required"}
+	}
+	if s.MarketPotential == "" {
+		return &SchemaError{Index: index, Field: "marketPotential", Reason: "is required"}
+	}
+	return nil
+}
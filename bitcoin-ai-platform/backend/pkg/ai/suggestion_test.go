@@ -0,0 +1,80 @@
+package ai
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseTokenSuggestions(t *testing.T) {
+	// suggestion is a valid reply entry with symbol
+	suggestion := func(name, symbol string) string {
+		return `{"name":"` + name + `","symbol":"` + symbol + `","description":"For fans","marketPotential":"High"}`
+	}
+	reply := func(suggestions ...string) string {
+		return `{"suggestions":[` + strings.Join(suggestions, ",") + `]}`
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		symbols []string // of the suggestions kept, in rank order
+		err     string   // part of the error when none is kept
+	}{
+		{
+			name:    "all valid",
+			content: reply(suggestion("Moon", "moon"), suggestion("Star", " ST4R "), suggestion("Sun", "SUN")),
+			symbols: []string{"MOON", "ST4R", "SUN"},
+		},
+		{
+			name:    "symbol lengths",
+			content: reply(suggestion("Short", "X"), suggestion("Two", "XY"), suggestion("Eleven", "ABCDEFGHIJK"), suggestion("Twelve", "ABCDEFGHIJKL")),
+			symbols: []string{"XY", "ABCDEFGHIJK"},
+		},
+		{
+			name:    "invalid ones dropped",
+			content: reply(`{"name":"No Symbol","description":"d","marketPotential":"m"}`, suggestion("Dash", "MO-ON"), suggestion("Moon", "MOON"), `{"symbol":"NONAME","description":"d","marketPotential":"m"}`, `{"name":"Vague","symbol":"VAG"}`),
+			symbols: []string{"MOON"},
+		},
+		{
+			name:    "none valid",
+			content: reply(`{"name":"No Symbol"}`, suggestion("Dash", "MO-ON")),
+			err:     "suggestions[0].symbol is required",
+		},
+		{
+			name:    "empty",
+			content: `{"suggestions":[]}`,
+			err:     "suggestions is missing or empty",
+		},
+		{
+			name:    "not JSON",
+			content: "Here are some ideas: MOON",
+			err:     "suggestions is not valid JSON",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestions, err := ParseTokenSuggestions(tt.content, "fans")
+			if tt.err != "" {
+				if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("ParseTokenSuggestions = %+v, %v, want an invalid response error mentioning %q", suggestions, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTokenSuggestions: %v", err)
+			}
+
+			var symbols []string
+			for i, s := range suggestions {
+				symbols = append(symbols, s.Symbol)
+				if s.Rank != i+1 || s.UseCase != "fans" {
+					t.Errorf("suggestion %d = %+v, want rank %d with the request use case", i, s, i+1)
+				}
+			}
+			if strings.Join(symbols, ",") != strings.Join(tt.symbols, ",") {
+				t.Errorf("symbols = %v, want %v", symbols, tt.symbols)
+			}
+		})
+	}
+}
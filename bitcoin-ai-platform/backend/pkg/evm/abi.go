@@ -0,0 +1,159 @@
+package evm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+)
+
+// wordSize is the size of an ABI encoded static value
+const wordSize = 32
+
+// ErrABI is returned when call output cannot be decoded as the expected type
+var ErrABI = errors.New("invalid ABI encoded data")
+
+// maxUint256 is the largest value of a uint256
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// Selector returns the 4 byte function selector of a signature such as
+// "balanceOf(address)"
+func Selector(signature string) []byte {
+	h := Keccak256([]byte(signature))
+	return h[:4]
+}
+
+// EventTopic returns the topic identifying an event signature such as
+// "Transfer(address,address,uint256)"
+func EventTopic(signature string) Hash {
+	return Keccak256([]byte(signature))
+}
+
+// EncodeCall concatenates the selector of signature with its encoded static arguments
+func EncodeCall(signature string, args ...[]byte) []byte {
+	data := Selector(signature)
+	for _, arg := range args {
+		data = append(data, arg...)
+	}
+	return data
+}
+
+// Tail marks the tail encoding of a dynamic argument, such as the output
+// of EncodeString, in EncodeArgs
+type Tail []byte
+
+// EncodeArgs encodes a tuple of arguments. Static words ([]byte) are
+// written in place; each Tail is replaced by the offset of its data, which
+// follows the head.
+func EncodeArgs(args ...interface{}) []byte {
+	head := make([]byte, 0, len(args)*wordSize)
+	var tail []byte
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case Tail:
+			offset, _ := EncodeUint256(big.NewInt(int64(len(args)*wordSize + len(tail))))
+			head = append(head, offset...)
+			tail = append(tail, v...)
+		case []byte:
+			head = append(head, v...)
+		default:
+			panic(fmt.Sprintf("abi: unsupported argument %T", arg))
+		}
+	}
+	return append(head, tail...)
+}
+
+// EncodeAddress returns the ABI word of an address
+func EncodeAddress(a Address) []byte {
+	word := make([]byte, wordSize)
+	copy(word[wordSize-len(a):], a[:])
+	return word
+}
+
+// EncodeUint256 returns the ABI word of n, which must fit in a uint256
+func EncodeUint256(n *big.Int) ([]byte, error) {
+	if n.Sign() < 0 || n.Cmp(maxUint256) > 0 {
+		return nil, fmt.Errorf("%w: %s does not fit in a uint256", ErrABI, n)
+	}
+	return n.FillBytes(make([]byte, wordSize)), nil
+}
+
+// EncodeString returns the tail encoding of a dynamic string: its length
+// word followed by the padded bytes. The caller writes the offset word.
+func EncodeString(s string) []byte {
+	length, _ := EncodeUint256(big.NewInt(int64(len(s))))
+	padded := make([]byte, (len(s)+wordSize-1)/wordSize*wordSize)
+	copy(padded, s)
+	return append(length, padded...)
+}
+
+// DecodeUint256 decodes the first word of data
+func DecodeUint256(data []byte) (*big.Int, error) {
+	if len(data) < wordSize {
+		return nil, fmt.Errorf("%w: %d bytes, want a uint256 word", ErrABI, len(data))
+	}
+	return new(big.Int).SetBytes(data[:wordSize]), nil
+}
+
+// DecodeUint8 decodes the first word of data, checking it fits in a uint8
+func DecodeUint8(data []byte) (uint8, error) {
+	n, err := DecodeUint256(data)
+	if err != nil {
+		return 0, err
+	}
+	if !n.IsUint64() || n.Uint64() > 255 {
+		return 0, fmt.Errorf("%w: %s does not fit in a uint8", ErrABI, n)
+	}
+	return uint8(n.Uint64()), nil
+}
+
+// DecodeAddress decodes the first word of data as an address
+func DecodeAddress(data []byte) (Address, error) {
+	var a Address
+	if len(data) < wordSize {
+		return a, fmt.Errorf("%w: %d bytes, want an address word", ErrABI, len(data))
+	}
+	copy(a[:], data[wordSize-len(a):wordSize])
+	return a, nil
+}
+
+// DecodeString decodes a string return value. Tokens predating the final
+// ERC-20 ABI return bytes32 instead, which is accepted as well.
+func DecodeString(data []byte) (string, error) {
+	if len(data) == wordSize {
+		return strings.TrimRight(string(data), "\x00"), nil
+	}
+
+	return DecodeStringArg(data, 0)
+}
+
+// DecodeStringArg decodes the string argument at index of an ABI encoded tuple
+func DecodeStringArg(data []byte, index int) (string, error) {
+	if len(data) < (index+1)*wordSize {
+		return "", fmt.Errorf("%w: %d bytes, want argument %d", ErrABI, len(data), index)
+	}
+	offset, err := DecodeUint256(data[index*wordSize:])
+	if err != nil {
+		return "", err
+	}
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(data)-wordSize) {
+		return "", fmt.Errorf("%w: string offset out of range", ErrABI)
+	}
+	start := int(offset.Uint64())
+
+	length, err := DecodeUint256(data[start:])
+	if err != nil {
+		return "", err
+	}
+	start += wordSize
+	if !length.IsUint64() || length.Uint64() > uint64(len(data)-start) {
+		return "", fmt.Errorf("%w: string length out of range", ErrABI)
+	}
+
+	s := string(data[start : start+int(length.Uint64())])
+	if !utf8.ValidString(s) {
+		return "", fmt.Errorf("%w: string is not UTF-8", ErrABI)
+	}
+	return s, nil
+}
@@ -0,0 +1,270 @@
+package evm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTimeout bounds a single JSON-RPC call, overridable on Client
+const DefaultTimeout = 10 * time.Second
+
+// ErrRPC is matched by every failed JSON-RPC call, whether the node could
+// not be reached or answered with an error object
+var ErrRPC = errors.New("EVM JSON-RPC request failed")
+
+// RPCError is an error object returned by the node
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Error implements the error interface
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("JSON-RPC error %d: %s", e.Code, e.Message)
+}
+
+// Unwrap allows errors.Is(err, ErrRPC)
+func (e *RPCError) Unwrap() error {
+	return ErrRPC
+}
+
+// Client is a minimal JSON-RPC client for an EVM node such as exSat EVM
+type Client struct {
+	URL        string
+	HTTPClient *http.Client
+	// Timeout bounds each call, zero means the caller's context is the only deadline
+	Timeout time.Duration
+
+	nextID atomic.Uint64
+}
+
+// NewClient creates a client for the node at url
+func NewClient(url string) *Client {
+	return &Client{
+		URL:        url,
+		HTTPClient: &http.Client{},
+		Timeout:    DefaultTimeout,
+	}
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// call invokes method and decodes its result into result
+func (c *Client) call(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	if params == nil {
+		params = []interface{}{}
+	}
+
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: c.nextID.Add(1), Method: method, Params: params})
+	if err != nil {
+		return fmt.Errorf("error encoding %s request: %w", method, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating %s request: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrRPC, method, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil {
+		return fmt.Errorf("%w: %s: error reading response: %w", ErrRPC, method, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s: status %d", ErrRPC, method, resp.StatusCode)
+	}
+
+	var response rpcResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return fmt.Errorf("%w: %s: error decoding response: %w", ErrRPC, method, err)
+	}
+	if response.Error != nil {
+		return fmt.Errorf("%s: %w", method, response.Error)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("%w: %s: error decoding result: %w", ErrRPC, method, err)
+	}
+	return nil
+}
+
+// BlockNumber returns the number of the most recent block
+func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	var result string
+	if err := c.call(ctx, &result, "eth_blockNumber"); err != nil {
+		return 0, err
+	}
+	n, err := decodeQuantity(result)
+	if err != nil {
+		return 0, fmt.Errorf("%w: eth_blockNumber: %w", ErrRPC, err)
+	}
+	return n, nil
+}
+
+// BlockTime returns the timestamp of block
+func (c *Client) BlockTime(ctx context.Context, block uint64) (time.Time, error) {
+	var header *struct {
+		Timestamp string `json:"timestamp"`
+	}
+	if err := c.call(ctx, &header, "eth_getBlockByNumber", encodeQuantity(block), false); err != nil {
+		return time.Time{}, err
+	}
+	if header == nil {
+		return time.Time{}, fmt.Errorf("%w: eth_getBlockByNumber: block %d not found", ErrRPC, block)
+	}
+	seconds, err := decodeQuantity(header.Timestamp)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: eth_getBlockByNumber: %w", ErrRPC, err)
+	}
+	return time.Unix(int64(seconds), 0).UTC(), nil
+}
+
+// CallMsg is a contract call executed without a transaction, by eth_call
+// or eth_estimateGas
+type CallMsg struct {
+	From  *Address // optional
+	To    Address
+	Data  []byte
+	Value *big.Int // optional
+}
+
+// MarshalJSON encodes the call object expected by eth_call
+func (m CallMsg) MarshalJSON() ([]byte, error) {
+	obj := map[string]string{
+		"to":   m.To.Hex(),
+		"data": encodeHex(m.Data),
+	}
+	if m.From != nil {
+		obj["from"] = m.From.Hex()
+	}
+	if m.Value != nil {
+		obj["value"] = "0x" + m.Value.Text(16)
+	}
+	return json.Marshal(obj)
+}
+
+// Call executes msg against the state at block and returns the output
+func (c *Client) Call(ctx context.Context, msg CallMsg, block uint64) ([]byte, error) {
+	var result string
+	if err := c.call(ctx, &result, "eth_call", msg, encodeQuantity(block)); err != nil {
+		return nil, err
+	}
+	out, err := decodeHex(result)
+	if err != nil {
+		return nil, fmt.Errorf("%w: eth_call: %w", ErrRPC, err)
+	}
+	return out, nil
+}
+
+// FilterQuery selects logs for eth_getLogs. Topics are matched by position,
+// a nil position matches anything and several hashes in one position are ORed.
+type FilterQuery struct {
+	FromBlock uint64
+	ToBlock   uint64
+	Addresses []Address
+	Topics    [][]Hash
+}
+
+// MarshalJSON encodes the filter object expected by eth_getLogs
+func (q FilterQuery) MarshalJSON() ([]byte, error) {
+	topics := make([]interface{}, len(q.Topics))
+	for i, position := range q.Topics {
+		switch len(position) {
+		case 0:
+			topics[i] = nil
+		case 1:
+			topics[i] = position[0]
+		default:
+			topics[i] = position
+		}
+	}
+	obj := map[string]interface{}{
+		"fromBlock": encodeQuantity(q.FromBlock),
+		"toBlock":   encodeQuantity(q.ToBlock),
+		"topics":    topics,
+	}
+	if len(q.Addresses) > 0 {
+		obj["address"] = q.Addresses
+	}
+	return json.Marshal(obj)
+}
+
+// Log is an event emitted by a contract
+type Log struct {
+	Address     Address
+	Topics      []Hash
+	Data        []byte
+	BlockNumber uint64
+	TxHash      Hash
+	LogIndex    uint64
+	Removed     bool
+}
+
+// UnmarshalJSON decodes a log object returned by eth_getLogs
+func (l *Log) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Address     Address `json:"address"`
+		Topics      []Hash  `json:"topics"`
+		Data        string  `json:"data"`
+		BlockNumber string  `json:"blockNumber"`
+		TxHash      Hash    `json:"transactionHash"`
+		LogIndex    string  `json:"logIndex"`
+		Removed     bool    `json:"removed"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var err error
+	*l = Log{Address: raw.Address, Topics: raw.Topics, TxHash: raw.TxHash, Removed: raw.Removed}
+	if l.Data, err = decodeHex(raw.Data); err != nil {
+		return fmt.Errorf("log data: %w", err)
+	}
+	if l.BlockNumber, err = decodeQuantity(raw.BlockNumber); err != nil {
+		return fmt.Errorf("log block number: %w", err)
+	}
+	if l.LogIndex, err = decodeQuantity(raw.LogIndex); err != nil {
+		return fmt.Errorf("log index: %w", err)
+	}
+	return nil
+}
+
+// GetLogs returns the logs matching q
+func (c *Client) GetLogs(ctx context.Context, q FilterQuery) ([]Log, error) {
+	var logs []Log
+	if err := c.call(ctx, &logs, "eth_getLogs", q); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
@@ -0,0 +1,160 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrNotContract is returned when a call to an address produces no output,
+// which means there is no contract there
+var ErrNotContract = errors.New("no contract at address")
+
+// TransferTopic identifies ERC-20 Transfer(from, to, value) events
+var TransferTopic = EventTopic("Transfer(address,address,uint256)")
+
+// TokenInfo holds the ERC-20 metadata of a token contract
+type TokenInfo struct {
+	Address     Address
+	Name        string
+	Symbol      string
+	Decimals    uint8
+	TotalSupply *big.Int
+}
+
+// TokenInfo reads the name, symbol, decimals and total supply of token at block
+func (c *Client) TokenInfo(ctx context.Context, token Address, block uint64) (*TokenInfo, error) {
+	info := &TokenInfo{Address: token}
+
+	out, err := c.callView(ctx, token, block, EncodeCall("name()"))
+	if err != nil {
+		return nil, err
+	}
+	if info.Name, err = DecodeString(out); err != nil {
+		return nil, fmt.Errorf("name(): %w", err)
+	}
+
+	if out, err = c.callView(ctx, token, block, EncodeCall("symbol()")); err != nil {
+		return nil, err
+	}
+	if info.Symbol, err = DecodeString(out); err != nil {
+		return nil, fmt.Errorf("symbol(): %w", err)
+	}
+
+	if out, err = c.callView(ctx, token, block, EncodeCall("decimals()")); err != nil {
+		return nil, err
+	}
+	if info.Decimals, err = DecodeUint8(out); err != nil {
+		return nil, fmt.Errorf("decimals(): %w", err)
+	}
+
+	if out, err = c.callView(ctx, token, block, EncodeCall("totalSupply()")); err != nil {
+		return nil, err
+	}
+	if info.TotalSupply, err = DecodeUint256(out); err != nil {
+		return nil, fmt.Errorf("totalSupply(): %w", err)
+	}
+
+	return info, nil
+}
+
+// BalanceOf reads the token balance of owner at block
+func (c *Client) BalanceOf(ctx context.Context, token, owner Address, block uint64) (*big.Int, error) {
+	out, err := c.callView(ctx, token, block, EncodeCall("balanceOf(address)", EncodeAddress(owner)))
+	if err != nil {
+		return nil, err
+	}
+	balance, err := DecodeUint256(out)
+	if err != nil {
+		return nil, fmt.Errorf("balanceOf(address): %w", err)
+	}
+	return balance, nil
+}
+
+// callView performs an eth_call, reporting empty output as ErrNotContract
+func (c *Client) callView(ctx context.Context, to Address, block uint64, data []byte) ([]byte, error) {
+	out, err := c.Call(ctx, CallMsg{To: to, Data: data}, block)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%w %s", ErrNotContract, to.Hex())
+	}
+	return out, nil
+}
+
+// Transfer is a decoded ERC-20 Transfer event
+type Transfer struct {
+	Token       Address
+	From        Address
+	To          Address
+	Value       *big.Int
+	BlockNumber uint64
+	TxHash      Hash
+	LogIndex    uint64
+}
+
+// DecodeTransfer decodes an ERC-20 Transfer log
+func DecodeTransfer(l Log) (*Transfer, error) {
+	if len(l.Topics) != 3 || l.Topics[0] != TransferTopic {
+		return nil, fmt.Errorf("%w: not an ERC-20 Transfer log", ErrABI)
+	}
+	value, err := DecodeUint256(l.Data)
+	if err != nil {
+		return nil, err
+	}
+	from, _ := DecodeAddress(l.Topics[1][:])
+	to, _ := DecodeAddress(l.Topics[2][:])
+	return &Transfer{
+		Token:       l.Address,
+		From:        from,
+		To:          to,
+		Value:       value,
+		BlockNumber: l.BlockNumber,
+		TxHash:      l.TxHash,
+		LogIndex:    l.LogIndex,
+	}, nil
+}
+
+// FormatUnits renders an amount of base units as a decimal string with
+// the given number of decimals, without trailing zeros
+func FormatUnits(amount *big.Int, decimals uint8) string {
+	if amount == nil {
+		return "0"
+	}
+	if decimals == 0 {
+		return amount.String()
+	}
+
+	abs := new(big.Int).Abs(amount)
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	whole, frac := new(big.Int).QuoRem(abs, unit, new(big.Int))
+
+	s := whole.String()
+	if frac.Sign() != 0 {
+		digits := fmt.Sprintf("%0*s", int(decimals), frac.String())
+		s += "." + strings.TrimRight(digits, "0")
+	}
+	if amount.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// ParseUnits converts a decimal amount such as "1,000.25" into base units
+// of the given number of decimals. Commas are read as thousands separators.
+func ParseUnits(s string, decimals uint8) (*big.Int, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" || strings.Trim(whole+frac, "0123456789") != "" {
+		return nil, fmt.Errorf("%q is not a decimal number", s)
+	}
+	if len(frac) > int(decimals) {
+		return nil, fmt.Errorf("%q has more than %d decimal places", s, decimals)
+	}
+
+	n, _ := new(big.Int).SetString(whole+frac+strings.Repeat("0", int(decimals)-len(frac)), 10)
+	return n, nil
+}
@@ -0,0 +1,294 @@
+package evm_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/bitcoin-ai-platform/pkg/evm"
+	"github.com/yourusername/bitcoin-ai-platform/pkg/evm/evmtest"
+)
+
+func mustAddress(t *testing.T, s string) evm.Address {
+	t.Helper()
+	a, err := evm.ParseAddress(s)
+	if err != nil {
+		t.Fatalf("ParseAddress(%q): %v", s, err)
+	}
+	return a
+}
+
+func TestAddressChecksum(t *testing.T) {
+	// Test vectors from EIP-55
+	for _, want := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		if got := mustAddress(t, want).Hex(); got != want {
+			t.Errorf("Hex() = %s, want %s", got, want)
+		}
+	}
+
+	for _, bad := range []string{"", "5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "0x1234", "0xZZAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"} {
+		if _, err := evm.ParseAddress(bad); !errors.Is(err, evm.ErrInvalidAddress) {
+			t.Errorf("ParseAddress(%q) = %v, want ErrInvalidAddress", bad, err)
+		}
+	}
+}
+
+func TestSelector(t *testing.T) {
+	tests := map[string]string{
+		"balanceOf(address)": "70a08231",
+		"totalSupply()":      "18160ddd",
+		"decimals()":         "313ce567",
+	}
+	for signature, want := range tests {
+		if got := hex.EncodeToString(evm.Selector(signature)); got != want {
+			t.Errorf("Selector(%s) = %s, want %s", signature, got, want)
+		}
+	}
+	if got := evm.TransferTopic.Hex(); got != "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef" {
+		t.Errorf("TransferTopic = %s", got)
+	}
+}
+
+func TestDecodeString(t *testing.T) {
+	offset, _ := evm.EncodeUint256(big.NewInt(32))
+	dynamic := append(offset, evm.EncodeString("exSat Token")...)
+	if got, err := evm.DecodeString(dynamic); err != nil || got != "exSat Token" {
+		t.Errorf("DecodeString(dynamic) = %q, %v", got, err)
+	}
+
+	bytes32 := make([]byte, 32)
+	copy(bytes32, "MKR")
+	if got, err := evm.DecodeString(bytes32); err != nil || got != "MKR" {
+		t.Errorf("DecodeString(bytes32) = %q, %v", got, err)
+	}
+
+	truncated := append(offset, 0, 0)
+	if _, err := evm.DecodeString(truncated); !errors.Is(err, evm.ErrABI) {
+		t.Errorf("DecodeString(truncated) = %v, want ErrABI", err)
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	tests := []struct {
+		amount   string
+		decimals uint8
+		want     string
+	}{
+		{"0", 18, "0"},
+		{"1000000000000000000", 18, "1"},
+		{"1500000000000000000", 18, "1.5"},
+		{"1", 18, "0.000000000000000001"},
+		{"12345", 0, "12345"},
+		{"-250", 2, "-2.5"},
+	}
+	for _, tt := range tests {
+		amount, _ := new(big.Int).SetString(tt.amount, 10)
+		if got := evm.FormatUnits(amount, tt.decimals); got != tt.want {
+			t.Errorf("FormatUnits(%s, %d) = %s, want %s", tt.amount, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	tests := []struct {
+		amount   string
+		decimals uint8
+		want     string // empty when invalid
+	}{
+		{"1", 18, "1000000000000000000"},
+		{"1,000.5", 18, "1000500000000000000000"},
+		{".25", 2, "25"},
+		{"12345", 0, "12345"},
+		{"0.000000000000000001", 18, "1"},
+		{"1.5", 0, ""},
+		{"1.234", 2, ""},
+		{"-1", 18, ""},
+		{"1e18", 18, ""},
+		{".", 18, ""},
+		{"", 18, ""},
+	}
+	for _, tt := range tests {
+		got, err := evm.ParseUnits(tt.amount, tt.decimals)
+		switch {
+		case tt.want == "" && err == nil:
+			t.Errorf("ParseUnits(%q, %d) = %s, want an error", tt.amount, tt.decimals, got)
+		case tt.want != "" && (err != nil || got.String() != tt.want):
+			t.Errorf("ParseUnits(%q, %d) = %v, %v, want %s", tt.amount, tt.decimals, got, err, tt.want)
+		}
+	}
+}
+
+func TestClientReadsTokens(t *testing.T) {
+	node := evmtest.NewServer(t)
+	token := mustAddress(t, "0x8266f2fbc720012e5Ac038aD3dbb29d2d613c459")
+	holder := mustAddress(t, "0x742d35Cc6634C0532925a3b844Bc454e4438f44e")
+	node.AddToken(token, "exSat Token", "XSAT", 18)
+	node.Mint(token, holder, big.NewInt(5000))
+
+	c := evm.NewClient(node.URL)
+	ctx := context.Background()
+
+	block, err := c.BlockNumber(ctx)
+	if err != nil || block != 2 {
+		t.Fatalf("BlockNumber = %d, %v, want 2", block, err)
+	}
+
+	info, err := c.TokenInfo(ctx, token, block)
+	if err != nil {
+		t.Fatalf("TokenInfo: %v", err)
+	}
+	if info.Name != "exSat Token" || info.Symbol != "XSAT" || info.Decimals != 18 || info.TotalSupply.Int64() != 5000 {
+		t.Errorf("TokenInfo = %+v", info)
+	}
+
+	balance, err := c.BalanceOf(ctx, token, holder, block)
+	if err != nil || balance.Int64() != 5000 {
+		t.Errorf("BalanceOf = %v, %v, want 5000", balance, err)
+	}
+
+	if _, err := c.TokenInfo(ctx, holder, block); !errors.Is(err, evm.ErrNotContract) {
+		t.Errorf("TokenInfo of a wallet = %v, want ErrNotContract", err)
+	}
+
+	logs, err := c.GetLogs(ctx, evm.FilterQuery{
+		FromBlock: 0,
+		ToBlock:   block,
+		Addresses: []evm.Address{token},
+		Topics:    [][]evm.Hash{{evm.TransferTopic}},
+	})
+	if err != nil || len(logs) != 1 {
+		t.Fatalf("GetLogs = %d logs, %v, want 1", len(logs), err)
+	}
+	transfer, err := evm.DecodeTransfer(logs[0])
+	if err != nil {
+		t.Fatalf("DecodeTransfer: %v", err)
+	}
+	if !transfer.From.IsZero() || transfer.To != holder || transfer.Value.Int64() != 5000 || transfer.BlockNumber != 2 {
+		t.Errorf("transfer = %+v, want a mint of 5000 to the holder at block 2", transfer)
+	}
+}
+
+func TestClientReportsRPCErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"header not found"}}`))
+	}))
+	c := evm.NewClient(server.URL)
+
+	var rpcErr *evm.RPCError
+	_, err := c.BlockNumber(context.Background())
+	if !errors.As(err, &rpcErr) || !errors.Is(err, evm.ErrRPC) || rpcErr.Code != -32000 {
+		t.Errorf("error response = %v, want an RPCError -32000", err)
+	}
+
+	server.Close()
+	if _, err := c.BlockNumber(context.Background()); !errors.Is(err, evm.ErrRPC) {
+		t.Errorf("BlockNumber with node down = %v, want ErrRPC", err)
+	}
+}
+
+// devKey is the first account of the default anvil and hardhat devnets
+const devKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+func TestPrivateKey(t *testing.T) {
+	key, err := evm.ParsePrivateKey(devKey)
+	if err != nil {
+		t.Fatalf("ParsePrivateKey: %v", err)
+	}
+	if got := key.Address().Hex(); got != "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266" {
+		t.Errorf("Address() = %s", got)
+	}
+
+	hash := evm.Keccak256([]byte("FansMint"))
+	sig := key.Sign(hash)
+	if signer, err := evm.RecoverAddress(hash, sig); err != nil || signer != key.Address() {
+		t.Errorf("RecoverAddress = %s, %v, want the signing key", signer, err)
+	}
+	// Wallets report V as 27 or 28
+	sig[64] += 27
+	if signer, err := evm.RecoverAddress(hash, sig); err != nil || signer != key.Address() {
+		t.Errorf("RecoverAddress with V+27 = %s, %v", signer, err)
+	}
+
+	for _, bad := range []string{"", "0x1234", "0x" + strings.Repeat("00", 32), "0x" + strings.Repeat("ff", 32)} {
+		if _, err := evm.ParsePrivateKey(bad); !errors.Is(err, evm.ErrInvalidKey) {
+			t.Errorf("ParsePrivateKey(%q) = %v, want ErrInvalidKey", bad, err)
+		}
+	}
+}
+
+func TestTransactionRoundTrip(t *testing.T) {
+	key, _ := evm.ParsePrivateKey(devKey)
+	factory := mustAddress(t, "0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	data, err := evm.EncodeCreateToken(evm.CreateTokenParams{
+		Name:          "Fan Token",
+		Symbol:        "FAN",
+		Decimals:      18,
+		InitialSupply: new(big.Int).Exp(big.NewInt(10), big.NewInt(24), nil),
+		Owner:         key.Address(),
+		MetadataURI:   "https://fansmint.example/api/v1/assets/42",
+	})
+	if err != nil {
+		t.Fatalf("EncodeCreateToken: %v", err)
+	}
+
+	tx := &evm.Transaction{
+		ChainID:   big.NewInt(31337),
+		Nonce:     7,
+		GasTipCap: big.NewInt(1_000_000_000),
+		GasFeeCap: big.NewInt(3_000_000_000),
+		Gas:       1_500_000,
+		To:        &factory,
+		Value:     new(big.Int),
+		Data:      data,
+	}
+	tx.Sign(key)
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	if raw[0] != evm.DynamicFeeTxType {
+		t.Errorf("type byte = %#x, want 0x02", raw[0])
+	}
+
+	decoded, err := evm.DecodeTransaction(raw)
+	if err != nil {
+		t.Fatalf("DecodeTransaction: %v", err)
+	}
+	if decoded.Nonce != 7 || decoded.Gas != 1_500_000 || *decoded.To != factory || decoded.ChainID.Int64() != 31337 || !bytes.Equal(decoded.Data, data) {
+		t.Errorf("decoded = %+v", decoded)
+	}
+	if sender, err := decoded.Sender(); err != nil || sender != key.Address() {
+		t.Errorf("Sender = %s, %v, want the signing key", sender, err)
+	}
+
+	params, err := evm.DecodeCreateToken(decoded.Data)
+	if err != nil {
+		t.Fatalf("DecodeCreateToken: %v", err)
+	}
+	if params.Name != "Fan Token" || params.Symbol != "FAN" || params.Decimals != 18 || params.Owner != key.Address() || params.MetadataURI != "https://fansmint.example/api/v1/assets/42" {
+		t.Errorf("createToken params = %+v", params)
+	}
+
+	// Any change to the signed fields changes the recovered sender
+	decoded.Nonce++
+	if sender, _ := decoded.Sender(); sender == key.Address() {
+		t.Error("tampered transaction still recovers the signer")
+	}
+
+	for _, bad := range [][]byte{nil, {0x01, 0xc0}, append([]byte{evm.DynamicFeeTxType}, 0xc1), raw[:len(raw)-1]} {
+		if _, err := evm.DecodeTransaction(bad); !errors.Is(err, evm.ErrInvalidTransaction) {
+			t.Errorf("DecodeTransaction(%x) = %v, want ErrInvalidTransaction", bad, err)
+		}
+	}
+}
@@ -0,0 +1,536 @@
+// Package evmtest provides an in-memory EVM JSON-RPC node for tests. It
+// serves ERC-20 tokens and their Transfer logs and accepts EIP-1559
+// transactions calling the FansMint factory, enough to exercise pkg/evm and
+// the services built on it without a devnet.
+package evmtest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourusername/bitcoin-ai-platform/pkg/evm"
+)
+
+// ChainID is the chain ID of the stub node, that of the exSat testnet
+const ChainID = 839999
+
+// Fees of the stub node, in wei
+const (
+	BaseFee  = 1_000_000_000
+	GasPrice = 2_000_000_000
+)
+
+// Block timestamps: block n is mined at GenesisTime + n*BlockInterval seconds
+const (
+	GenesisTime   = 1_700_000_000
+	BlockInterval = 12
+)
+
+// BlockTime returns the time block n is mined at
+func BlockTime(n uint64) time.Time {
+	return time.Unix(GenesisTime+int64(n)*BlockInterval, 0)
+}
+
+// Gas reported by eth_estimateGas for factory calls and anything else
+const (
+	CreateTokenGas = 1_500_000
+	TransferGas    = 21_000
+)
+
+// Server is a stub EVM node. Every Transfer, Mint and transaction is mined
+// in its own block.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	block     uint64
+	tokens    map[evm.Address]*token
+	logs      []rpcLog
+	calls     map[string]int
+	factories map[evm.Address]bool
+	nonces    map[evm.Address]uint64
+	receipts  map[evm.Hash]*rpcReceipt
+	txs       map[evm.Hash]*rpcTransaction
+	sent      []*evm.Transaction
+	reverting bool
+	dropping  bool
+}
+
+type token struct {
+	name, symbol string
+	decimals     uint8
+	supply       *big.Int
+	balances     map[evm.Address]*big.Int
+}
+
+// rpcLog is a log in its JSON-RPC form
+type rpcLog struct {
+	Address     string   `json:"address"`
+	Topics      []string `json:"topics"`
+	Data        string   `json:"data"`
+	BlockNumber string   `json:"blockNumber"`
+	TxHash      string   `json:"transactionHash"`
+	LogIndex    string   `json:"logIndex"`
+	Removed     bool     `json:"removed"`
+
+	block uint64
+}
+
+// rpcReceipt is a receipt in its JSON-RPC form
+type rpcReceipt struct {
+	TxHash          string   `json:"transactionHash"`
+	Status          string   `json:"status"`
+	BlockNumber     string   `json:"blockNumber"`
+	GasUsed         string   `json:"gasUsed"`
+	ContractAddress *string  `json:"contractAddress"`
+	Logs            []rpcLog `json:"logs"`
+}
+
+// rpcTransaction is a transaction in its JSON-RPC form
+type rpcTransaction struct {
+	Hash        string       `json:"hash"`
+	From        evm.Address  `json:"from"`
+	To          *evm.Address `json:"to"`
+	Nonce       string       `json:"nonce"`
+	Input       string       `json:"input"`
+	Value       string       `json:"value"`
+	BlockNumber string       `json:"blockNumber"`
+}
+
+// NewServer starts a stub node at block 1, closed when the test ends
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{
+		block:     1,
+		tokens:    make(map[evm.Address]*token),
+		calls:     make(map[string]int),
+		factories: make(map[evm.Address]bool),
+		nonces:    make(map[evm.Address]uint64),
+		receipts:  make(map[evm.Hash]*rpcReceipt),
+		txs:       make(map[evm.Hash]*rpcTransaction),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// AddToken deploys an ERC-20 token with no supply at address
+func (s *Server) AddToken(address evm.Address, name, symbol string, decimals uint8) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addToken(address, name, symbol, decimals)
+}
+
+// AddFactory deploys the FansMint factory at address
+func (s *Server) AddFactory(address evm.Address) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.factories[address] = true
+}
+
+// RevertTransactions makes the transactions mined from now on revert, or
+// succeed again
+func (s *Server) RevertTransactions(revert bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reverting = revert
+}
+
+// DropTransactions makes the transactions received from now on accepted
+// but never mined, as when a node evicts them, or mined again
+func (s *Server) DropTransactions(drop bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dropping = drop
+}
+
+// Transactions returns the transactions received so far
+func (s *Server) Transactions() []*evm.Transaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*evm.Transaction(nil), s.sent...)
+}
+
+func (s *Server) addToken(address evm.Address, name, symbol string, decimals uint8) {
+	s.tokens[address] = &token{
+		name:     name,
+		symbol:   symbol,
+		decimals: decimals,
+		supply:   new(big.Int),
+		balances: make(map[evm.Address]*big.Int),
+	}
+}
+
+// Mint creates amount tokens for to, emitting a Transfer from the zero address
+func (s *Server) Mint(address, to evm.Address, amount *big.Int) {
+	s.Transfer(address, evm.Address{}, to, amount)
+}
+
+// Transfer moves amount tokens and mines a block holding the Transfer log.
+// Transfers from or to the zero address mint or burn.
+func (s *Server) Transfer(address, from, to evm.Address, amount *big.Int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.block++
+	txHash := evm.Keccak256([]byte(strconv.Itoa(len(s.logs))), address[:])
+	s.transfer(address, from, to, amount, txHash, 0)
+}
+
+// transfer moves amount tokens in the current block, with s.mu held
+func (s *Server) transfer(address, from, to evm.Address, amount *big.Int, txHash evm.Hash, logIndex uint64) rpcLog {
+	tok := s.tokens[address]
+	if from.IsZero() {
+		tok.supply.Add(tok.supply, amount)
+	} else {
+		tok.balances[from] = new(big.Int).Sub(tok.balance(from), amount)
+	}
+	if to.IsZero() {
+		tok.supply.Sub(tok.supply, amount)
+	} else {
+		tok.balances[to] = new(big.Int).Add(tok.balance(to), amount)
+	}
+
+	value, _ := evm.EncodeUint256(amount)
+	return s.emit(address, []string{evm.TransferTopic.Hex(), wordHex(from), wordHex(to)}, value, txHash, logIndex)
+}
+
+// emit records a log in the current block, with s.mu held
+func (s *Server) emit(address evm.Address, topics []string, data []byte, txHash evm.Hash, logIndex uint64) rpcLog {
+	l := rpcLog{
+		Address:     strings.ToLower(address.Hex()),
+		Topics:      topics,
+		Data:        "0x" + hex.EncodeToString(data),
+		BlockNumber: quantity(s.block),
+		TxHash:      txHash.Hex(),
+		LogIndex:    quantity(logIndex),
+		block:       s.block,
+	}
+	s.logs = append(s.logs, l)
+	return l
+}
+
+// MineBlocks advances the chain by n empty blocks
+func (s *Server) MineBlocks(n uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.block += n
+}
+
+// BlockNumber returns the current block
+func (s *Server) BlockNumber() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.block
+}
+
+// Calls returns the number of requests served for a JSON-RPC method
+func (s *Server) Calls(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+func (t *token) balance(owner evm.Address) *big.Int {
+	if b, ok := t.balances[owner]; ok {
+		return b
+	}
+	return new(big.Int)
+}
+
+type rpcRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	var req rpcRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.calls[req.Method]++
+	result, rpcErr := s.handle(req)
+	s.mu.Unlock()
+
+	response := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	if rpcErr != nil {
+		response["error"] = rpcErr
+	} else {
+		response["result"] = result
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// handle answers a request, with s.mu held
+func (s *Server) handle(req rpcRequest) (interface{}, *evm.RPCError) {
+	switch req.Method {
+	case "eth_blockNumber":
+		return quantity(s.block), nil
+	case "eth_call":
+		var msg struct {
+			To   evm.Address `json:"to"`
+			Data string      `json:"data"`
+		}
+		if len(req.Params) == 0 || json.Unmarshal(req.Params[0], &msg) != nil {
+			return nil, &evm.RPCError{Code: -32602, Message: "invalid call object"}
+		}
+		data, err := hex.DecodeString(strings.TrimPrefix(msg.Data, "0x"))
+		if err != nil || len(data) < 4 {
+			return nil, &evm.RPCError{Code: -32602, Message: "invalid call data"}
+		}
+		return "0x" + hex.EncodeToString(s.call(msg.To, data)), nil
+	case "eth_chainId":
+		return quantity(ChainID), nil
+	case "eth_gasPrice":
+		return quantity(GasPrice), nil
+	case "eth_getBlockByNumber":
+		block := s.block
+		var tag string
+		if len(req.Params) > 0 && json.Unmarshal(req.Params[0], &tag) == nil {
+			block = parseQuantity(tag, s.block)
+		}
+		if block > s.block {
+			return nil, nil
+		}
+		return map[string]string{
+			"number":        quantity(block),
+			"timestamp":     quantity(uint64(BlockTime(block).Unix())),
+			"baseFeePerGas": quantity(BaseFee),
+		}, nil
+	case "eth_getTransactionCount":
+		var account evm.Address
+		if len(req.Params) == 0 || json.Unmarshal(req.Params[0], &account) != nil {
+			return nil, &evm.RPCError{Code: -32602, Message: "invalid account"}
+		}
+		return quantity(s.nonces[account]), nil
+	case "eth_estimateGas":
+		var msg struct {
+			To   evm.Address `json:"to"`
+			Data string      `json:"data"`
+		}
+		if len(req.Params) == 0 || json.Unmarshal(req.Params[0], &msg) != nil {
+			return nil, &evm.RPCError{Code: -32602, Message: "invalid call object"}
+		}
+		if s.factories[msg.To] {
+			return quantity(CreateTokenGas), nil
+		}
+		return quantity(TransferGas), nil
+	case "eth_sendRawTransaction":
+		var raw string
+		if len(req.Params) == 0 || json.Unmarshal(req.Params[0], &raw) != nil {
+			return nil, &evm.RPCError{Code: -32602, Message: "invalid raw transaction"}
+		}
+		data, err := hex.DecodeString(strings.TrimPrefix(raw, "0x"))
+		if err != nil {
+			return nil, &evm.RPCError{Code: -32602, Message: "invalid raw transaction"}
+		}
+		return s.sendTransaction(data)
+	case "eth_getTransactionReceipt":
+		var hash evm.Hash
+		if len(req.Params) == 0 || json.Unmarshal(req.Params[0], &hash) != nil {
+			return nil, &evm.RPCError{Code: -32602, Message: "invalid transaction hash"}
+		}
+		if receipt, ok := s.receipts[hash]; ok {
+			return receipt, nil
+		}
+		return nil, nil
+	case "eth_getTransactionByHash":
+		var hash evm.Hash
+		if len(req.Params) == 0 || json.Unmarshal(req.Params[0], &hash) != nil {
+			return nil, &evm.RPCError{Code: -32602, Message: "invalid transaction hash"}
+		}
+		if tx, ok := s.txs[hash]; ok {
+			return tx, nil
+		}
+		return nil, nil
+	case "eth_getLogs":
+		var filter struct {
+			FromBlock string          `json:"fromBlock"`
+			ToBlock   string          `json:"toBlock"`
+			Address   json.RawMessage `json:"address"`
+			Topics    []interface{}   `json:"topics"`
+		}
+		if len(req.Params) == 0 || json.Unmarshal(req.Params[0], &filter) != nil {
+			return nil, &evm.RPCError{Code: -32602, Message: "invalid filter object"}
+		}
+		return s.filterLogs(filter.FromBlock, filter.ToBlock, filter.Address), nil
+	default:
+		return nil, &evm.RPCError{Code: -32601, Message: "method not found: " + req.Method}
+	}
+}
+
+// call executes an ERC-20 view; unknown contracts return no data like an EOA
+func (s *Server) call(to evm.Address, data []byte) []byte {
+	tok, ok := s.tokens[to]
+	if !ok {
+		return nil
+	}
+
+	selector := hex.EncodeToString(data[:4])
+	switch selector {
+	case hex.EncodeToString(evm.Selector("name()")):
+		return abiString(tok.name)
+	case hex.EncodeToString(evm.Selector("symbol()")):
+		return abiString(tok.symbol)
+	case hex.EncodeToString(evm.Selector("decimals()")):
+		word, _ := evm.EncodeUint256(big.NewInt(int64(tok.decimals)))
+		return word
+	case hex.EncodeToString(evm.Selector("totalSupply()")):
+		word, _ := evm.EncodeUint256(tok.supply)
+		return word
+	case hex.EncodeToString(evm.Selector("balanceOf(address)")):
+		owner, err := evm.DecodeAddress(data[4:])
+		if err != nil {
+			return nil
+		}
+		word, _ := evm.EncodeUint256(tok.balance(owner))
+		return word
+	}
+	return nil
+}
+
+// sendTransaction validates and mines a raw transaction in a new block,
+// with s.mu held. Calls to a factory create a token minted to its owner.
+func (s *Server) sendTransaction(raw []byte) (interface{}, *evm.RPCError) {
+	tx, err := evm.DecodeTransaction(raw)
+	if err != nil {
+		return nil, &evm.RPCError{Code: -32000, Message: err.Error()}
+	}
+	if tx.ChainID.Cmp(big.NewInt(ChainID)) != 0 {
+		return nil, &evm.RPCError{Code: -32000, Message: "invalid chain id"}
+	}
+	sender, err := tx.Sender()
+	if err != nil {
+		return nil, &evm.RPCError{Code: -32000, Message: "invalid sender: " + err.Error()}
+	}
+	switch nonce := s.nonces[sender]; {
+	case tx.Nonce < nonce:
+		return nil, &evm.RPCError{Code: -32000, Message: "nonce too low"}
+	case tx.Nonce > nonce:
+		return nil, &evm.RPCError{Code: -32000, Message: "nonce too high"}
+	}
+	if tx.GasFeeCap.Cmp(big.NewInt(BaseFee)) < 0 {
+		return nil, &evm.RPCError{Code: -32000, Message: "max fee per gas less than block base fee"}
+	}
+
+	hash := evm.Keccak256(raw)
+	s.sent = append(s.sent, tx)
+	if s.dropping {
+		return hash.Hex(), nil
+	}
+	s.nonces[sender]++
+	s.block++
+	receipt := &rpcReceipt{
+		TxHash:      hash.Hex(),
+		Status:      "0x1",
+		BlockNumber: quantity(s.block),
+		GasUsed:     quantity(TransferGas),
+		Logs:        []rpcLog{},
+	}
+	s.receipts[hash] = receipt
+	s.txs[hash] = &rpcTransaction{
+		Hash:        hash.Hex(),
+		From:        sender,
+		To:          tx.To,
+		Nonce:       quantity(tx.Nonce),
+		Input:       "0x" + hex.EncodeToString(tx.Data),
+		Value:       "0x" + tx.Value.Text(16),
+		BlockNumber: quantity(s.block),
+	}
+
+	if s.reverting {
+		receipt.Status = "0x0"
+		return hash.Hex(), nil
+	}
+	if tx.To != nil && s.factories[*tx.To] {
+		params, err := evm.DecodeCreateToken(tx.Data)
+		if err != nil {
+			receipt.Status = "0x0"
+			return hash.Hex(), nil
+		}
+		receipt.GasUsed = quantity(CreateTokenGas)
+
+		// Token addresses derive from the factory and a counter, like CREATE
+		created := evm.Keccak256(tx.To[:], []byte(strconv.Itoa(len(s.tokens))))
+		var address evm.Address
+		copy(address[:], created[12:])
+		s.addToken(address, params.Name, params.Symbol, params.Decimals)
+		receipt.Logs = append(receipt.Logs,
+			s.transfer(address, evm.Address{}, params.Owner, params.InitialSupply, hash, 0),
+			s.emit(*tx.To, []string{evm.TokenCreatedTopic.Hex(), wordHex(address), wordHex(params.Owner)}, nil, hash, 1),
+		)
+	}
+	return hash.Hex(), nil
+}
+
+// filterLogs returns the logs within the block range and addresses
+func (s *Server) filterLogs(fromBlock, toBlock string, address json.RawMessage) []rpcLog {
+	from := parseQuantity(fromBlock, 0)
+	to := parseQuantity(toBlock, s.block)
+
+	var addresses []string
+	if len(address) > 0 && string(address) != "null" {
+		if json.Unmarshal(address, &addresses) != nil {
+			var single string
+			json.Unmarshal(address, &single)
+			addresses = []string{single}
+		}
+	}
+
+	logs := []rpcLog{}
+	for _, l := range s.logs {
+		if l.block < from || l.block > to {
+			continue
+		}
+		if len(addresses) > 0 && !containsFold(addresses, l.Address) {
+			continue
+		}
+		logs = append(logs, l)
+	}
+	return logs
+}
+
+// abiString returns the ABI encoding of a string return value
+func abiString(s string) []byte {
+	offset, _ := evm.EncodeUint256(big.NewInt(32))
+	return append(offset, evm.EncodeString(s)...)
+}
+
+func wordHex(a evm.Address) string {
+	return "0x" + hex.EncodeToString(evm.EncodeAddress(a))
+}
+
+func quantity(n uint64) string {
+	return "0x" + strconv.FormatUint(n, 16)
+}
+
+func parseQuantity(s string, fallback uint64) uint64 {
+	n, err := strconv.ParseUint(strings.TrimPrefix(s, "0x"), 16, 64)
+	if err != nil {
+		return fallback
+	}
+	return n
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
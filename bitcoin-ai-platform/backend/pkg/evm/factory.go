@@ -0,0 +1,97 @@
+package evm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// The FansMint factory deploys one ERC-20 contract per fan token, minting
+// the initial supply to the owner. Its Solidity interface is:
+//
+//	function createToken(string name, string symbol, uint8 decimals,
+//	    uint256 initialSupply, address owner, string metadataURI)
+//	    returns (address token);
+//	event TokenCreated(address indexed token, address indexed owner);
+const CreateTokenSignature = "createToken(string,string,uint8,uint256,address,string)"
+
+// TokenCreatedTopic identifies factory TokenCreated(token, owner) events
+var TokenCreatedTopic = EventTopic("TokenCreated(address,address)")
+
+// ErrNoTokenCreated is returned when a receipt holds no TokenCreated event of the factory
+var ErrNoTokenCreated = errors.New("no TokenCreated event in receipt")
+
+// CreateTokenParams are the arguments of a factory createToken call
+type CreateTokenParams struct {
+	Name          string
+	Symbol        string
+	Decimals      uint8
+	InitialSupply *big.Int // base units
+	Owner         Address
+	MetadataURI   string
+}
+
+// EncodeCreateToken returns the call data of a factory createToken call
+func EncodeCreateToken(p CreateTokenParams) ([]byte, error) {
+	supply, err := EncodeUint256(p.InitialSupply)
+	if err != nil {
+		return nil, err
+	}
+	decimals, _ := EncodeUint256(big.NewInt(int64(p.Decimals)))
+	args := EncodeArgs(
+		Tail(EncodeString(p.Name)),
+		Tail(EncodeString(p.Symbol)),
+		decimals,
+		supply,
+		EncodeAddress(p.Owner),
+		Tail(EncodeString(p.MetadataURI)),
+	)
+	return append(Selector(CreateTokenSignature), args...), nil
+}
+
+// DecodeCreateToken decodes the call data of a factory createToken call
+func DecodeCreateToken(data []byte) (*CreateTokenParams, error) {
+	if len(data) < 4 || !bytes.Equal(data[:4], Selector(CreateTokenSignature)) {
+		return nil, fmt.Errorf("%w: not a createToken call", ErrABI)
+	}
+	args := data[4:]
+	if len(args) < 6*wordSize {
+		return nil, fmt.Errorf("%w: createToken arguments are %d bytes", ErrABI, len(args))
+	}
+
+	var p CreateTokenParams
+	var err error
+	if p.Name, err = DecodeStringArg(args, 0); err != nil {
+		return nil, err
+	}
+	if p.Symbol, err = DecodeStringArg(args, 1); err != nil {
+		return nil, err
+	}
+	if p.Decimals, err = DecodeUint8(args[2*wordSize:]); err != nil {
+		return nil, err
+	}
+	if p.InitialSupply, err = DecodeUint256(args[3*wordSize:]); err != nil {
+		return nil, err
+	}
+	if p.Owner, err = DecodeAddress(args[4*wordSize:]); err != nil {
+		return nil, err
+	}
+	if p.MetadataURI, err = DecodeStringArg(args, 5); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// CreatedToken returns the token deployed by factory in a receipt
+func CreatedToken(receipt *Receipt, factory Address) (Address, error) {
+	for _, l := range receipt.Logs {
+		if l.Address != factory || len(l.Topics) != 3 || l.Topics[0] != TokenCreatedTopic {
+			continue
+		}
+		var token Address
+		copy(token[:], l.Topics[1][12:])
+		return token, nil
+	}
+	return Address{}, ErrNoTokenCreated
+}
@@ -0,0 +1,90 @@
+package evm
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// SignatureLength is the size of a recoverable signature: R, S and the
+// recovery ID V (0 or 1)
+const SignatureLength = 65
+
+// ErrInvalidKey is returned when a private key is not 32 bytes in range
+var ErrInvalidKey = errors.New("invalid secp256k1 private key")
+
+// ErrInvalidSignature is returned when no signer can be recovered from a signature
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// PrivateKey is a secp256k1 account key
+type PrivateKey struct {
+	key *secp256k1.PrivateKey
+}
+
+// ParsePrivateKey parses a 32 byte hex private key, with or without 0x
+func ParsePrivateKey(s string) (*PrivateKey, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X"))
+	if err != nil || len(b) != 32 {
+		return nil, fmt.Errorf("%w: want 64 hex digits", ErrInvalidKey)
+	}
+
+	var scalar secp256k1.ModNScalar
+	if overflow := scalar.SetByteSlice(b); overflow || scalar.IsZero() {
+		return nil, fmt.Errorf("%w: out of range", ErrInvalidKey)
+	}
+	return &PrivateKey{key: secp256k1.NewPrivateKey(&scalar)}, nil
+}
+
+// Address returns the account address of the key
+func (k *PrivateKey) Address() Address {
+	return pubkeyAddress(k.key.PubKey())
+}
+
+// Sign returns the recoverable signature of hash as R || S || V, with V 0 or 1
+func (k *PrivateKey) Sign(hash Hash) []byte {
+	// SignCompact produces V || R || S with V = 27 + recovery ID
+	compact := ecdsa.SignCompact(k.key, hash[:], false)
+	return append(compact[1:], compact[0]-27)
+}
+
+// RecoverAddress returns the address whose key produced sig over hash. V
+// may be given as 0/1 or as 27/28.
+func RecoverAddress(hash Hash, sig []byte) (Address, error) {
+	if len(sig) != SignatureLength {
+		return Address{}, fmt.Errorf("%w: %d bytes, want %d", ErrInvalidSignature, len(sig), SignatureLength)
+	}
+	v := sig[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return Address{}, fmt.Errorf("%w: recovery ID %d", ErrInvalidSignature, sig[64])
+	}
+
+	compact := append([]byte{27 + v}, sig[:64]...)
+	pub, _, err := ecdsa.RecoverCompact(compact, hash[:])
+	if err != nil {
+		return Address{}, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	return pubkeyAddress(pub), nil
+}
+
+// pubkeyAddress derives the account address of a public key: the last 20
+// bytes of the Keccak-256 of its uncompressed X || Y coordinates
+func pubkeyAddress(pub *secp256k1.PublicKey) Address {
+	h := Keccak256(pub.SerializeUncompressed()[1:])
+	var a Address
+	copy(a[:], h[12:])
+	return a
+}
+
+// TextHash returns the EIP-191 personal_sign hash of a message, the hash
+// wallets sign for personal_sign and eth_sign
+func TextHash(message []byte) Hash {
+	prefix := fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(message))
+	return Keccak256([]byte(prefix), message)
+}
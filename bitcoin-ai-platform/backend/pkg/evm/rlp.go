@@ -0,0 +1,159 @@
+package evm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// errRLP is wrapped by every RLP decoding failure
+var errRLP = errors.New("invalid RLP data")
+
+// rlpList is an RLP list of items, each a []byte or a nested rlpList
+type rlpList []interface{}
+
+// rlpEncode returns the RLP encoding of item, a []byte or an rlpList
+func rlpEncode(item interface{}) []byte {
+	switch v := item.(type) {
+	case []byte:
+		if len(v) == 1 && v[0] < 0x80 {
+			return []byte{v[0]}
+		}
+		return append(rlpHeader(0x80, len(v)), v...)
+	case rlpList:
+		var payload []byte
+		for _, elem := range v {
+			payload = append(payload, rlpEncode(elem)...)
+		}
+		return append(rlpHeader(0xc0, len(payload)), payload...)
+	default:
+		panic(fmt.Sprintf("rlp: unsupported item %T", item))
+	}
+}
+
+// rlpHeader returns the prefix of a string (0x80) or list (0xc0) payload
+func rlpHeader(offset byte, size int) []byte {
+	if size < 56 {
+		return []byte{offset + byte(size)}
+	}
+	length := new(big.Int).SetInt64(int64(size)).Bytes()
+	return append([]byte{offset + 55 + byte(len(length))}, length...)
+}
+
+// rlpUint returns the RLP string of n: big-endian without leading zeros
+func rlpUint(n uint64) []byte {
+	return new(big.Int).SetUint64(n).Bytes()
+}
+
+// rlpBig returns the RLP string of n, which must not be negative; nil is 0
+func rlpBig(n *big.Int) []byte {
+	if n == nil {
+		return []byte{}
+	}
+	return n.Bytes()
+}
+
+// rlpDecode decodes a single item filling all of data
+func rlpDecode(data []byte) (interface{}, error) {
+	item, rest, err := rlpSplit(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes", errRLP, len(rest))
+	}
+	return item, nil
+}
+
+// rlpSplit decodes the first item of data and returns the remaining bytes
+func rlpSplit(data []byte) (interface{}, []byte, error) {
+	if len(data) == 0 {
+		return nil, nil, fmt.Errorf("%w: unexpected end of data", errRLP)
+	}
+
+	prefix := data[0]
+	switch {
+	case prefix < 0x80:
+		return data[:1], data[1:], nil
+	case prefix < 0xc0:
+		payload, rest, err := rlpPayload(data, 0x80)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(payload) == 1 && payload[0] < 0x80 {
+			return nil, nil, fmt.Errorf("%w: non-canonical single byte", errRLP)
+		}
+		return payload, rest, nil
+	default:
+		payload, rest, err := rlpPayload(data, 0xc0)
+		if err != nil {
+			return nil, nil, err
+		}
+		list := rlpList{}
+		for len(payload) > 0 {
+			var elem interface{}
+			if elem, payload, err = rlpSplit(payload); err != nil {
+				return nil, nil, err
+			}
+			list = append(list, elem)
+		}
+		return list, rest, nil
+	}
+}
+
+// rlpPayload returns the payload of the string or list starting data
+func rlpPayload(data []byte, offset byte) ([]byte, []byte, error) {
+	header := int(data[0] - offset)
+	start, size := 1, header
+	if header > 55 {
+		lengthSize := header - 55
+		if len(data) < 1+lengthSize || data[1] == 0 {
+			return nil, nil, fmt.Errorf("%w: bad length prefix", errRLP)
+		}
+		length := new(big.Int).SetBytes(data[1 : 1+lengthSize])
+		if !length.IsInt64() || length.Int64() < 56 {
+			return nil, nil, fmt.Errorf("%w: bad length prefix", errRLP)
+		}
+		start, size = 1+lengthSize, int(length.Int64())
+	}
+	if size > len(data)-start {
+		return nil, nil, fmt.Errorf("%w: item exceeds data", errRLP)
+	}
+	return data[start : start+size], data[start+size:], nil
+}
+
+// rlpBytesAt returns item i of list as a string
+func rlpBytesAt(list rlpList, i int) ([]byte, error) {
+	b, ok := list[i].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("%w: item %d is a list", errRLP, i)
+	}
+	return b, nil
+}
+
+// rlpUintAt returns item i of list as a uint64
+func rlpUintAt(list rlpList, i int) (uint64, error) {
+	n, err := rlpBigAt(list, i)
+	if err != nil {
+		return 0, err
+	}
+	if !n.IsUint64() {
+		return 0, fmt.Errorf("%w: item %d overflows uint64", errRLP, i)
+	}
+	return n.Uint64(), nil
+}
+
+// rlpBigAt returns item i of list as a canonical unsigned integer
+func rlpBigAt(list rlpList, i int) (*big.Int, error) {
+	b, err := rlpBytesAt(list, i)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > 0 && b[0] == 0 {
+		return nil, fmt.Errorf("%w: item %d has leading zeros", errRLP, i)
+	}
+	if len(b) > 32 {
+		return nil, fmt.Errorf("%w: item %d overflows uint256", errRLP, i)
+	}
+	return new(big.Int).SetBytes(b), nil
+}
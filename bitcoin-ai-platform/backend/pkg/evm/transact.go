@@ -0,0 +1,237 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrNoReceipt is returned while a transaction is not mined, or unknown to the node
+var ErrNoReceipt = errors.New("transaction has no receipt")
+
+// ErrUnknownTransaction is returned when the node does not know a transaction hash
+var ErrUnknownTransaction = errors.New("unknown transaction")
+
+// Receipt statuses
+const (
+	ReceiptFailed  = 0
+	ReceiptSuccess = 1
+)
+
+// Receipt is the outcome of a mined transaction
+type Receipt struct {
+	TxHash          Hash
+	Status          uint64 // ReceiptSuccess, or ReceiptFailed when reverted
+	BlockNumber     uint64
+	GasUsed         uint64
+	ContractAddress *Address // set when the transaction created a contract
+	Logs            []Log
+}
+
+// UnmarshalJSON decodes a receipt object returned by eth_getTransactionReceipt
+func (r *Receipt) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		TxHash          Hash     `json:"transactionHash"`
+		Status          string   `json:"status"`
+		BlockNumber     string   `json:"blockNumber"`
+		GasUsed         string   `json:"gasUsed"`
+		ContractAddress *Address `json:"contractAddress"`
+		Logs            []Log    `json:"logs"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var err error
+	*r = Receipt{TxHash: raw.TxHash, ContractAddress: raw.ContractAddress, Logs: raw.Logs}
+	if r.Status, err = decodeQuantity(raw.Status); err != nil {
+		return fmt.Errorf("receipt status: %w", err)
+	}
+	if r.BlockNumber, err = decodeQuantity(raw.BlockNumber); err != nil {
+		return fmt.Errorf("receipt block number: %w", err)
+	}
+	if r.GasUsed, err = decodeQuantity(raw.GasUsed); err != nil {
+		return fmt.Errorf("receipt gas used: %w", err)
+	}
+	return nil
+}
+
+// TransactionInfo is a transaction as reported by eth_getTransactionByHash
+type TransactionInfo struct {
+	Hash        Hash
+	From        Address
+	To          *Address // nil for contract creations
+	Nonce       uint64
+	Data        []byte
+	Value       *big.Int
+	BlockNumber *uint64 // nil while pending
+}
+
+// UnmarshalJSON decodes a transaction object
+func (t *TransactionInfo) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Hash        Hash     `json:"hash"`
+		From        Address  `json:"from"`
+		To          *Address `json:"to"`
+		Nonce       string   `json:"nonce"`
+		Input       string   `json:"input"`
+		Value       string   `json:"value"`
+		BlockNumber *string  `json:"blockNumber"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var err error
+	*t = TransactionInfo{Hash: raw.Hash, From: raw.From, To: raw.To}
+	if t.Nonce, err = decodeQuantity(raw.Nonce); err != nil {
+		return fmt.Errorf("transaction nonce: %w", err)
+	}
+	if t.Data, err = decodeHex(raw.Input); err != nil {
+		return fmt.Errorf("transaction input: %w", err)
+	}
+	if t.Value, err = decodeBigQuantity(raw.Value); err != nil {
+		return fmt.Errorf("transaction value: %w", err)
+	}
+	if raw.BlockNumber != nil {
+		block, err := decodeQuantity(*raw.BlockNumber)
+		if err != nil {
+			return fmt.Errorf("transaction block number: %w", err)
+		}
+		t.BlockNumber = &block
+	}
+	return nil
+}
+
+// ChainID returns the EIP-155 chain ID of the node
+func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
+	return c.callBig(ctx, "eth_chainId")
+}
+
+// PendingNonceAt returns the next nonce of account, counting pending transactions
+func (c *Client) PendingNonceAt(ctx context.Context, account Address) (uint64, error) {
+	return c.nonceAt(ctx, account, "pending")
+}
+
+// NonceAt returns the number of transactions account had mined as of block
+func (c *Client) NonceAt(ctx context.Context, account Address, block uint64) (uint64, error) {
+	return c.nonceAt(ctx, account, encodeQuantity(block))
+}
+
+// nonceAt calls eth_getTransactionCount for the block tag
+func (c *Client) nonceAt(ctx context.Context, account Address, tag string) (uint64, error) {
+	var result string
+	if err := c.call(ctx, &result, "eth_getTransactionCount", account, tag); err != nil {
+		return 0, err
+	}
+	n, err := decodeQuantity(result)
+	if err != nil {
+		return 0, fmt.Errorf("%w: eth_getTransactionCount: %w", ErrRPC, err)
+	}
+	return n, nil
+}
+
+// SuggestFees returns the priority fee and fee cap of a transaction likely
+// to be included within a few blocks. Chains without a base fee get the
+// node's gas price as both.
+func (c *Client) SuggestFees(ctx context.Context) (tipCap, feeCap *big.Int, err error) {
+	gasPrice, err := c.callBig(ctx, "eth_gasPrice")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var head struct {
+		BaseFee string `json:"baseFeePerGas"`
+	}
+	if err := c.call(ctx, &head, "eth_getBlockByNumber", "latest", false); err != nil {
+		return nil, nil, err
+	}
+	if head.BaseFee == "" {
+		return gasPrice, new(big.Int).Set(gasPrice), nil
+	}
+	baseFee, err := decodeBigQuantity(head.BaseFee)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: eth_getBlockByNumber: %w", ErrRPC, err)
+	}
+
+	// eth_gasPrice is the base fee plus the suggested tip; the cap leaves
+	// room for the base fee to double before inclusion
+	tipCap = new(big.Int).Sub(gasPrice, baseFee)
+	if tipCap.Sign() < 0 {
+		tipCap.SetInt64(0)
+	}
+	feeCap = new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), tipCap)
+	return tipCap, feeCap, nil
+}
+
+// EstimateGas returns the gas msg would use if sent as a transaction
+func (c *Client) EstimateGas(ctx context.Context, msg CallMsg) (uint64, error) {
+	var result string
+	if err := c.call(ctx, &result, "eth_estimateGas", msg); err != nil {
+		return 0, err
+	}
+	n, err := decodeQuantity(result)
+	if err != nil {
+		return 0, fmt.Errorf("%w: eth_estimateGas: %w", ErrRPC, err)
+	}
+	return n, nil
+}
+
+// SendRawTransaction broadcasts a signed transaction and returns its hash
+func (c *Client) SendRawTransaction(ctx context.Context, raw []byte) (Hash, error) {
+	var hash Hash
+	if err := c.call(ctx, &hash, "eth_sendRawTransaction", encodeHex(raw)); err != nil {
+		return Hash{}, err
+	}
+	return hash, nil
+}
+
+// SendTransaction broadcasts a signed transaction
+func (c *Client) SendTransaction(ctx context.Context, tx *Transaction) (Hash, error) {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return Hash{}, err
+	}
+	return c.SendRawTransaction(ctx, raw)
+}
+
+// TransactionReceipt returns the receipt of a mined transaction, or
+// ErrNoReceipt while it is pending
+func (c *Client) TransactionReceipt(ctx context.Context, hash Hash) (*Receipt, error) {
+	var receipt *Receipt
+	if err := c.call(ctx, &receipt, "eth_getTransactionReceipt", hash); err != nil {
+		return nil, err
+	}
+	if receipt == nil {
+		return nil, ErrNoReceipt
+	}
+	return receipt, nil
+}
+
+// TransactionByHash returns a pending or mined transaction, or
+// ErrUnknownTransaction
+func (c *Client) TransactionByHash(ctx context.Context, hash Hash) (*TransactionInfo, error) {
+	var tx *TransactionInfo
+	if err := c.call(ctx, &tx, "eth_getTransactionByHash", hash); err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, ErrUnknownTransaction
+	}
+	return tx, nil
+}
+
+// callBig invokes a method without parameters returning a quantity
+func (c *Client) callBig(ctx context.Context, method string) (*big.Int, error) {
+	var result string
+	if err := c.call(ctx, &result, method); err != nil {
+		return nil, err
+	}
+	n, err := decodeBigQuantity(result)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrRPC, method, err)
+	}
+	return n, nil
+}
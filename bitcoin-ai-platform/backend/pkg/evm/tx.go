@@ -0,0 +1,175 @@
+package evm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// DynamicFeeTxType is the EIP-2718 type byte of EIP-1559 transactions
+const DynamicFeeTxType = 0x02
+
+// ErrInvalidTransaction is returned when raw transaction bytes cannot be decoded
+var ErrInvalidTransaction = errors.New("invalid transaction")
+
+// Transaction is an EIP-1559 (type 2) transaction. Access lists are not
+// supported: they are always encoded empty and rejected when decoding.
+type Transaction struct {
+	ChainID   *big.Int
+	Nonce     uint64
+	GasTipCap *big.Int // maxPriorityFeePerGas
+	GasFeeCap *big.Int // maxFeePerGas
+	Gas       uint64
+	To        *Address // nil creates a contract
+	Value     *big.Int
+	Data      []byte
+
+	// Signature, set by Sign or DecodeTransaction
+	V    uint8 // y parity, 0 or 1
+	R, S *big.Int
+}
+
+// fields returns the RLP items of the unsigned transaction
+func (tx *Transaction) fields() rlpList {
+	to := []byte{}
+	if tx.To != nil {
+		to = tx.To[:]
+	}
+	return rlpList{
+		rlpBig(tx.ChainID),
+		rlpUint(tx.Nonce),
+		rlpBig(tx.GasTipCap),
+		rlpBig(tx.GasFeeCap),
+		rlpUint(tx.Gas),
+		to,
+		rlpBig(tx.Value),
+		tx.Data,
+		rlpList{}, // access list
+	}
+}
+
+// SigningHash returns the hash signed by the sender
+func (tx *Transaction) SigningHash() Hash {
+	return Keccak256([]byte{DynamicFeeTxType}, rlpEncode(tx.fields()))
+}
+
+// Sign signs the transaction with key
+func (tx *Transaction) Sign(key *PrivateKey) {
+	sig := key.Sign(tx.SigningHash())
+	tx.R = new(big.Int).SetBytes(sig[:32])
+	tx.S = new(big.Int).SetBytes(sig[32:64])
+	tx.V = sig[64]
+}
+
+// Signed reports whether the transaction carries a signature
+func (tx *Transaction) Signed() bool {
+	return tx.R != nil && tx.S != nil
+}
+
+// MarshalBinary returns the raw signed transaction sent to
+// eth_sendRawTransaction
+func (tx *Transaction) MarshalBinary() ([]byte, error) {
+	if !tx.Signed() {
+		return nil, fmt.Errorf("%w: not signed", ErrInvalidTransaction)
+	}
+	fields := append(tx.fields(), rlpUint(uint64(tx.V)), rlpBig(tx.R), rlpBig(tx.S))
+	return append([]byte{DynamicFeeTxType}, rlpEncode(fields)...), nil
+}
+
+// Hash returns the transaction hash of a signed transaction
+func (tx *Transaction) Hash() (Hash, error) {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return Hash{}, err
+	}
+	return Keccak256(raw), nil
+}
+
+// Sender recovers the address that signed the transaction
+func (tx *Transaction) Sender() (Address, error) {
+	if !tx.Signed() || tx.R.BitLen() > 256 || tx.S.BitLen() > 256 {
+		return Address{}, fmt.Errorf("%w: not signed", ErrInvalidSignature)
+	}
+	sig := make([]byte, SignatureLength)
+	tx.R.FillBytes(sig[:32])
+	tx.S.FillBytes(sig[32:64])
+	sig[64] = tx.V
+	return RecoverAddress(tx.SigningHash(), sig)
+}
+
+// DecodeTransaction decodes a raw signed EIP-1559 transaction
+func DecodeTransaction(raw []byte) (*Transaction, error) {
+	if len(raw) == 0 || raw[0] != DynamicFeeTxType {
+		return nil, fmt.Errorf("%w: only EIP-1559 (type 2) transactions are supported", ErrInvalidTransaction)
+	}
+	item, err := rlpDecode(raw[1:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+	fields, ok := item.(rlpList)
+	if !ok || len(fields) != 12 {
+		return nil, fmt.Errorf("%w: want a list of 12 fields", ErrInvalidTransaction)
+	}
+
+	tx := &Transaction{}
+	if err := decodeTxFields(tx, fields); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+	return tx, nil
+}
+
+func decodeTxFields(tx *Transaction, fields rlpList) error {
+	var err error
+	if tx.ChainID, err = rlpBigAt(fields, 0); err != nil {
+		return err
+	}
+	if tx.Nonce, err = rlpUintAt(fields, 1); err != nil {
+		return err
+	}
+	if tx.GasTipCap, err = rlpBigAt(fields, 2); err != nil {
+		return err
+	}
+	if tx.GasFeeCap, err = rlpBigAt(fields, 3); err != nil {
+		return err
+	}
+	if tx.Gas, err = rlpUintAt(fields, 4); err != nil {
+		return err
+	}
+
+	to, err := rlpBytesAt(fields, 5)
+	if err != nil {
+		return err
+	}
+	switch len(to) {
+	case 0:
+	case len(Address{}):
+		tx.To = new(Address)
+		copy(tx.To[:], to)
+	default:
+		return fmt.Errorf("recipient is %d bytes", len(to))
+	}
+
+	if tx.Value, err = rlpBigAt(fields, 6); err != nil {
+		return err
+	}
+	if tx.Data, err = rlpBytesAt(fields, 7); err != nil {
+		return err
+	}
+	if accessList, ok := fields[8].(rlpList); !ok || len(accessList) > 0 {
+		return errors.New("access lists are not supported")
+	}
+
+	v, err := rlpUintAt(fields, 9)
+	if err != nil {
+		return err
+	}
+	if v > 1 {
+		return fmt.Errorf("y parity %d", v)
+	}
+	tx.V = uint8(v)
+	if tx.R, err = rlpBigAt(fields, 10); err != nil {
+		return err
+	}
+	tx.S, err = rlpBigAt(fields, 11)
+	return err
+}
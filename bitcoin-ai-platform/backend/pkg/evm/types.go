@@ -0,0 +1,167 @@
+package evm
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// ErrInvalidAddress is returned when a string is not a 0x-prefixed 20 byte hex address
+var ErrInvalidAddress = errors.New("invalid EVM address")
+
+// Address is a 20 byte EVM account or contract address
+type Address [20]byte
+
+// Hash is a 32 byte Keccak-256 hash, used for transaction hashes and log topics
+type Hash [32]byte
+
+// ParseAddress parses a 0x-prefixed hex address in any letter case
+func ParseAddress(s string) (Address, error) {
+	var a Address
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return a, fmt.Errorf("%w: %q lacks the 0x prefix", ErrInvalidAddress, s)
+	}
+	b, err := hex.DecodeString(s[2:])
+	if err != nil || len(b) != len(a) {
+		return a, fmt.Errorf("%w: %q is not 40 hex digits", ErrInvalidAddress, s)
+	}
+	copy(a[:], b)
+	return a, nil
+}
+
+// Hex returns the EIP-55 mixed-case checksum encoding of the address
+func (a Address) Hex() string {
+	lower := hex.EncodeToString(a[:])
+	digest := Keccak256([]byte(lower))
+
+	out := []byte(lower)
+	for i, c := range out {
+		if c < 'a' {
+			continue
+		}
+		// Upper-case a letter when the matching nibble of the hash is >= 8
+		nibble := digest[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if nibble&0x0f >= 8 {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(out)
+}
+
+// String implements fmt.Stringer
+func (a Address) String() string {
+	return a.Hex()
+}
+
+// IsZero reports whether a is the zero address
+func (a Address) IsZero() bool {
+	return a == Address{}
+}
+
+// MarshalText encodes the address in its checksum form
+func (a Address) MarshalText() ([]byte, error) {
+	return []byte(a.Hex()), nil
+}
+
+// UnmarshalText parses a hex address
+func (a *Address) UnmarshalText(text []byte) error {
+	parsed, err := ParseAddress(string(text))
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// ParseHash parses a 0x-prefixed 32 byte hex hash
+func ParseHash(s string) (Hash, error) {
+	var h Hash
+	b, err := decodeHex(s)
+	if err != nil || len(b) != len(h) {
+		return h, fmt.Errorf("invalid hash %q", s)
+	}
+	copy(h[:], b)
+	return h, nil
+}
+
+// Hex returns the 0x-prefixed lower-case encoding of the hash
+func (h Hash) Hex() string {
+	return "0x" + hex.EncodeToString(h[:])
+}
+
+// String implements fmt.Stringer
+func (h Hash) String() string {
+	return h.Hex()
+}
+
+// MarshalText encodes the hash as hex
+func (h Hash) MarshalText() ([]byte, error) {
+	return []byte(h.Hex()), nil
+}
+
+// UnmarshalText parses a hex hash
+func (h *Hash) UnmarshalText(text []byte) error {
+	parsed, err := ParseHash(string(text))
+	if err != nil {
+		return err
+	}
+	*h = parsed
+	return nil
+}
+
+// Keccak256 returns the legacy Keccak-256 digest used throughout Ethereum
+func Keccak256(data ...[]byte) Hash {
+	d := sha3.NewLegacyKeccak256()
+	for _, b := range data {
+		d.Write(b)
+	}
+	var h Hash
+	d.Sum(h[:0])
+	return h
+}
+
+// encodeHex returns the 0x-prefixed hex encoding of b
+func encodeHex(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+// decodeHex parses 0x-prefixed hex data
+func decodeHex(s string) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return nil, fmt.Errorf("hex data %q lacks the 0x prefix", s)
+	}
+	return hex.DecodeString(s[2:])
+}
+
+// encodeQuantity returns the JSON-RPC encoding of an unsigned number
+func encodeQuantity(n uint64) string {
+	return "0x" + strconv.FormatUint(n, 16)
+}
+
+// decodeQuantity parses a JSON-RPC quantity such as "0x1a"
+func decodeQuantity(s string) (uint64, error) {
+	if !strings.HasPrefix(s, "0x") || len(s) < 3 {
+		return 0, fmt.Errorf("invalid quantity %q", s)
+	}
+	return strconv.ParseUint(s[2:], 16, 64)
+}
+
+// decodeBigQuantity parses a JSON-RPC quantity of any size
+func decodeBigQuantity(s string) (*big.Int, error) {
+	if !strings.HasPrefix(s, "0x") || len(s) < 3 {
+		return nil, fmt.Errorf("invalid quantity %q", s)
+	}
+	n, ok := new(big.Int).SetString(s[2:], 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid quantity %q", s)
+	}
+	return n, nil
+}
@@ -1,30 +1,84 @@
 package client
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 )
 
+// Default per-operation deadlines, overridable on ExSatClient
+const (
+	DefaultReadTimeout  = 10 * time.Second
+	DefaultWriteTimeout = 30 * time.Second
+)
+
+// Listing limits. GetAssets requests pages of DefaultPageSize and gives up
+// after maxListPages, in case exSat keeps returning a cursor.
+const (
+	DefaultPageSize = 100
+	maxListPages    = 1000
+)
+
 // ExSatClient represents a client for interacting with the exSat API
 type ExSatClient struct {
 	BaseURL    string
 	APIKey     string
 	HTTPClient *http.Client
+
+	// ReadTimeout bounds GET calls and WriteTimeout calls that modify state.
+	// Zero means the caller's context is the only deadline.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// Retry controls retries of failed calls and Breaker, when set, stops
+	// calling exSat after repeated failures
+	Retry   RetryPolicy
+	Breaker *CircuitBreaker
 }
 
+// ExSatAPI is the exSat API surface used by FansMint. ExSatClient
+// implements it; tests substitute their own implementation.
+type ExSatAPI interface {
+	CreateAsset(ctx context.Context, params AssetCreateParams) (*Asset, error)
+	GetAsset(ctx context.Context, assetID string) (*Asset, error)
+	ListAssets(ctx context.Context, params ListAssetsParams) (*AssetsPage, error)
+	GetAssets(ctx context.Context) ([]Asset, error)
+}
+
+var _ ExSatAPI = (*ExSatClient)(nil)
+
 // NewExSatClient creates a new exSat API client
 func NewExSatClient(baseURL, apiKey string) *ExSatClient {
 	return &ExSatClient{
 		BaseURL: baseURL,
 		APIKey:  apiKey,
-		HTTPClient: &http.Client{
-			Timeout: time.Second * 30,
-		},
+		// Deadlines come from the request context and the per-operation timeouts
+		HTTPClient:   &http.Client{},
+		ReadTimeout:  DefaultReadTimeout,
+		WriteTimeout: DefaultWriteTimeout,
+		Retry:        DefaultRetryPolicy,
+		Breaker:      NewCircuitBreaker(5, 30*time.Second),
+	}
+}
+
+// CircuitState returns the state of the circuit breaker, "closed" when there is none
+func (c *ExSatClient) CircuitState() string {
+	if c.Breaker == nil {
+		return CircuitClosed
 	}
+	return c.Breaker.State()
+}
+
+// withTimeout derives a context bounded by timeout when it is positive
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
 }
 
 // Asset represents an asset on the exSat platform
@@ -32,24 +86,76 @@ type Asset struct {
 	ID                string `json:"id"`
 	Name              string `json:"name"`
 	Symbol            string `json:"symbol"`
-	TotalSupply       string `json:"totalSupply"`
-	CirculatingSupply string `json:"circulatingSupply"`
+	TotalSupply       string `json:"totalSupply"`       // base units
+	CirculatingSupply string `json:"circulatingSupply"` // base units
+	Decimals          uint8  `json:"decimals"`
 	Description       string `json:"description"`
 	CreatorAddress    string `json:"creatorAddress"`
 	ContractAddress   string `json:"contractAddress"`
 	CreatedAt         string `json:"createdAt"`
-	Status            string `json:"status"`
+	Status            string `json:"status"` // pending_review (token awaiting the owner's signature), deploying, active, paused, failed or retired
+	Holders           int64  `json:"holders"`
 	IconUrl           string `json:"iconUrl,omitempty"`
+
+	// The supplies in whole tokens, filled in by FansMint so clients never
+	// do floating point math on amounts
+	TotalSupplyFormatted       string `json:"totalSupplyFormatted,omitempty"`
+	CirculatingSupplyFormatted string `json:"circulatingSupplyFormatted,omitempty"`
+
+	// OwnerBitcoinAddress is the Bitcoin address the owner named at
+	// creation, recorded by FansMint without proof of control
+	OwnerBitcoinAddress string `json:"ownerBitcoinAddress,omitempty"`
+
+	// Deployment is recorded by FansMint when it deploys the token contract
+	Deployment *Deployment `json:"deployment,omitempty"`
+
+	// Whitepaper is recorded by FansMint when the owner publishes one
+	Whitepaper *Whitepaper `json:"whitepaper,omitempty"`
+}
+
+// Whitepaper identifies the published version of the whitepaper of an
+// asset, so that holders can check the document they read
+type Whitepaper struct {
+	Version     int    `json:"version"`
+	ContentHash string `json:"contentHash"` // hex SHA-256 of the markdown
+	PublishedAt string `json:"publishedAt"`
+}
+
+// Deployment statuses of a token contract
+const (
+	// DeploymentAwaitingSignature is a prepared deployment the owner has
+	// not submitted yet
+	DeploymentAwaitingSignature = "awaiting_signature"
+	DeploymentPending           = "pending"
+	DeploymentConfirmed         = "confirmed"
+	DeploymentFailed            = "failed"
+)
+
+// Deployment tracks the transaction deploying an asset's token contract
+type Deployment struct {
+	Status      string  `json:"status"` // awaiting_signature, pending, confirmed or failed
+	Factory     string  `json:"factory"`
+	Sender      string  `json:"sender,omitempty"` // account that signed the transaction
+	TxHash      string  `json:"txHash,omitempty"`
+	Nonce       *uint64 `json:"nonce,omitempty"`       // of the sender, taken by the transaction
+	BlockNumber uint64  `json:"blockNumber,omitempty"` // block holding the transaction once mined
+	Error       string  `json:"error,omitempty"`
+	SubmittedAt string  `json:"submittedAt,omitempty"`
+	UpdatedAt   string  `json:"updatedAt"`
 }
 
 // AssetCreateParams represents params for creating a new asset
 type AssetCreateParams struct {
 	Name         string `json:"name"`
 	Symbol       string `json:"symbol"`
-	TotalSupply  string `json:"totalSupply"`
+	TotalSupply  string `json:"totalSupply"` // base units
+	Decimals     uint8  `json:"decimals"`
 	Description  string `json:"description"`
 	OwnerAddress string `json:"ownerAddress"`
 	IconData     string `json:"iconData,omitempty"` // base64 encoded image data
+
+	// IdempotencyKey is sent as the Idempotency-Key header, not in the body
+	IdempotencyKey string `json:"-"`
 }
 
 // AssetResponse is the API response for asset operations
@@ -61,144 +167,160 @@ type AssetResponse struct {
 
 // AssetsResponse is the API response for listing assets
 type AssetsResponse struct {
-	Success bool    `json:"success"`
-	Message string  `json:"message"`
-	Assets  []Asset `json:"assets,omitempty"`
+	Success    bool    `json:"success"`
+	Message    string  `json:"message"`
+	Assets     []Asset `json:"assets,omitempty"`
+	NextCursor string  `json:"nextCursor,omitempty"`
+}
+
+// ListAssetsParams selects a page of the asset listing
+type ListAssetsParams struct {
+	Limit  int    // page size, DefaultPageSize when zero
+	Cursor string // NextCursor of the previous page, empty for the first page
 }
 
-// CreateAsset creates a new asset on the exSat platform
-func (c *ExSatClient) CreateAsset(params AssetCreateParams) (*Asset, error) {
+// AssetsPage is one page of the asset listing
+type AssetsPage struct {
+	Assets     []Asset
+	NextCursor string // empty on the last page
+}
+
+// CreateAsset creates a new asset on the exSat platform.
+// The request carries params.IdempotencyKey, or a generated key, so that
+// retries after a lost response cannot create the asset twice.
+func (c *ExSatClient) CreateAsset(ctx context.Context, params AssetCreateParams) (*Asset, error) {
 	// Convert params to JSON
 	jsonData, err := json.Marshal(params)
 	if err != nil {
 		return nil, fmt.Errorf("error marshaling params: %w", err)
 	}
 
-	// Create request
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/assets/create", c.BaseURL), bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
-
-	// Send request
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error sending request: %w", err)
+	key := params.IdempotencyKey
+	if key == "" {
+		key = newIdempotencyKey()
 	}
-	defer resp.Body.Close()
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	resp, err := c.do(ctx, apiRequest{
+		method:         http.MethodPost,
+		path:           "/assets/create",
+		body:           jsonData,
+		idempotencyKey: key,
+		timeout:        c.WriteTimeout,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
+		return nil, err
 	}
 
 	// Check response status code
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("API error: %s", string(body))
+	if resp.status != http.StatusOK && resp.status != http.StatusCreated {
+		return nil, decodeError(resp)
 	}
 
 	// Parse response
 	var response AssetResponse
-	if err := json.Unmarshal(body, &response); err != nil {
+	if err := json.Unmarshal(resp.body, &response); err != nil {
 		return nil, fmt.Errorf("error unmarshaling response: %w", err)
 	}
 
 	if !response.Success {
-		return nil, fmt.Errorf("API error: %s", response.Message)
+		return nil, unsuccessful(response.Message)
 	}
 
 	return &response.Asset, nil
 }
 
 // GetAsset retrieves details of a specific asset
-func (c *ExSatClient) GetAsset(assetID string) (*Asset, error) {
-	// Create request
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/assets/%s", c.BaseURL, assetID), nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
-
-	// Send request
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error sending request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+func (c *ExSatClient) GetAsset(ctx context.Context, assetID string) (*Asset, error) {
+	resp, err := c.do(ctx, apiRequest{
+		method:  http.MethodGet,
+		path:    "/assets/" + url.PathEscape(assetID),
+		timeout: c.ReadTimeout,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
+		return nil, err
 	}
 
 	// Check response status code
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error: %s", string(body))
+	if resp.status != http.StatusOK {
+		return nil, decodeError(resp)
 	}
 
 	// Parse response
 	var response AssetResponse
-	if err := json.Unmarshal(body, &response); err != nil {
+	if err := json.Unmarshal(resp.body, &response); err != nil {
 		return nil, fmt.Errorf("error unmarshaling response: %w", err)
 	}
 
 	if !response.Success {
-		return nil, fmt.Errorf("API error: %s", response.Message)
+		return nil, unsuccessful(response.Message)
 	}
 
 	return &response.Asset, nil
 }
 
-// GetAssets retrieves a list of assets
-func (c *ExSatClient) GetAssets() ([]Asset, error) {
-	// Create request
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/assets", c.BaseURL), nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+// ListAssets retrieves one page of the asset listing
+func (c *ExSatClient) ListAssets(ctx context.Context, params ListAssetsParams) (*AssetsPage, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = DefaultPageSize
 	}
-
-	// Set headers
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
-
-	// Send request
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error sending request: %w", err)
+	query := url.Values{"limit": {strconv.Itoa(limit)}}
+	if params.Cursor != "" {
+		query.Set("cursor", params.Cursor)
 	}
-	defer resp.Body.Close()
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	resp, err := c.do(ctx, apiRequest{
+		method:  http.MethodGet,
+		path:    "/assets?" + query.Encode(),
+		timeout: c.ReadTimeout,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
+		return nil, err
 	}
 
 	// Check response status code
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error: %s", string(body))
+	if resp.status != http.StatusOK {
+		return nil, decodeError(resp)
 	}
 
 	// Parse response
 	var response AssetsResponse
-	if err := json.Unmarshal(body, &response); err != nil {
+	if err := json.Unmarshal(resp.body, &response); err != nil {
 		return nil, fmt.Errorf("error unmarshaling response: %w", err)
 	}
 
 	if !response.Success {
-		return nil, fmt.Errorf("API error: %s", response.Message)
+		return nil, unsuccessful(response.Message)
 	}
 
-	return response.Assets, nil
+	return &AssetsPage{Assets: response.Assets, NextCursor: response.NextCursor}, nil
+}
+
+// GetAssets retrieves every asset, following the listing cursor page by page.
+// ReadTimeout applies to each page.
+func (c *ExSatClient) GetAssets(ctx context.Context) ([]Asset, error) {
+	var assets []Asset
+	seen := make(map[string]bool)
+
+	params := ListAssetsParams{Limit: DefaultPageSize}
+	for range maxListPages {
+		page, err := c.ListAssets(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		assets = append(assets, page.Assets...)
+
+		if page.NextCursor == "" {
+			return assets, nil
+		}
+		if seen[page.NextCursor] {
+			return nil, unsuccessful(fmt.Sprintf("listing cursor %q repeated", page.NextCursor))
+		}
+		seen[page.NextCursor] = true
+		params.Cursor = page.NextCursor
+	}
+	return nil, unsuccessful(fmt.Sprintf("listing did not end after %d pages", maxListPages))
 }
 
 // Note: This is a basic implementation. In a real-world scenario,
-// you would need to add more error handling, pagination for listing assets,
-// additional endpoints for transfers, and wallet functionality.
+// you would need additional endpoints for transfers and wallet functionality.
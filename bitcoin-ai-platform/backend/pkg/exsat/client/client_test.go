@@ -0,0 +1,358 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client for server with fast retries
+func newTestClient(server *httptest.Server) *ExSatClient {
+	c := NewExSatClient(server.URL, "key")
+	c.Retry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+	c.Breaker = nil
+	return c
+}
+
+func TestGetAssetRetriesServerErrors(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		json.NewEncoder(w).Encode(AssetResponse{Success: true, Asset: Asset{ID: "1"}})
+	}))
+	defer server.Close()
+
+	asset, err := newTestClient(server).GetAsset(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("GetAsset: %v", err)
+	}
+	if asset.ID != "1" || calls != 3 {
+		t.Errorf("got asset %q after %d calls, want asset 1 after 3 calls", asset.ID, calls)
+	}
+}
+
+func TestRetryAfterIsHonored(t *testing.T) {
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		times = append(times, time.Now())
+		if len(times) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(AssetsResponse{Success: true})
+	}))
+	defer server.Close()
+
+	c := newTestClient(server)
+	c.Retry.MaxDelay = 2 * time.Second
+	if _, err := c.GetAssets(context.Background()); err != nil {
+		t.Fatalf("GetAssets: %v", err)
+	}
+	if len(times) != 2 {
+		t.Fatalf("got %d calls, want 2", len(times))
+	}
+	if wait := times[1].Sub(times[0]); wait < time.Second {
+		t.Errorf("retried after %s, want at least the 1s Retry-After", wait)
+	}
+}
+
+func TestLongRetryAfterIsReturned(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		retryAfter time.Duration
+		maxDelay   time.Duration
+		timeout    time.Duration
+	}{
+		{"beyond the longest delay", time.Minute, 10 * time.Millisecond, time.Minute},
+		{"beyond the deadline", 2 * time.Second, 5 * time.Second, 500 * time.Millisecond},
+	} {
+		var calls int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Retry-After", fmt.Sprint(tt.retryAfter.Seconds()))
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+
+		c := newTestClient(server)
+		c.Retry.MaxDelay = tt.maxDelay
+		c.ReadTimeout = tt.timeout
+		started := time.Now()
+		_, err := c.GetAsset(context.Background(), "1")
+		server.Close()
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !errors.Is(err, ErrRateLimited) || apiErr.RetryAfter != tt.retryAfter {
+			t.Errorf("%s: got %v, want ErrRateLimited retrying after %s", tt.name, err, tt.retryAfter)
+		}
+		if calls != 1 || time.Since(started) > 100*time.Millisecond {
+			t.Errorf("%s: got %d calls in %s, want a single call returned at once", tt.name, calls, time.Since(started))
+		}
+	}
+}
+
+func TestRateLimitsDoNotTripBreaker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c := newTestClient(server)
+	c.Breaker = NewCircuitBreaker(2, time.Minute)
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetAssets(context.Background()); !errors.Is(err, ErrRateLimited) {
+			t.Fatalf("call %d returned %v, want ErrRateLimited", i, err)
+		}
+	}
+	if state := c.CircuitState(); state != CircuitClosed {
+		t.Errorf("state after 9 rate limited attempts = %s, want closed", state)
+	}
+}
+
+func TestCreateAssetReusesIdempotencyKey(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(AssetResponse{Success: true, Asset: Asset{ID: "new"}})
+	}))
+	defer server.Close()
+
+	if _, err := newTestClient(server).CreateAsset(context.Background(), AssetCreateParams{Name: "A"}); err != nil {
+		t.Fatalf("CreateAsset: %v", err)
+	}
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("idempotency keys = %q, want the same non-empty key on both attempts", keys)
+	}
+}
+
+func TestClientErrorsAreNotRetried(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	if _, err := newTestClient(server).GetAsset(context.Background(), "1"); err == nil {
+		t.Fatal("GetAsset succeeded, want an error")
+	}
+	if calls != 1 {
+		t.Errorf("got %d calls, want 1", calls)
+	}
+}
+
+func TestErrorResponsesAreTyped(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		kind   error
+	}{
+		{http.StatusNotFound, `{"success":false,"message":"asset not found"}`, ErrNotFound},
+		{http.StatusUnauthorized, `{"error":"bad key"}`, ErrUnauthorized},
+		{http.StatusForbidden, ``, ErrUnauthorized},
+		{http.StatusTooManyRequests, `{"message":"slow down"}`, ErrRateLimited},
+		{http.StatusUnprocessableEntity, `{"message":"invalid","errors":[{"field":"symbol","message":"already taken"}]}`, ErrValidation},
+		{http.StatusServiceUnavailable, `<html>internal stack trace</html>`, ErrUpstream},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if tt.status == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "7")
+			}
+			w.WriteHeader(tt.status)
+			w.Write([]byte(tt.body))
+		}))
+		c := newTestClient(server)
+		c.Retry.MaxAttempts = 1
+
+		_, err := c.GetAsset(context.Background(), "1")
+		server.Close()
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !errors.Is(err, tt.kind) {
+			t.Errorf("status %d: got %v, want an APIError of kind %v", tt.status, err, tt.kind)
+			continue
+		}
+		if apiErr.StatusCode != tt.status {
+			t.Errorf("status %d: StatusCode = %d", tt.status, apiErr.StatusCode)
+		}
+		if strings.Contains(err.Error(), "stack trace") {
+			t.Errorf("status %d: error %q leaks the response body", tt.status, err)
+		}
+		switch tt.kind {
+		case ErrValidation:
+			if len(apiErr.Fields) != 1 || apiErr.Fields[0].Field != "symbol" {
+				t.Errorf("validation fields = %+v, want symbol", apiErr.Fields)
+			}
+		case ErrRateLimited:
+			if apiErr.RetryAfter != 7*time.Second {
+				t.Errorf("RetryAfter = %s, want 7s", apiErr.RetryAfter)
+			}
+		}
+	}
+}
+
+func TestCircuitBreakerTripsAndRecovers(t *testing.T) {
+	var mu sync.Mutex
+	healthy := false
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if !healthy {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(AssetsResponse{Success: true})
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	c := newTestClient(server)
+	c.Retry.MaxAttempts = 1
+	c.Breaker = NewCircuitBreaker(2, time.Minute)
+	c.Breaker.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		c.GetAssets(context.Background())
+	}
+	if state := c.CircuitState(); state != CircuitOpen {
+		t.Fatalf("state after 2 failures = %s, want open", state)
+	}
+
+	if _, err := c.GetAssets(context.Background()); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("call while open returned %v, want ErrCircuitOpen", err)
+	}
+	if calls != 2 {
+		t.Errorf("exSat saw %d calls, want 2: the open circuit must not call it", calls)
+	}
+
+	mu.Lock()
+	healthy = true
+	mu.Unlock()
+	now = now.Add(2 * time.Minute)
+	if state := c.CircuitState(); state != CircuitHalfOpen {
+		t.Fatalf("state after cooldown = %s, want half-open", state)
+	}
+	if _, err := c.GetAssets(context.Background()); err != nil {
+		t.Fatalf("probe call: %v", err)
+	}
+	if state := c.CircuitState(); state != CircuitClosed {
+		t.Errorf("state after successful probe = %s, want closed", state)
+	}
+}
+
+// hangingServer stands in for an exSat that accepts requests and never
+// answers, until healthy is called
+func hangingServer(t *testing.T) (server *httptest.Server, healthy func()) {
+	var mu sync.Mutex
+	hang := true
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hanging := hang
+		mu.Unlock()
+		if hanging {
+			<-r.Context().Done()
+			return
+		}
+		json.NewEncoder(w).Encode(AssetResponse{Success: true, Asset: Asset{ID: "a1"}})
+	}))
+	t.Cleanup(server.Close)
+	return server, func() {
+		mu.Lock()
+		hang = false
+		mu.Unlock()
+	}
+}
+
+func TestHangingServerTripsBreaker(t *testing.T) {
+	server, _ := hangingServer(t)
+
+	c := newTestClient(server)
+	c.ReadTimeout = 20 * time.Millisecond
+	c.Retry.MaxAttempts = 1
+	c.Breaker = NewCircuitBreaker(2, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetAsset(context.Background(), "a1"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("call %d returned %v, want a deadline error", i, err)
+		}
+	}
+	if state := c.CircuitState(); state != CircuitOpen {
+		t.Errorf("state after 2 timeouts = %s, want open", state)
+	}
+}
+
+func TestCancelledProbeDoesNotWedgeBreaker(t *testing.T) {
+	server, healthy := hangingServer(t)
+
+	now := time.Now()
+	c := newTestClient(server)
+	c.ReadTimeout = 20 * time.Millisecond
+	c.Retry.MaxAttempts = 1
+	c.Breaker = NewCircuitBreaker(1, time.Minute)
+	c.Breaker.now = func() time.Time { return now }
+
+	c.GetAsset(context.Background(), "a1")
+	if state := c.CircuitState(); state != CircuitOpen {
+		t.Fatalf("state after timeout = %s, want open", state)
+	}
+
+	// The probe is abandoned by its caller before exSat answers
+	now = now.Add(2 * time.Minute)
+	c.ReadTimeout = time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.GetAsset(ctx, "a1"); err == nil {
+		t.Fatal("cancelled probe succeeded")
+	}
+	if state := c.CircuitState(); state != CircuitHalfOpen {
+		t.Fatalf("state after cancelled probe = %s, want half-open", state)
+	}
+
+	healthy()
+	if _, err := c.GetAsset(context.Background(), "a1"); err != nil {
+		t.Fatalf("next probe: %v", err)
+	}
+	if state := c.CircuitState(); state != CircuitClosed {
+		t.Errorf("state after successful probe = %s, want closed", state)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{now.Add(5 * time.Second).Format(http.TimeFormat), 5 * time.Second, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %s, %v, want %s, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Error kinds returned by ExSatClient, to be matched with errors.Is
+var (
+	ErrNotFound     = errors.New("exSat resource not found")
+	ErrUnauthorized = errors.New("exSat rejected the API credentials")
+	ErrRateLimited  = errors.New("exSat rate limit exceeded")
+	ErrValidation   = errors.New("exSat rejected the request")
+	ErrUpstream     = errors.New("exSat request failed")
+)
+
+// FieldError describes a rejected request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// APIError is an error response from the exSat API. It wraps one of the
+// error kinds above; the raw response body is kept out of Error() so it is
+// never echoed to API consumers.
+type APIError struct {
+	Kind       error
+	StatusCode int
+	Message    string        // message reported by exSat, if any
+	Fields     []FieldError  // set for ErrValidation
+	RetryAfter time.Duration // set for ErrRateLimited when exSat sent Retry-After
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("%v: %s", e.Kind, e.Message)
+	}
+	if e.Message == "" {
+		return fmt.Sprintf("%v (status %d)", e.Kind, e.StatusCode)
+	}
+	return fmt.Sprintf("%v (status %d): %s", e.Kind, e.StatusCode, e.Message)
+}
+
+// Unwrap allows errors.Is(err, ErrNotFound) and friends
+func (e *APIError) Unwrap() error {
+	return e.Kind
+}
+
+// errorBody covers the error shapes returned by exSat
+type errorBody struct {
+	Message string       `json:"message"`
+	Error   string       `json:"error"`
+	Errors  []FieldError `json:"errors"`
+}
+
+// decodeError builds the APIError for a non-successful response
+func decodeError(resp *apiResponse) *APIError {
+	apiErr := &APIError{
+		Kind:       kindForStatus(resp.status),
+		StatusCode: resp.status,
+	}
+
+	var body errorBody
+	if err := json.Unmarshal(resp.body, &body); err == nil {
+		apiErr.Message = strings.TrimSpace(body.Message)
+		if apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(body.Error)
+		}
+		apiErr.Fields = body.Errors
+	}
+
+	if apiErr.Kind == ErrRateLimited {
+		apiErr.RetryAfter = resp.retryAfter
+	}
+	return apiErr
+}
+
+// unsuccessful builds the APIError for a 2xx response whose body reports failure
+func unsuccessful(message string) *APIError {
+	return &APIError{Kind: ErrUpstream, Message: message}
+}
+
+// kindForStatus maps an HTTP status to an error kind
+func kindForStatus(status int) error {
+	switch status {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusConflict:
+		return ErrValidation
+	default:
+		return ErrUpstream
+	}
+}
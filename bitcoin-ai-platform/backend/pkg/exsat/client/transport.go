@@ -0,0 +1,204 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	mathrand "math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed exSat calls are retried
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts including the first, 1 disables retries
+	BaseDelay   time.Duration // delay before the first retry, doubled on each retry
+	MaxDelay    time.Duration // upper bound of a single backoff or Retry-After wait
+}
+
+// DefaultRetryPolicy is used by NewExSatClient
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+}
+
+// backoff returns the jittered delay before retry number attempt (1-based)
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(attempt-1))
+	if delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	// Full jitter between half and the whole delay avoids synchronized retries
+	return time.Duration(delay/2 + mathrand.Float64()*delay/2)
+}
+
+// retryableStatus reports whether a response status is worth retrying
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := at.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// withinDeadline reports whether ctx allows waiting for d
+func withinDeadline(ctx context.Context, d time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Until(deadline) > d
+}
+
+// newIdempotencyKey returns a random key identifying one logical write
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+// apiRequest describes one logical call to the exSat API
+type apiRequest struct {
+	method         string
+	path           string
+	body           []byte
+	idempotencyKey string        // required for writes to be retried
+	timeout        time.Duration // bounds every attempt together, 0 for none
+}
+
+// apiResponse is the raw response of a call
+type apiResponse struct {
+	status     int
+	body       []byte
+	retryAfter time.Duration
+}
+
+// do sends a request through the circuit breaker, retrying network errors,
+// 429 and 5xx responses. GETs are always retried; other methods only when
+// they carry an idempotency key, so a retry can never apply a write twice.
+// ctx is the caller's: the request timeout running out counts as a failure
+// of exSat, the caller giving up does not.
+func (c *ExSatClient) do(ctx context.Context, req apiRequest) (*apiResponse, error) {
+	opCtx, cancel := withTimeout(ctx, req.timeout)
+	defer cancel()
+
+	retryable := req.method == http.MethodGet || req.idempotencyKey != ""
+	attempts := c.Retry.MaxAttempts
+	if attempts < 1 || !retryable {
+		attempts = 1
+	}
+
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		if c.Breaker != nil {
+			if err := c.Breaker.Allow(); err != nil {
+				if lastErr != nil {
+					return nil, fmt.Errorf("%w (last error: %v)", err, lastErr)
+				}
+				return nil, err
+			}
+		}
+
+		resp, err := c.attempt(opCtx, req)
+		failed := err != nil || retryableStatus(resp.status)
+		if c.Breaker != nil {
+			switch {
+			case !failed:
+				c.Breaker.Success()
+			case ctx.Err() != nil, err == nil && resp.status == http.StatusTooManyRequests:
+				// Only failures of exSat itself count, not a caller giving
+				// up or exSat asking this client to slow down
+				c.Breaker.Release()
+			default:
+				c.Breaker.Failure()
+			}
+		}
+		if !failed {
+			return resp, nil
+		}
+		if err == nil {
+			lastErr = fmt.Errorf("status %d", resp.status)
+		} else {
+			lastErr = err
+		}
+
+		if attempt >= attempts || opCtx.Err() != nil {
+			if err != nil {
+				return nil, err
+			}
+			return resp, nil
+		}
+
+		delay := c.Retry.backoff(attempt)
+		if err == nil && resp.retryAfter > 0 {
+			// Retrying any sooner than asked is pointless, so a wait longer
+			// than allowed goes back to the caller as ErrRateLimited with
+			// its RetryAfter
+			if resp.retryAfter > c.Retry.MaxDelay || !withinDeadline(opCtx, resp.retryAfter) {
+				return resp, nil
+			}
+			delay = resp.retryAfter
+		}
+		select {
+		case <-opCtx.Done():
+			return nil, fmt.Errorf("error sending request: %w", opCtx.Err())
+		case <-time.After(delay):
+		}
+	}
+}
+
+// attempt performs a single HTTP exchange
+func (c *ExSatClient) attempt(ctx context.Context, req apiRequest) (*apiResponse, error) {
+	var body io.Reader
+	if req.body != nil {
+		body = bytes.NewReader(req.body)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, c.BaseURL+req.path, body)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	// Set headers
+	if req.body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+	if req.idempotencyKey != "" {
+		httpReq.Header.Set("Idempotency-Key", req.idempotencyKey)
+	}
+
+	// Send request
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error sending request: %w", ErrUpstream, err)
+	}
+	defer resp.Body.Close()
+
+	// Read response body
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error reading response body: %w", ErrUpstream, err)
+	}
+
+	retryAfter, _ := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	return &apiResponse{status: resp.StatusCode, body: data, retryAfter: retryAfter}, nil
+}
@@ -0,0 +1,188 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"strings"
+
+	// Register the raster decoders accepted for uploads
+	_ "image/jpeg"
+
+	_ "golang.org/x/image/webp"
+
+	"golang.org/x/image/draw"
+)
+
+// Supported content types
+const (
+	ContentTypePNG  = "image/png"
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypeWebP = "image/webp"
+	ContentTypeSVG  = "image/svg+xml"
+)
+
+// ErrInvalidImage is the error all validation failures wrap
+var ErrInvalidImage = errors.New("invalid image")
+
+// Limits bounds what an uploaded image may look like
+type Limits struct {
+	MaxBytes      int // maximum decoded size in bytes
+	MaxDimension  int // maximum width and height in pixels
+	MinDimension  int // minimum width and height in pixels
+	ThumbnailSize int // bounding box of generated thumbnails
+}
+
+// DefaultLimits are the limits applied to asset icons
+var DefaultLimits = Limits{
+	MaxBytes:      2 << 20,
+	MaxDimension:  2048,
+	MinDimension:  16,
+	ThumbnailSize: 128,
+}
+
+// Image is a validated image ready to be stored
+type Image struct {
+	Data        []byte
+	ContentType string
+	Width       int // zero for SVG
+	Height      int // zero for SVG
+}
+
+// Extension returns the file extension matching the content type
+func (img *Image) Extension() string {
+	return Extension(img.ContentType)
+}
+
+// Extension returns the file extension for a supported content type
+func Extension(contentType string) string {
+	switch contentType {
+	case ContentTypePNG:
+		return ".png"
+	case ContentTypeJPEG:
+		return ".jpg"
+	case ContentTypeWebP:
+		return ".webp"
+	case ContentTypeSVG:
+		return ".svg"
+	default:
+		return ""
+	}
+}
+
+// invalid builds an error wrapping ErrInvalidImage
+func invalid(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidImage, fmt.Sprintf(format, args...))
+}
+
+// DecodeBase64 decodes base64 image data, with or without a data URL prefix
+// such as "data:image/png;base64,"
+func DecodeBase64(encoded string, limits Limits) ([]byte, error) {
+	encoded = strings.TrimSpace(encoded)
+	if strings.HasPrefix(encoded, "data:") {
+		comma := strings.IndexByte(encoded, ',')
+		if comma < 0 || !strings.HasSuffix(encoded[:comma], ";base64") {
+			return nil, invalid("malformed data URL")
+		}
+		encoded = encoded[comma+1:]
+	}
+
+	if base64.StdEncoding.DecodedLen(len(encoded)) > limits.MaxBytes+3 {
+		return nil, invalid("image exceeds %d bytes", limits.MaxBytes)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		data, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(encoded, "="))
+		if err != nil {
+			return nil, invalid("data is not valid base64")
+		}
+	}
+	if len(data) == 0 {
+		return nil, invalid("image is empty")
+	}
+	if len(data) > limits.MaxBytes {
+		return nil, invalid("image exceeds %d bytes", limits.MaxBytes)
+	}
+	return data, nil
+}
+
+// Process validates an image and generates its thumbnail.
+// Raster images keep their original encoding and get a PNG thumbnail;
+// SVG images are sanitized and serve as their own thumbnail.
+func Process(data []byte, limits Limits) (original *Image, thumbnail *Image, err error) {
+	if len(data) > limits.MaxBytes {
+		return nil, nil, invalid("image exceeds %d bytes", limits.MaxBytes)
+	}
+
+	contentType := http.DetectContentType(data)
+	switch contentType {
+	case ContentTypePNG, ContentTypeJPEG, ContentTypeWebP:
+		return processRaster(data, contentType, limits)
+	}
+
+	if looksLikeSVG(data) {
+		sanitized, err := SanitizeSVG(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		img := &Image{Data: sanitized, ContentType: ContentTypeSVG}
+		return img, img, nil
+	}
+
+	return nil, nil, invalid("unsupported format %s, expected PNG, JPEG, WebP or SVG", contentType)
+}
+
+// processRaster validates the dimensions of a raster image and renders its thumbnail
+func processRaster(data []byte, contentType string, limits Limits) (*Image, *Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, invalid("cannot read image header: %v", err)
+	}
+	if cfg.Width > limits.MaxDimension || cfg.Height > limits.MaxDimension {
+		return nil, nil, invalid("image is %dx%d, maximum is %dx%d", cfg.Width, cfg.Height, limits.MaxDimension, limits.MaxDimension)
+	}
+	if cfg.Width < limits.MinDimension || cfg.Height < limits.MinDimension {
+		return nil, nil, invalid("image is %dx%d, minimum is %dx%d", cfg.Width, cfg.Height, limits.MinDimension, limits.MinDimension)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, invalid("cannot decode image: %v", err)
+	}
+
+	thumb, err := Thumbnail(src, limits.ThumbnailSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	original := &Image{Data: data, ContentType: contentType, Width: cfg.Width, Height: cfg.Height}
+	return original, thumb, nil
+}
+
+// Thumbnail scales src to fit within a size x size box, preserving the
+// aspect ratio, and encodes the result as PNG. Smaller images are not enlarged.
+func Thumbnail(src image.Image, size int) (*Image, error) {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w > size || h > size {
+		if w >= h {
+			w, h = size, max(1, h*size/w)
+		} else {
+			w, h = max(1, w*size/h), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, dst); err != nil {
+		return nil, fmt.Errorf("error encoding thumbnail: %w", err)
+	}
+	return &Image{Data: buf.Bytes(), ContentType: ContentTypePNG, Width: w, Height: h}, nil
+}
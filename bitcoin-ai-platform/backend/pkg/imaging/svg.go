@@ -0,0 +1,172 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+const (
+	svgNamespace   = "http://www.w3.org/2000/svg"
+	xlinkNamespace = "http://www.w3.org/1999/xlink"
+)
+
+// allowedSVGElements lists the elements kept by SanitizeSVG. Anything else,
+// notably script, style and foreignObject, is removed with its children.
+var allowedSVGElements = map[string]bool{
+	"svg": true, "g": true, "defs": true, "symbol": true, "use": true,
+	"title": true, "desc": true,
+	"path": true, "rect": true, "circle": true, "ellipse": true,
+	"line": true, "polyline": true, "polygon": true,
+	"text": true, "tspan": true,
+	"linearGradient": true, "radialGradient": true, "stop": true,
+	"clipPath": true, "mask": true, "pattern": true,
+	"filter": true, "feGaussianBlur": true, "feOffset": true, "feBlend": true,
+	"feColorMatrix": true, "feFlood": true, "feComposite": true,
+	"feMerge": true, "feMergeNode": true,
+}
+
+// looksLikeSVG reports whether data starts like an SVG document
+func looksLikeSVG(data []byte) bool {
+	head := data
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	head = bytes.TrimPrefix(head, []byte("\xef\xbb\xbf"))
+	head = bytes.TrimSpace(head)
+	return bytes.HasPrefix(head, []byte("<")) && bytes.Contains(head, []byte("<svg"))
+}
+
+// SanitizeSVG re-serializes an SVG document keeping only allow-listed
+// elements and safe attributes. Event handlers, external references,
+// comments, processing instructions and DOCTYPE declarations are dropped.
+func SanitizeSVG(data []byte) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = true
+
+	var out bytes.Buffer
+	skipDepth := 0 // > 0 while inside a removed element
+	depth := 0
+	sawRoot := false
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, invalid("malformed SVG: %v", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			if skipDepth > 0 {
+				skipDepth++
+				continue
+			}
+			if depth == 1 {
+				if t.Name.Local != "svg" || (t.Name.Space != "" && t.Name.Space != svgNamespace) {
+					return nil, invalid("SVG root element must be <svg>")
+				}
+				sawRoot = true
+			}
+			if (t.Name.Space != "" && t.Name.Space != svgNamespace) || !allowedSVGElements[t.Name.Local] {
+				skipDepth = 1
+				continue
+			}
+			writeSVGStart(&out, t, depth == 1)
+		case xml.EndElement:
+			depth--
+			if skipDepth > 0 {
+				skipDepth--
+				continue
+			}
+			out.WriteString("</" + t.Name.Local + ">")
+		case xml.CharData:
+			if skipDepth > 0 || depth == 0 {
+				continue
+			}
+			xml.EscapeText(&out, t)
+		}
+	}
+
+	if !sawRoot {
+		return nil, invalid("SVG document has no <svg> element")
+	}
+	return out.Bytes(), nil
+}
+
+// writeSVGStart writes a start tag with its safe attributes
+func writeSVGStart(out *bytes.Buffer, t xml.StartElement, root bool) {
+	out.WriteString("<" + t.Name.Local)
+	if root {
+		out.WriteString(` xmlns="` + svgNamespace + `" xmlns:xlink="` + xlinkNamespace + `"`)
+	}
+
+	for _, attr := range t.Attr {
+		name, ok := svgAttributeName(attr.Name)
+		if !ok || !safeSVGAttribute(name, attr.Value) {
+			continue
+		}
+		out.WriteString(" " + name + `="`)
+		xml.EscapeText(out, []byte(attr.Value))
+		out.WriteString(`"`)
+	}
+	out.WriteString(">")
+}
+
+// svgAttributeName maps a decoded attribute name back to its serialized form.
+// Namespace declarations and foreign namespaces are dropped.
+func svgAttributeName(name xml.Name) (string, bool) {
+	switch name.Space {
+	case "":
+		if name.Local == "xmlns" {
+			return "", false
+		}
+		return name.Local, true
+	case xlinkNamespace:
+		return "xlink:" + name.Local, true
+	default:
+		return "", false
+	}
+}
+
+// safeSVGAttribute rejects event handlers and anything able to load
+// external content or run script
+func safeSVGAttribute(name, value string) bool {
+	lowerName := strings.ToLower(name)
+	if strings.HasPrefix(lowerName, "on") {
+		return false
+	}
+
+	compact := strings.ToLower(strings.Join(strings.Fields(value), ""))
+	if strings.Contains(compact, "javascript:") || strings.Contains(compact, "vbscript:") {
+		return false
+	}
+
+	switch lowerName {
+	case "href", "xlink:href":
+		// Only references to elements within the document are allowed
+		return strings.HasPrefix(value, "#")
+	case "style":
+		if strings.Contains(compact, "@import") || strings.Contains(compact, "expression(") {
+			return false
+		}
+	}
+
+	// url() references, in presentation attributes or style, must stay local
+	for rest := compact; ; {
+		i := strings.Index(rest, "url(")
+		if i < 0 {
+			break
+		}
+		rest = strings.TrimLeft(rest[i+len("url("):], `'"`)
+		if !strings.HasPrefix(rest, "#") {
+			return false
+		}
+	}
+	return true
+}
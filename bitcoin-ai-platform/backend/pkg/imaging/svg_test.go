@@ -0,0 +1,122 @@
+package imaging
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeSVG(t *testing.T) {
+	const open = `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" viewBox="0 0 10 10">`
+
+	for _, tc := range []struct {
+		name    string
+		svg     string
+		keep    []string // substrings of the sanitized document
+		drop    []string // substrings it must not contain, case-insensitively
+		invalid bool
+	}{{
+		name: "plain shapes",
+		svg:  open + `<g fill="#f00"><circle cx="5" cy="5" r="4"/><text x="1">a &lt; b</text></g></svg>`,
+		keep: []string{`<circle cx="5" cy="5" r="4">`, `fill="#f00"`, `viewBox="0 0 10 10"`, "a &lt; b"},
+	}, {
+		name: "script",
+		svg:  open + `<script>alert(1)</script><script type="text/javascript"><![CDATA[alert(2)]]></script><rect/></svg>`,
+		keep: []string{"<rect>"},
+		drop: []string{"script", "alert"},
+	}, {
+		name: "event handlers",
+		svg:  open + `<rect onload="alert(1)" ONCLICK="alert(2)" onmouseover="alert(3)" width="1"/></svg>`,
+		keep: []string{`width="1"`},
+		drop: []string{"alert", "onload", "onclick"},
+	}, {
+		name: "foreignObject",
+		svg:  open + `<foreignObject><body xmlns="http://www.w3.org/1999/xhtml"><iframe src="https://evil.example"/></body></foreignObject><rect/></svg>`,
+		keep: []string{"<rect>"},
+		drop: []string{"foreignObject", "iframe", "evil"},
+	}, {
+		name: "style element",
+		svg:  open + `<style>@import url(https://evil.example/x.css); rect { fill: red }</style><rect/></svg>`,
+		keep: []string{"<rect>"},
+		drop: []string{"<style", "@import", "evil"},
+	}, {
+		name: "javascript href",
+		svg:  open + `<use xlink:href="javascript:alert(1)"/><use href="javascript:alert(2)"/><use xlink:href="#shape"/></svg>`,
+		keep: []string{`xlink:href="#shape"`},
+		drop: []string{"javascript", "alert"},
+	}, {
+		name: "external references",
+		svg:  open + `<use href="https://evil.example/x.svg#a"/><rect fill="url(http://evil.example/p.svg#p)" style="fill: url( 'https://evil.example' )" stroke="url(#local)"/></svg>`,
+		keep: []string{`stroke="url(#local)"`},
+		drop: []string{"evil", "url(http", "url('"},
+	}, {
+		name: "entity obfuscated javascript",
+		svg:  open + `<rect fill="jav&#x61;script:alert(1)" stroke="&#106;avascript:alert(2)" style="background:url(jav&#97;script:alert(3))"/></svg>`,
+		keep: []string{"<rect>"},
+		drop: []string{"javascript", "alert"},
+	}, {
+		name: "whitespace obfuscated javascript",
+		svg:  open + "<rect fill=\"java&#x09;script:alert(1)\" stroke=\" java\nscript : alert(2)\" mask=\"VBScript:alert(3)\"/></svg>",
+		keep: []string{"<rect>"},
+		drop: []string{"script", "alert"},
+	}, {
+		name: "doctype",
+		svg:  `<?xml version="1.0"?><!DOCTYPE svg PUBLIC "-//W3C//DTD SVG 1.1//EN" "http://www.w3.org/Graphics/SVG/1.1/DTD/svg11.dtd"><!-- comment -->` + open + `<rect/></svg>`,
+		keep: []string{"<rect>"},
+		drop: []string{"DOCTYPE", "<?xml", "comment", "dtd"},
+	}, {
+		name:    "entity declaration",
+		svg:     `<!DOCTYPE svg [<!ENTITY js "javascript:alert(1)">]>` + open + `<use href="&js;"/></svg>`,
+		invalid: true,
+	}, {
+		name:    "html root",
+		svg:     `<html><svg xmlns="http://www.w3.org/2000/svg"><rect/></svg></html>`,
+		invalid: true,
+	}, {
+		name:    "foreign svg root",
+		svg:     `<svg xmlns="http://evil.example/ns"><rect/></svg>`,
+		invalid: true,
+	}, {
+		name:    "no element",
+		svg:     `<?xml version="1.0"?><!-- nothing -->`,
+		invalid: true,
+	}, {
+		name:    "malformed",
+		svg:     open + `<rect></svg>`,
+		invalid: true,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := SanitizeSVG([]byte(tc.svg))
+			if tc.invalid {
+				if !errors.Is(err, ErrInvalidImage) {
+					t.Fatalf("SanitizeSVG = %q, %v, want ErrInvalidImage", out, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SanitizeSVG: %v", err)
+			}
+
+			got := string(out)
+			if !strings.HasPrefix(got, `<svg xmlns="http://www.w3.org/2000/svg"`) || !strings.HasSuffix(got, "</svg>") {
+				t.Errorf("sanitized document is not a single svg element:\n%s", got)
+			}
+			for _, s := range tc.keep {
+				if !strings.Contains(got, s) {
+					t.Errorf("sanitized document lost %q:\n%s", s, got)
+				}
+			}
+			for _, s := range tc.drop {
+				if strings.Contains(strings.ToLower(got), strings.ToLower(s)) {
+					t.Errorf("sanitized document kept %q:\n%s", s, got)
+				}
+			}
+
+			// Sanitizing is stable
+			again, err := SanitizeSVG(out)
+			if err != nil || string(again) != got {
+				t.Errorf("sanitizing again = %q, %v, want it unchanged", again, err)
+			}
+		})
+	}
+}
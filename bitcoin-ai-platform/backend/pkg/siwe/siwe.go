@@ -0,0 +1,260 @@
+// Package siwe parses and verifies Sign-In with Ethereum (EIP-4361) messages.
+package siwe
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/bitcoin-ai-platform/pkg/evm"
+)
+
+// Version is the only message version defined by EIP-4361
+const Version = "1"
+
+// MinNonceLength is the shortest nonce allowed by EIP-4361
+const MinNonceLength = 8
+
+// ErrInvalidMessage is returned when a message does not follow the EIP-4361 format
+var ErrInvalidMessage = errors.New("invalid SIWE message")
+
+// ErrExpired is returned when a message is used outside of its validity window
+var ErrExpired = errors.New("SIWE message is not valid at this time")
+
+// preambleSuffix ends the first line of every message
+const preambleSuffix = " wants you to sign in with your Ethereum account:"
+
+// Message is an EIP-4361 sign-in request
+type Message struct {
+	Scheme         string // optional, e.g. https
+	Domain         string // host, with port when not the default
+	Address        evm.Address
+	Statement      string // optional
+	URI            string
+	Version        string
+	ChainID        int64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+	RequestID      string
+	Resources      []string
+}
+
+// String returns the message text the wallet signs
+func (m *Message) String() string {
+	var b strings.Builder
+	if m.Scheme != "" {
+		b.WriteString(m.Scheme + "://")
+	}
+	b.WriteString(m.Domain + preambleSuffix + "\n")
+	b.WriteString(m.Address.Hex() + "\n\n")
+	if m.Statement != "" {
+		b.WriteString(m.Statement + "\n")
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, "URI: %s\n", m.URI)
+	fmt.Fprintf(&b, "Version: %s\n", m.Version)
+	fmt.Fprintf(&b, "Chain ID: %d\n", m.ChainID)
+	fmt.Fprintf(&b, "Nonce: %s\n", m.Nonce)
+	fmt.Fprintf(&b, "Issued At: %s", m.IssuedAt.UTC().Format(time.RFC3339))
+	if m.ExpirationTime != nil {
+		fmt.Fprintf(&b, "\nExpiration Time: %s", m.ExpirationTime.UTC().Format(time.RFC3339))
+	}
+	if m.NotBefore != nil {
+		fmt.Fprintf(&b, "\nNot Before: %s", m.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if m.RequestID != "" {
+		fmt.Fprintf(&b, "\nRequest ID: %s", m.RequestID)
+	}
+	if len(m.Resources) > 0 {
+		b.WriteString("\nResources:")
+		for _, r := range m.Resources {
+			b.WriteString("\n- " + r)
+		}
+	}
+	return b.String()
+}
+
+// Parse parses the text of an EIP-4361 message. The address must be in its
+// EIP-55 checksum form, as the specification requires.
+func Parse(text string) (*Message, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	p := &parser{lines: lines}
+	m := &Message{}
+
+	preamble, ok := strings.CutSuffix(p.next(), preambleSuffix)
+	if !ok || preamble == "" {
+		return nil, fmt.Errorf("%w: missing the sign-in preamble", ErrInvalidMessage)
+	}
+	if scheme, domain, found := strings.Cut(preamble, "://"); found {
+		m.Scheme, preamble = scheme, domain
+	}
+	if strings.ContainsAny(preamble, " /") {
+		return nil, fmt.Errorf("%w: invalid domain %q", ErrInvalidMessage, preamble)
+	}
+	m.Domain = preamble
+
+	address := p.next()
+	a, err := evm.ParseAddress(address)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMessage, err)
+	}
+	if a.Hex() != address {
+		return nil, fmt.Errorf("%w: address %s is not EIP-55 checksummed", ErrInvalidMessage, address)
+	}
+	m.Address = a
+
+	if p.next() != "" {
+		return nil, fmt.Errorf("%w: missing blank line after the address", ErrInvalidMessage)
+	}
+	// The statement, when present, is followed by another blank line
+	if line := p.next(); line != "" {
+		if strings.HasPrefix(line, "URI: ") {
+			return nil, fmt.Errorf("%w: missing blank line before the URI", ErrInvalidMessage)
+		}
+		m.Statement = line
+		if p.next() != "" {
+			return nil, fmt.Errorf("%w: missing blank line after the statement", ErrInvalidMessage)
+		}
+	}
+
+	if m.URI, err = p.field("URI", true); err != nil {
+		return nil, err
+	}
+	if m.Version, err = p.field("Version", true); err != nil {
+		return nil, err
+	}
+	chainID, err := p.field("Chain ID", true)
+	if err != nil {
+		return nil, err
+	}
+	if m.ChainID, err = strconv.ParseInt(chainID, 10, 64); err != nil || m.ChainID < 1 {
+		return nil, fmt.Errorf("%w: invalid chain ID %q", ErrInvalidMessage, chainID)
+	}
+	if m.Nonce, err = p.field("Nonce", true); err != nil {
+		return nil, err
+	}
+	if len(m.Nonce) < MinNonceLength || strings.ContainsFunc(m.Nonce, func(r rune) bool { return !isAlphanumeric(r) }) {
+		return nil, fmt.Errorf("%w: nonce must be at least %d alphanumeric characters", ErrInvalidMessage, MinNonceLength)
+	}
+	if m.IssuedAt, err = p.time("Issued At", true); err != nil {
+		return nil, err
+	}
+	if t, err := p.time("Expiration Time", false); err != nil {
+		return nil, err
+	} else if !t.IsZero() {
+		m.ExpirationTime = &t
+	}
+	if t, err := p.time("Not Before", false); err != nil {
+		return nil, err
+	} else if !t.IsZero() {
+		m.NotBefore = &t
+	}
+	if m.RequestID, err = p.field("Request ID", false); err != nil {
+		return nil, err
+	}
+	if p.peek() == "Resources:" {
+		p.next()
+		for p.more() {
+			resource, ok := strings.CutPrefix(p.peek(), "- ")
+			if !ok {
+				break
+			}
+			m.Resources = append(m.Resources, resource)
+			p.next()
+		}
+	}
+
+	if p.more() {
+		return nil, fmt.Errorf("%w: unexpected line %q", ErrInvalidMessage, p.peek())
+	}
+	return m, nil
+}
+
+// Valid checks the validity window of the message at now
+func (m *Message) Valid(now time.Time) error {
+	if m.ExpirationTime != nil && !now.Before(*m.ExpirationTime) {
+		return fmt.Errorf("%w: expired at %s", ErrExpired, m.ExpirationTime.Format(time.RFC3339))
+	}
+	if m.NotBefore != nil && now.Before(*m.NotBefore) {
+		return fmt.Errorf("%w: not valid before %s", ErrExpired, m.NotBefore.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Verify checks that sig is the personal_sign signature of text by the
+// address of the message it holds, and returns the parsed message
+func Verify(text string, sig []byte) (*Message, error) {
+	m, err := Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := evm.RecoverAddress(evm.TextHash([]byte(text)), sig)
+	if err != nil {
+		return nil, err
+	}
+	if signer != m.Address {
+		return nil, fmt.Errorf("%w: signed by %s, not %s", evm.ErrInvalidSignature, signer.Hex(), m.Address.Hex())
+	}
+	return m, nil
+}
+
+// parser walks the lines of a message
+type parser struct {
+	lines []string
+	pos   int
+}
+
+func (p *parser) more() bool {
+	return p.pos < len(p.lines)
+}
+
+func (p *parser) peek() string {
+	if !p.more() {
+		return ""
+	}
+	return p.lines[p.pos]
+}
+
+func (p *parser) next() string {
+	line := p.peek()
+	p.pos++
+	return line
+}
+
+// field consumes the "name: value" line, which may be absent unless required
+func (p *parser) field(name string, required bool) (string, error) {
+	value, ok := strings.CutPrefix(p.peek(), name+": ")
+	if !ok {
+		if required {
+			return "", fmt.Errorf("%w: missing %s", ErrInvalidMessage, name)
+		}
+		return "", nil
+	}
+	p.next()
+	if value == "" {
+		return "", fmt.Errorf("%w: empty %s", ErrInvalidMessage, name)
+	}
+	return value, nil
+}
+
+// time consumes an RFC 3339 timestamp field
+func (p *parser) time(name string, required bool) (time.Time, error) {
+	value, err := p.field(name, required)
+	if err != nil || value == "" {
+		return time.Time{}, err
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s is not an RFC 3339 timestamp", ErrInvalidMessage, name)
+	}
+	return t, nil
+}
+
+func isAlphanumeric(r rune) bool {
+	return r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
@@ -0,0 +1,83 @@
+package siwe
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/bitcoin-ai-platform/pkg/evm"
+)
+
+// specMessage is the example message of EIP-4361
+const specMessage = `service.invalid wants you to sign in with your Ethereum account:
+0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2
+
+I accept the ServiceOrg Terms of Service: https://service.invalid/tos
+
+URI: https://service.invalid/login
+Version: 1
+Chain ID: 1
+Nonce: 32891756
+Issued At: 2021-09-30T16:25:24Z
+Resources:
+- ipfs://bafybeiemxf5abjwjbikoz4mc3a3dla6ual3jsgpdr4cjr3oz3evfyavhwq/
+- https://example.com/my-web2-claim.json`
+
+func TestParse(t *testing.T) {
+	m, err := Parse(specMessage)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if m.Domain != "service.invalid" || m.Address.Hex() != "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2" || m.ChainID != 1 || m.Nonce != "32891756" || len(m.Resources) != 2 {
+		t.Errorf("parsed = %+v", m)
+	}
+	if !m.IssuedAt.Equal(time.Date(2021, 9, 30, 16, 25, 24, 0, time.UTC)) {
+		t.Errorf("issued at = %s", m.IssuedAt)
+	}
+	if got := m.String(); got != specMessage {
+		t.Errorf("String() =\n%s\nwant\n%s", got, specMessage)
+	}
+
+	// Without a statement two blank lines separate the address and the URI
+	m.Statement = ""
+	m.Scheme = "https"
+	expires := m.IssuedAt.Add(time.Hour)
+	m.ExpirationTime = &expires
+	again, err := Parse(m.String())
+	if err != nil {
+		t.Fatalf("Parse without statement: %v\n%s", err, m.String())
+	}
+	if again.Scheme != "https" || again.Statement != "" || !again.ExpirationTime.Equal(expires) {
+		t.Errorf("reparsed = %+v", again)
+	}
+	if err := again.Valid(expires); !errors.Is(err, ErrExpired) {
+		t.Errorf("Valid at expiry = %v, want ErrExpired", err)
+	}
+
+	for name, text := range map[string]string{
+		"lower case address": strings.Replace(specMessage, "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2", "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", 1),
+		"short nonce":        strings.Replace(specMessage, "Nonce: 32891756", "Nonce: 123", 1),
+		"missing version":    strings.Replace(specMessage, "Version: 1\n", "", 1),
+		"trailing line":      specMessage + "\nextra",
+	} {
+		if _, err := Parse(text); !errors.Is(err, ErrInvalidMessage) {
+			t.Errorf("Parse with %s = %v, want ErrInvalidMessage", name, err)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	key, _ := evm.ParsePrivateKey("0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	m, _ := Parse(specMessage)
+	m.Address = key.Address()
+	text := m.String()
+
+	if _, err := Verify(text, key.Sign(evm.TextHash([]byte(text)))); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+	tampered := strings.Replace(text, "Chain ID: 1", "Chain ID: 2", 1)
+	if _, err := Verify(tampered, key.Sign(evm.TextHash([]byte(text)))); !errors.Is(err, evm.ErrInvalidSignature) {
+		t.Errorf("Verify of a tampered message = %v, want ErrInvalidSignature", err)
+	}
+}
@@ -0,0 +1,243 @@
+// Package textdiff compares texts line by line and formats the changes as
+// unified diffs.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op is what happens to a line going from one text to the other
+type Op byte
+
+// Line operations, printed as the prefix of the line in a unified diff
+const (
+	Equal  Op = ' '
+	Delete Op = '-'
+	Insert Op = '+'
+)
+
+// MaxEdits bounds the search for the shortest edit script. Texts further
+// apart are diffed as the removal of every differing line followed by the
+// insertion of the new ones, which is correct if not minimal.
+const MaxEdits = 1000
+
+// Line is one line of a diff, without its line ending
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Lines returns the shortest sequence of line operations turning a into b
+func Lines(a, b string) []Line {
+	x, y := split(a), split(b)
+
+	// Common ends need no search
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]Line, 0, len(x)+len(y)-prefix-suffix)
+	for _, text := range x[:prefix] {
+		lines = append(lines, Line{Equal, text})
+	}
+	lines = append(lines, middle(x[prefix:len(x)-suffix], y[prefix:len(y)-suffix])...)
+	for _, text := range x[len(x)-suffix:] {
+		lines = append(lines, Line{Equal, text})
+	}
+	return lines
+}
+
+// split breaks text into lines, a final line ending not starting another
+func split(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// middle diffs x and y with Myers' algorithm, recording for each edit
+// distance d the furthest x reached on each diagonal k = x - y
+func middle(x, y []string) []Line {
+	n, m := len(x), len(y)
+	if n == 0 || m == 0 {
+		return replace(x, y)
+	}
+
+	limit := min(n+m, MaxEdits)
+	offset := limit + 1
+	v := make([]int, 2*offset+1)
+	// trace[d] holds v before edit d, for diagonals -d-1 to d+1
+	var trace [][]int
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var i int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				i = v[offset+k+1] // down: insert y[j-1]
+			} else {
+				i = v[offset+k-1] + 1 // right: delete x[i-1]
+			}
+			j := i - k
+			for i < n && j < m && x[i] == y[j] {
+				i++
+				j++
+			}
+			v[offset+k] = i
+			if i >= n && j >= m {
+				return backtrack(x, y, trace)
+			}
+		}
+	}
+	return replace(x, y)
+}
+
+// backtrack walks trace back from the end of both texts to the start
+func backtrack(x, y []string, trace [][]int) []Line {
+	var reversed []Line
+	i, j := len(x), len(y)
+	for d := len(trace) - 1; d >= 0; d-- {
+		at := func(k int) int { return trace[d][k+d+1] }
+		k := i - j
+		prev := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prev = k + 1
+		}
+		pi := at(prev)
+		pj := pi - prev
+		for i > pi && j > pj {
+			i--
+			j--
+			reversed = append(reversed, Line{Equal, x[i]})
+		}
+		if d == 0 {
+			break
+		}
+		if i == pi {
+			j--
+			reversed = append(reversed, Line{Insert, y[j]})
+		} else {
+			i--
+			reversed = append(reversed, Line{Delete, x[i]})
+		}
+	}
+
+	lines := make([]Line, len(reversed))
+	for n, line := range reversed {
+		lines[len(reversed)-1-n] = line
+	}
+	return lines
+}
+
+// replace deletes every line of x and inserts every line of y
+func replace(x, y []string) []Line {
+	lines := make([]Line, 0, len(x)+len(y))
+	for _, text := range x {
+		lines = append(lines, Line{Delete, text})
+	}
+	for _, text := range y {
+		lines = append(lines, Line{Insert, text})
+	}
+	return lines
+}
+
+// Stats counts the inserted and deleted lines of a diff
+func Stats(lines []Line) (insertions, deletions int) {
+	for _, line := range lines {
+		switch line.Op {
+		case Insert:
+			insertions++
+		case Delete:
+			deletions++
+		}
+	}
+	return insertions, deletions
+}
+
+// Unified formats lines as a unified diff from fromName to toName, with up
+// to context unchanged lines around each change. It is empty when nothing
+// changed.
+func Unified(fromName, toName string, lines []Line, context int) string {
+	var b strings.Builder
+	// aLine and bLine are the 1-based numbers of lines[i] in each text
+	aLine, bLine := 1, 1
+	for i := 0; i < len(lines); {
+		if lines[i].Op == Equal {
+			aLine, bLine = aLine+1, bLine+1
+			i++
+			continue
+		}
+
+		// A hunk runs until more than 2*context unchanged lines follow a change
+		start := max(i-context, 0)
+		end := i
+		for equal := 0; end < len(lines) && equal <= 2*context; end++ {
+			if lines[end].Op == Equal {
+				equal++
+			} else {
+				equal = 0
+			}
+		}
+		for end > i && lines[end-1].Op == Equal && trailing(lines[i:end]) > context {
+			end--
+		}
+
+		aStart, bStart := aLine-(i-start), bLine-(i-start)
+		var aCount, bCount int
+		var body strings.Builder
+		for _, line := range lines[start:end] {
+			if line.Op != Insert {
+				aCount++
+			}
+			if line.Op != Delete {
+				bCount++
+			}
+			body.WriteByte(byte(line.Op))
+			body.WriteString(line.Text)
+			body.WriteByte('\n')
+		}
+
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(aStart, aCount), hunkRange(bStart, bCount))
+		b.WriteString(body.String())
+
+		for _, line := range lines[i:end] {
+			if line.Op != Insert {
+				aLine++
+			}
+			if line.Op != Delete {
+				bLine++
+			}
+		}
+		i = end
+	}
+	return b.String()
+}
+
+// trailing counts the unchanged lines ending lines
+func trailing(lines []Line) int {
+	n := 0
+	for n < len(lines) && lines[len(lines)-1-n].Op == Equal {
+		n++
+	}
+	return n
+}
+
+// hunkRange formats the start and length of a hunk in one text, where an
+// empty range starts at the line before it
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
@@ -0,0 +1,106 @@
+package textdiff
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// apply rebuilds both texts from a diff
+func apply(lines []Line) (a, b []string) {
+	for _, line := range lines {
+		if line.Op != Insert {
+			a = append(a, line.Text)
+		}
+		if line.Op != Delete {
+			b = append(b, line.Text)
+		}
+	}
+	return a, b
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		edits int
+	}{
+		{"", "", 0},
+		{"a\nb\nc\n", "a\nb\nc\n", 0},
+		{"", "a\nb\n", 2},
+		{"a\nb\n", "", 2},
+		{"a\nb\nc\n", "a\nx\nc\n", 2},
+		{"a\nb\nc\na\nb\nb\na\n", "c\nb\na\nb\na\nc\n", 5}, // the example of Myers' paper
+		{"# Title\n\nIntro\n", "# Title\n\nIntro\n\n## Roadmap\n", 2},
+		{"one\ntwo", "one\ntwo\n", 0},
+	}
+	for _, tt := range tests {
+		lines := Lines(tt.a, tt.b)
+		a, b := apply(lines)
+		if strings.Join(a, "\n") != strings.Join(split(tt.a), "\n") || strings.Join(b, "\n") != strings.Join(split(tt.b), "\n") {
+			t.Errorf("Lines(%q, %q) = %v does not rebuild both texts", tt.a, tt.b, lines)
+		}
+		if ins, del := Stats(lines); ins+del != tt.edits {
+			t.Errorf("Lines(%q, %q) has %d edits, want %d", tt.a, tt.b, ins+del, tt.edits)
+		}
+	}
+}
+
+func TestLinesRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	text := func() string {
+		lines := make([]string, rng.Intn(40))
+		for i := range lines {
+			lines[i] = fmt.Sprint(rng.Intn(5))
+		}
+		return strings.Join(lines, "\n")
+	}
+	for n := 0; n < 500; n++ {
+		x, y := text(), text()
+		a, b := apply(Lines(x, y))
+		if strings.Join(a, "\n") != x || strings.Join(b, "\n") != y {
+			t.Fatalf("Lines(%q, %q) does not rebuild both texts", x, y)
+		}
+	}
+}
+
+func TestLinesBeyondMaxEdits(t *testing.T) {
+	var x, y []string
+	for i := 0; i < MaxEdits; i++ {
+		x = append(x, fmt.Sprint("old ", i))
+		y = append(y, fmt.Sprint("new ", i))
+	}
+	lines := Lines(strings.Join(x, "\n"), strings.Join(y, "\n"))
+	if ins, del := Stats(lines); ins != MaxEdits || del != MaxEdits {
+		t.Errorf("stats = +%d -%d, want every line replaced", ins, del)
+	}
+}
+
+func TestUnified(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+	want := `--- v1
++++ v2
+@@ -1,5 +1,5 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+@@ -11,2 +11,3 @@
+ 11
+ 12
++13
+`
+	if got := Unified("v1", "v2", Lines(a, b), 2); got != want {
+		t.Errorf("Unified =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := Unified("v1", "v2", Lines("", "first\n"), 3); got != "--- v1\n+++ v2\n@@ -0,0 +1 @@\n+first\n" {
+		t.Errorf("Unified from empty =\n%s", got)
+	}
+	if got := Unified("v1", "v2", Lines(a, a), 3); got != "" {
+		t.Errorf("Unified of equal texts = %q, want empty", got)
+	}
+}
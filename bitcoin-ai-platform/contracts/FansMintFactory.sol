@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+/// @title FansMint fan token
+/// @notice Minimal ERC-20 whose whole supply is minted to the owner at creation
+contract FansMintToken {
+    string public name;
+    string public symbol;
+    uint8 public immutable decimals;
+    uint256 public totalSupply;
+    string public metadataURI;
+    address public immutable owner;
+
+    mapping(address => uint256) public balanceOf;
+    mapping(address => mapping(address => uint256)) public allowance;
+
+    event Transfer(address indexed from, address indexed to, uint256 value);
+    event Approval(address indexed owner, address indexed spender, uint256 value);
+
+    constructor(
+        string memory name_,
+        string memory symbol_,
+        uint8 decimals_,
+        uint256 initialSupply,
+        address owner_,
+        string memory metadataURI_
+    ) {
+        name = name_;
+        symbol = symbol_;
+        decimals = decimals_;
+        owner = owner_;
+        metadataURI = metadataURI_;
+        totalSupply = initialSupply;
+        balanceOf[owner_] = initialSupply;
+        emit Transfer(address(0), owner_, initialSupply);
+    }
+
+    function transfer(address to, uint256 value) external returns (bool) {
+        _transfer(msg.sender, to, value);
+        return true;
+    }
+
+    function approve(address spender, uint256 value) external returns (bool) {
+        allowance[msg.sender][spender] = value;
+        emit Approval(msg.sender, spender, value);
+        return true;
+    }
+
+    function transferFrom(address from, address to, uint256 value) external returns (bool) {
+        uint256 allowed = allowance[from][msg.sender];
+        if (allowed != type(uint256).max) {
+            require(allowed >= value, "allowance exceeded");
+            allowance[from][msg.sender] = allowed - value;
+        }
+        _transfer(from, to, value);
+        return true;
+    }
+
+    function _transfer(address from, address to, uint256 value) private {
+        require(to != address(0), "transfer to the zero address");
+        require(balanceOf[from] >= value, "balance exceeded");
+        unchecked {
+            balanceOf[from] -= value;
+        }
+        balanceOf[to] += value;
+        emit Transfer(from, to, value);
+    }
+}
+
+/// @title FansMint token factory
+/// @notice Deploys one FansMintToken per fan token created through FansMint.
+/// The backend calls createToken and reads the token address from
+/// TokenCreated (see pkg/evm/factory.go).
+contract FansMintFactory {
+    event TokenCreated(address indexed token, address indexed owner);
+
+    function createToken(
+        string calldata name,
+        string calldata symbol,
+        uint8 decimals,
+        uint256 initialSupply,
+        address owner,
+        string calldata metadataURI
+    ) external returns (address token) {
+        require(owner != address(0), "owner is the zero address");
+        token = address(new FansMintToken(name, symbol, decimals, initialSupply, owner, metadataURI));
+        emit TokenCreated(token, owner);
+    }
+}
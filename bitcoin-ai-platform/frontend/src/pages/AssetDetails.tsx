@@ -39,20 +39,28 @@ interface Holder {
 }
 
 interface Transaction {
-  txId: string;
+  blockNumber: number;
+  timestamp?: string;
   from: string;
   to: string;
   amount: string;
-  timestamp: string;
-  type: 'in' | 'out';
+  amountFormatted: string;
+  txHash: string;
+  logIndex: number;
+  direction?: 'in' | 'out';
+  explorerUrl: string;
 }
 
+const ZERO_ADDRESS = '0x0000000000000000000000000000000000000000';
+
 const AssetDetails: React.FC = () => {
   const { id } = useParams<{ id: string }>();
   const location = useLocation();
   const [asset, setAsset] = useState<any>(null);
   const [loading, setLoading] = useState(true);
   const [activeTab, setActiveTab] = useState('1');
+  const [transactions, setTransactions] = useState<Transaction[]>([]);
+  const [transactionsLoading, setTransactionsLoading] = useState(false);
 
   // 新增：从location.state获取前面创建资产时传递过来的数据
   const assetData = location.state || {};
@@ -66,13 +74,6 @@ const AssetDetails: React.FC = () => {
     { address: '0xabc123...', balance: '200,000', percentage: 20 }
   ];
 
-  // Mock transactions data
-  const transactions: Transaction[] = [
-    { txId: 'tx_12345', from: '0x742d35Cc6634C0532925a3b844Bc454e4438f44e', to: '0x5aeda56215b167893e80b4fe645ba6d5bab767de', amount: '10,000', timestamp: '2023-05-19T12:00:00Z', type: 'out' },
-    { txId: 'tx_12346', from: '0x5aeda56215b167893e80b4fe645ba6d5bab767de', to: '0x742d35Cc6634C0532925a3b844Bc454e4438f44e', amount: '5,000', timestamp: '2023-05-18T10:30:00Z', type: 'in' },
-    { txId: 'tx_12347', from: '0x6796cc07bac28e08818ed0e6d3bab143b6e74c02', to: '0x742d35Cc6634C0532925a3b844Bc454e4438f44e', amount: '2,500', timestamp: '2023-05-17T15:45:00Z', type: 'in' },
-    { txId: 'tx_12348', from: '0x742d35Cc6634C0532925a3b844Bc454e4438f44e', to: '0x28f35ca8d308bea4b325a9c34b58d6a72876468d', amount: '7,500', timestamp: '2023-05-16T08:20:00Z', type: 'out' }
-  ];
 
   const holderColumns = [
     {
@@ -101,25 +102,31 @@ const AssetDetails: React.FC = () => {
 
   const transactionColumns = [
     {
-      title: 'Transaction ID',
-      dataIndex: 'txId',
-      key: 'txId',
-      render: (txId: string) => (
+      title: 'Transaction Hash',
+      dataIndex: 'txHash',
+      key: 'txHash',
+      render: (txHash: string, tx: Transaction) => (
         <Space>
-          <Text ellipsis style={{ maxWidth: 100 }}>{txId}</Text>
-          <LinkOutlined style={{ cursor: 'pointer', color: '#1890ff' }} onClick={() => window.open(`https://blockstream.info/tx/${txId}`, '_blank')} />
+          <Text ellipsis style={{ maxWidth: 100 }}>{txHash}</Text>
+          <LinkOutlined style={{ cursor: 'pointer', color: '#1890ff' }} onClick={() => window.open(tx.explorerUrl, '_blank')} />
         </Space>
       ),
     },
     {
       title: 'Type',
-      dataIndex: 'type',
       key: 'type',
-      render: (type: string) => (
-        type === 'in' 
-          ? <Tag color="green" icon={<ArrowDownOutlined />}>Received</Tag> 
-          : <Tag color="volcano" icon={<ArrowUpOutlined />}>Sent</Tag>
-      )
+      render: (_: unknown, tx: Transaction) => {
+        if (tx.from === ZERO_ADDRESS) return <Tag color="blue">Mint</Tag>;
+        if (tx.to === ZERO_ADDRESS) return <Tag color="default">Burn</Tag>;
+        if (tx.direction === 'in') return <Tag color="green" icon={<ArrowDownOutlined />}>Received</Tag>;
+        if (tx.direction === 'out') return <Tag color="volcano" icon={<ArrowUpOutlined />}>Sent</Tag>;
+        return <Tag icon={<SwapOutlined />}>Transfer</Tag>;
+      }
+    },
+    {
+      title: 'Block',
+      dataIndex: 'blockNumber',
+      key: 'blockNumber',
     },
     {
       title: 'From',
@@ -135,14 +142,14 @@ const AssetDetails: React.FC = () => {
     },
     {
       title: 'Amount',
-      dataIndex: 'amount',
+      dataIndex: 'amountFormatted',
       key: 'amount',
     },
     {
       title: 'Timestamp',
       dataIndex: 'timestamp',
       key: 'timestamp',
-      render: (timestamp: string) => new Date(timestamp).toLocaleString(),
+      render: (timestamp?: string) => (timestamp ? new Date(timestamp).toLocaleString() : '-'),
     },
   ];
 
@@ -207,6 +214,24 @@ const AssetDetails: React.FC = () => {
     fetchAssetDetails();
   }, [id, assetData]);
 
+  useEffect(() => {
+    if (!id) return;
+    const fetchTransactions = async () => {
+      setTransactionsLoading(true);
+      try {
+        const response = await assetApi.getTransactions(id, { limit: 50 });
+        setTransactions(response.data.transactions || []);
+      } catch (error) {
+        console.error('Error fetching transactions:', error);
+        setTransactions([]);
+      } finally {
+        setTransactionsLoading(false);
+      }
+    };
+
+    fetchTransactions();
+  }, [id]);
+
   // Chart options
   const getChartOptions = () => {
     return {
@@ -408,7 +433,8 @@ const AssetDetails: React.FC = () => {
           <Card>
             <Table
               columns={transactionColumns}
-              dataSource={transactions.map((tx, index) => ({ ...tx, key: index }))}
+              dataSource={transactions.map((tx) => ({ ...tx, key: `${tx.txHash}:${tx.logIndex}` }))}
+              loading={transactionsLoading}
               pagination={{ pageSize: 10 }}
             />
           </Card>
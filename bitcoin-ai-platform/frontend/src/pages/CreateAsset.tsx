@@ -1,4 +1,4 @@
-import React, { useState } from 'react';
+import React, { useEffect, useState } from 'react';
 import { 
   Typography, 
   Form, 
@@ -24,7 +24,7 @@ import {
   UploadOutlined,
   PictureOutlined
 } from '@ant-design/icons';
-import { assetApi, aiApi } from '../services/api';
+import { assetApi, aiApi, draftApi } from '../services/api';
 import { useNavigate } from 'react-router-dom';
 
 const { Title, Paragraph, Text } = Typography;
@@ -34,13 +34,23 @@ const { TextArea } = Input;
 interface AssetFormData {
   name: string;
   symbol: string;
-  totalSupply: number;
+  totalSupply: string; // whole tokens, kept a string so large supplies stay exact
   description: string;
   useCase: string;
   tokenType: string;
   icon?: string; // base64 encoded image
 }
 
+// Local storage key of the draft being edited
+const DRAFT_KEY = 'fansmint.draftId';
+
+// HTTP status of a failed API call, undefined when the server did not answer
+const statusOf = (error: any): number | undefined => error?.response?.status;
+
+// Group the digits of a whole number string in thousands without going
+// through floating point
+const groupDigits = (value: string) => value.replace(/\B(?=(\d{3})+(?!\d))/g, ',');
+
 const CreateAsset: React.FC = () => {
   const [form] = Form.useForm();
   const navigate = useNavigate();
@@ -50,6 +60,92 @@ const CreateAsset: React.FC = () => {
   const [whitepaper, setWhitepaper] = useState<string>('');
   const [createdAsset, setCreatedAsset] = useState<any>(null);
   const [iconPreview, setIconPreview] = useState<string | null>(null);
+  const [draftId, setDraftId] = useState<string | null>(localStorage.getItem(DRAFT_KEY));
+  const [draftError, setDraftError] = useState<string | null>(null);
+
+  // Resume the draft left by a reload
+  useEffect(() => {
+    if (!draftId) return;
+    draftApi.getDraft(draftId)
+      .then(response => {
+        const draft = response.data.draft;
+        if (draft.assetId) {
+          forgetDraft();
+          return;
+        }
+        form.setFieldsValue({
+          useCase: draft.useCase,
+          name: draft.name,
+          symbol: draft.symbol,
+          description: draft.description,
+          icon: draft.iconData || undefined,
+          ...(draft.totalSupply ? { totalSupply: draft.totalSupply } : {}),
+        });
+        setAiSuggestion(draft.suggestion);
+        setWhitepaper(draft.whitepaper);
+        setIconPreview(draft.iconData || null);
+        setCurrentStep(Math.min(draft.step, 2));
+      })
+      .catch(error => {
+        if (statusOf(error) === 401) {
+          setDraftError('Sign in with your wallet to resume your saved draft.');
+          return;
+        }
+        forgetDraft();
+      });
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, []);
+
+  // The whitepaper arrives after its step completes
+  useEffect(() => {
+    if (!draftId || !whitepaper) return;
+    draftApi.updateDraft(draftId, { whitepaper })
+      .then(() => setDraftError(null))
+      .catch(draftSaveFailed);
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [whitepaper]);
+
+  const forgetDraft = () => {
+    localStorage.removeItem(DRAFT_KEY);
+    setDraftId(null);
+  };
+
+  // Tell the user their progress is not being saved, rather than losing it
+  // quietly on the next reload
+  const draftSaveFailed = (error: any) => {
+    console.warn('Failed to save draft:', error);
+    setDraftError(statusOf(error) === 401
+      ? 'Sign in with your wallet to save this draft. Until then your progress is kept only in this page.'
+      : 'Failed to save your draft. Your progress is kept only in this page.');
+  };
+
+  // Save the wizard so far; needs a signed-in wallet, otherwise progress
+  // stays in this page and draftError says so
+  const saveDraft = async (step: number, extra: any = {}) => {
+    const values = form.getFieldsValue(true);
+    const fields = {
+      step,
+      useCase: values.useCase,
+      name: values.name,
+      symbol: values.symbol,
+      description: values.description,
+      iconData: values.icon,
+      ...(values.totalSupply ? { totalSupply: values.totalSupply } : {}),
+      ...extra,
+    };
+    try {
+      if (draftId) {
+        await draftApi.updateDraft(draftId, fields);
+      } else {
+        const response = await draftApi.createDraft(fields);
+        localStorage.setItem(DRAFT_KEY, response.data.draftId);
+        setDraftId(response.data.draftId);
+      }
+      setDraftError(null);
+    } catch (error) {
+      draftSaveFailed(error);
+    }
+  };
 
   // Get AI suggestions
   const getAiSuggestions = async (values: any) => {
@@ -96,7 +192,7 @@ ${values.description}
 ${values.name} is built on the exSat protocol, inheriting Bitcoin's security and decentralization features.
 
 ## 3. Tokenomics
-- Total Supply: ${values.totalSupply.toLocaleString()}
+- Total Supply: ${groupDigits(values.totalSupply)}
 - Token Type: ${values.tokenType}
 - Distribution Plan: 
   * Team: 15%
@@ -162,6 +258,7 @@ ${values.name} will provide innovative solutions for ${values.useCase}, and with
       case 0:
         await getAiSuggestions(values);
         setCurrentStep(1);
+        saveDraft(1);
         break;
       case 1:
         const combinedValues = { 
@@ -171,10 +268,12 @@ ${values.name} will provide innovative solutions for ${values.useCase}, and with
         form.setFieldsValue(combinedValues);
         await generateWhitepaper(combinedValues);
         setCurrentStep(2);
+        saveDraft(2, { suggestion: aiSuggestion && form.getFieldValue('useAiSuggestion') ? aiSuggestion : undefined });
         break;
       case 2:
         await createAsset(values);
         setCurrentStep(3);
+        forgetDraft();
         break;
       case 3:
         navigate(`/`);
@@ -326,7 +425,7 @@ ${values.name} will provide innovative solutions for ${values.useCase}, and with
               <TextArea rows={4} placeholder="Detailed description of your asset features and uses" />
             </Form.Item>
             <Form.Item name="totalSupply" label="Total Supply" rules={[{ required: true, message: 'Please enter total supply' }]}>
-              <InputNumber style={{ width: '100%' }} min={1} placeholder="e.g., 21000000" />
+              <InputNumber style={{ width: '100%' }} min={1} precision={0} stringMode placeholder="e.g., 21000000" />
             </Form.Item>
             <Form.Item name="tokenType" label="Token Type" rules={[{ required: true, message: 'Please select token type' }]}>
               <Select placeholder="Select token type">
@@ -471,6 +570,17 @@ ${values.name} will provide innovative solutions for ${values.useCase}, and with
         style={{ marginBottom: 32 }}
       />
 
+      {draftError && (
+        <Alert
+          message={draftError}
+          type="warning"
+          showIcon
+          closable
+          onClose={() => setDraftError(null)}
+          style={{ marginBottom: 24 }}
+        />
+      )}
+
       <Spin spinning={loading && currentStep !== 2}>
         <Form
           form={form}
@@ -479,7 +589,7 @@ ${values.name} will provide innovative solutions for ${values.useCase}, and with
           initialValues={{
             name: '',
             symbol: '',
-            totalSupply: 1000000,
+            totalSupply: '1000000',
             description: '',
             useCase: '',
             tokenType: 'utility'
@@ -9,6 +9,38 @@ const api = axios.create({
   },
 });
 
+// Session token from Sign-In with Ethereum, sent as a bearer token
+const SESSION_KEY = 'fansmint.session';
+
+export const setSessionToken = (token: string | null) => {
+  if (token) {
+    localStorage.setItem(SESSION_KEY, token);
+  } else {
+    localStorage.removeItem(SESSION_KEY);
+  }
+};
+
+api.interceptors.request.use((config) => {
+  const token = localStorage.getItem(SESSION_KEY);
+  if (token) {
+    config.headers = config.headers || {};
+    config.headers.Authorization = `Bearer ${token}`;
+  }
+  return config;
+});
+
+// Sign-In with Ethereum (EIP-4361)
+export const authApi = {
+  // Get a single-use nonce, with the domain and chain ID to sign for
+  getNonce: () => api.get('/auth/nonce'),
+
+  // Exchange the signed message for a session token
+  verify: (message: string, signature: string) => api.post('/auth/verify', { message, signature }),
+
+  // Get the signed-in wallet
+  getSession: () => api.get('/auth/session'),
+};
+
 // Asset related API
 export const assetApi = {
   // Get asset list
@@ -19,6 +51,63 @@ export const assetApi = {
   
   // Create new asset
   createAsset: (assetData: any) => api.post('/assets/create', assetData),
+
+  // Check whether a symbol is free for a new asset
+  checkSymbol: (symbol: string) => api.get(`/assets/symbols/${encodeURIComponent(symbol)}/availability`),
+
+  // Get indexed token transfers, newest first; pass sinceBlock to poll for new ones
+  getTransactions: (id: string, params?: { address?: string; direction?: 'in' | 'out'; sinceBlock?: number; limit?: number; cursor?: string }) =>
+    api.get(`/assets/${id}/transactions`, { params }),
+
+  // Get the status changes of an asset, oldest first
+  getHistory: (id: string) => api.get(`/assets/${id}/history`),
+
+  // Owner status changes, each with an optional reason
+  pauseAsset: (id: string, reason?: string) => api.post(`/assets/${id}/pause`, { reason }),
+  resumeAsset: (id: string, reason?: string) => api.post(`/assets/${id}/resume`, { reason }),
+  retireAsset: (id: string, reason?: string) => api.post(`/assets/${id}/retire`, { reason }),
+
+  // Get the latest whitepaper version, or the given one
+  getWhitepaper: (id: string, version?: number) => api.get(`/assets/${id}/whitepaper`, { params: { version } }),
+
+  // Save and publish a new whitepaper version; owner only
+  publishWhitepaper: (id: string, content: string) => api.put(`/assets/${id}/whitepaper`, { content }),
+
+  // Unified diff between two whitepaper versions, by default the last two
+  diffWhitepaper: (id: string, from?: number, to?: number) => api.get(`/assets/${id}/whitepaper/diff`, { params: { from, to } }),
+};
+
+// Asset creation drafts of the signed-in wallet
+export const draftApi = {
+  // List drafts, most recently updated first, without their icons
+  getDrafts: () => api.get('/drafts'),
+
+  // Get one draft
+  getDraft: (id: string) => api.get(`/drafts/${id}`),
+
+  // Start a draft with any of its fields
+  createDraft: (fields: any) => api.post('/drafts', fields),
+
+  // Change only the fields given
+  updateDraft: (id: string, fields: any) => api.patch(`/drafts/${id}`, fields),
+
+  // Discard a draft
+  deleteDraft: (id: string) => api.delete(`/drafts/${id}`),
+
+  // Create the asset a draft describes
+  publishDraft: (id: string) => api.post(`/drafts/${id}/publish`),
+
+  // Get a version of the whitepapers saved in a draft, by default the latest
+  getWhitepaper: (id: string, version?: number) => api.get(`/drafts/${id}/whitepaper`, { params: { version } }),
+
+  // Unified diff between two whitepaper versions of a draft
+  diffWhitepaper: (id: string, from?: number, to?: number) => api.get(`/drafts/${id}/whitepaper/diff`, { params: { from, to } }),
+};
+
+// Wallet related API
+export const walletApi = {
+  // Get the FansMint tokens a wallet holds and created, from indexed chain data
+  getPortfolio: (address: string) => api.get(`/wallets/${encodeURIComponent(address)}/portfolio`),
 };
 
 // AI related API